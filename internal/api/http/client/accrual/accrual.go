@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/arvaliullin/gophermart/internal/core/ports"
 	"github.com/arvaliullin/gophermart/internal/core/services/accrual"
@@ -12,7 +13,34 @@ import (
 )
 
 // GetOrderAccrual запрашивает информацию о начислениях по заказу.
+// Запросы всех вызывающих проходят через общий ограничитель частоты,
+// который подстраивается под лимит из ответа 429 Too Many Requests.
+// Если лимит исчерпан, запрос дожидается разрешения.
 func (c *Client) GetOrderAccrual(ctx context.Context, orderNumber string) (*ports.AccrualResponse, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.getOrderAccrual(ctx, orderNumber)
+}
+
+// TryGetOrderAccrual запрашивает информацию о начислениях по заказу без
+// ожидания ограничителя частоты: если лимит исчерпан, сразу возвращает
+// *accrual.RetryAfterError со временем до следующего разрешения.
+func (c *Client) TryGetOrderAccrual(ctx context.Context, orderNumber string) (*ports.AccrualResponse, error) {
+	if ok, delay := c.limiter.Allow(); !ok {
+		return nil, &accrual.RetryAfterError{Duration: delay}
+	}
+	return c.getOrderAccrual(ctx, orderNumber)
+}
+
+// PausedFor возвращает оставшееся время паузы после ответа 429 Too Many Requests.
+func (c *Client) PausedFor() time.Duration {
+	return c.limiter.PausedFor()
+}
+
+// getOrderAccrual выполняет запрос, на который уже получено разрешение
+// ограничителя частоты.
+func (c *Client) getOrderAccrual(ctx context.Context, orderNumber string) (*ports.AccrualResponse, error) {
 	requestURL, err := url.JoinPath(c.baseURL, "api", "orders", orderNumber)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrBuildURL, err)
@@ -46,6 +74,10 @@ func (c *Client) GetOrderAccrual(ctx context.Context, orderNumber string) (*port
 	case http.StatusTooManyRequests:
 		retryAfter := resp.Header().Get("Retry-After")
 		duration := parseRetryAfter(retryAfter)
+		if limit, ok := parseRequestsPerMinute(resp.String()); ok {
+			c.limiter.SetRate(float64(limit) / 60)
+		}
+		c.limiter.Pause(duration)
 		return nil, &accrual.RetryAfterError{Duration: duration}
 
 	default:
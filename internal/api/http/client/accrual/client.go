@@ -3,6 +3,7 @@ package accrual
 import (
 	"time"
 
+	"github.com/arvaliullin/gophermart/internal/pkg/ratelimit"
 	"github.com/go-resty/resty/v2"
 )
 
@@ -15,6 +16,7 @@ type HTTPClient interface {
 type Client struct {
 	client  HTTPClient
 	baseURL string
+	limiter *ratelimit.Limiter
 }
 
 // ClientOption определяет функциональную опцию для настройки клиента.
@@ -22,6 +24,7 @@ type ClientOption func(*clientConfig)
 
 type clientConfig struct {
 	httpClient HTTPClient
+	limiter    *ratelimit.Limiter
 }
 
 // WithHTTPClient устанавливает пользовательский HTTP клиент.
@@ -31,6 +34,22 @@ func WithHTTPClient(client HTTPClient) ClientOption {
 	}
 }
 
+// WithRateLimit ограничивает частоту запросов requestsPerMinute запросами
+// в минуту с запасом burst. Неположительное значение оставляет частоту
+// неограниченной, пока система начислений не сообщит свой лимит.
+func WithRateLimit(requestsPerMinute, burst int) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.limiter = ratelimit.NewLimiter(float64(requestsPerMinute)/60, burst)
+	}
+}
+
+// WithRateLimiter задаёт готовый ограничитель частоты запросов.
+func WithRateLimiter(limiter *ratelimit.Limiter) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.limiter = limiter
+	}
+}
+
 // NewClient создаёт новый HTTP клиент системы начислений с указанными опциями.
 func NewClient(baseURL string, opts ...ClientOption) *Client {
 	cfg := &clientConfig{}
@@ -45,8 +64,13 @@ func NewClient(baseURL string, opts ...ClientOption) *Client {
 			SetRetryCount(0)
 	}
 
+	if cfg.limiter == nil {
+		cfg.limiter = ratelimit.NewLimiter(0, 1)
+	}
+
 	return &Client{
 		client:  cfg.httpClient,
 		baseURL: baseURL,
+		limiter: cfg.limiter,
 	}
 }
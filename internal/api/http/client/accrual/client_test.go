@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/arvaliullin/gophermart/internal/api/http/client/accrual"
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	accrualservice "github.com/arvaliullin/gophermart/internal/core/services/accrual"
+	"github.com/arvaliullin/gophermart/internal/pkg/ratelimit"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -91,6 +94,83 @@ func TestClient_GetOrderAccrual_TooManyRequests(t *testing.T) {
 	assert.Equal(t, 60, int(retryErr.Duration.Seconds()))
 }
 
+func TestClient_GetOrderAccrual_TooManyRequestsAdaptsRate(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("No more than 30 requests per minute allowed"))
+	}))
+	defer server.Close()
+
+	limiter := ratelimit.NewLimiter(0, 1)
+	client := accrual.NewClient(server.URL, accrual.WithRateLimiter(limiter))
+
+	_, err := client.GetOrderAccrual(context.Background(), "12345678903")
+
+	var retryErr *accrualservice.RetryAfterError
+	require.ErrorAs(t, err, &retryErr)
+	assert.InDelta(t, 0.5, limiter.Rate(), 1e-9)
+	assert.InDelta(t, time.Minute.Seconds(), limiter.PausedFor().Seconds(), 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = client.GetOrderAccrual(ctx, "12345678903")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_GetOrderAccrual_RateLimit(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := accrual.NewClient(server.URL, accrual.WithRateLimit(60, 1))
+
+	_, err := client.GetOrderAccrual(context.Background(), "12345678903")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = client.GetOrderAccrual(ctx, "12345678903")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_TryGetOrderAccrual_FailsFast(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := accrual.NewClient(server.URL, accrual.WithRateLimit(60, 1))
+
+	_, err := client.TryGetOrderAccrual(context.Background(), "12345678903")
+
+	var retryErr *accrualservice.RetryAfterError
+	require.ErrorAs(t, err, &retryErr)
+	assert.InDelta(t, time.Minute.Seconds(), client.PausedFor().Seconds(), 1)
+
+	start := time.Now()
+	_, err = client.TryGetOrderAccrual(context.Background(), "12345678903")
+
+	require.ErrorAs(t, err, &retryErr)
+	assert.InDelta(t, time.Minute.Seconds(), retryErr.Duration.Seconds(), 1)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
 func TestClient_GetOrderAccrual_ServiceUnavailable(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -1,12 +1,15 @@
 package accrual
 
 import (
+	"regexp"
 	"strconv"
 	"time"
 
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 )
 
+var requestsPerMinuteRe = regexp.MustCompile(`(?i)no more than (\d+) requests per minute`)
+
 func mapStatus(status string) domain.OrderStatus {
 	statusMap := map[string]domain.OrderStatus{
 		"REGISTERED": domain.OrderStatusNew,
@@ -33,3 +36,17 @@ func parseRetryAfter(value string) time.Duration {
 
 	return time.Duration(seconds) * time.Second
 }
+
+func parseRequestsPerMinute(body string) (int, bool) {
+	match := requestsPerMinuteRe.FindStringSubmatch(body)
+	if match == nil {
+		return 0, false
+	}
+
+	limit, err := strconv.Atoi(match[1])
+	if err != nil || limit <= 0 {
+		return 0, false
+	}
+
+	return limit, true
+}
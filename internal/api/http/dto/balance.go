@@ -1,13 +1,18 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 )
 
 // BalanceResponse представляет ответ с информацией о балансе.
 type BalanceResponse struct {
-	Current   float64 `json:"current"`
-	Withdrawn float64 `json:"withdrawn"`
+	Current      float64 `json:"current"`
+	Withdrawn    float64 `json:"withdrawn"`
+	OnHold       float64 `json:"on_hold"`
+	ExpiringSoon float64 `json:"expiring_soon"`
+	Tier         string  `json:"tier"`
 }
 
 // WithdrawRequest представляет запрос на списание средств.
@@ -21,12 +26,82 @@ func (r *WithdrawRequest) IsValid() bool {
 	return r.Order != "" && r.Sum > 0
 }
 
+// HoldRequest представляет запрос на блокировку баллов под оплату заказа.
+type HoldRequest struct {
+	Order string  `json:"order"`
+	Sum   float64 `json:"sum"`
+}
+
+// IsValid проверяет корректность данных запроса.
+func (r *HoldRequest) IsValid() bool {
+	return r.Order != "" && r.Sum > 0
+}
+
+// HoldResponse представляет ответ с информацией о блокировке баллов.
+type HoldResponse struct {
+	ID        int64   `json:"id"`
+	Order     string  `json:"order"`
+	Sum       float64 `json:"sum"`
+	Status    string  `json:"status"`
+	CreatedAt string  `json:"created_at"`
+	ExpiresAt string  `json:"expires_at"`
+}
+
+// TransferRequest представляет запрос на перевод баллов другому пользователю.
+type TransferRequest struct {
+	Login string  `json:"login"`
+	Sum   float64 `json:"sum"`
+}
+
+// IsValid проверяет корректность данных запроса.
+func (r *TransferRequest) IsValid() bool {
+	return r.Login != "" && r.Sum > 0
+}
+
+// TransferResponse представляет ответ с информацией о переводе баллов.
+type TransferResponse struct {
+	ID        int64   `json:"id"`
+	Login     string  `json:"login"`
+	Sum       float64 `json:"sum"`
+	CreatedAt string  `json:"created_at"`
+}
+
 // FromDomainBalance преобразует доменный баланс в DTO.
 func FromDomainBalance(balance *domain.Balance) *BalanceResponse {
 	current, _ := balance.Current.Float64()
 	withdrawn, _ := balance.Withdrawn.Float64()
+	onHold, _ := balance.OnHold.Float64()
+	expiringSoon, _ := balance.ExpiringSoon.Float64()
 	return &BalanceResponse{
-		Current:   current,
-		Withdrawn: withdrawn,
+		Current:      current,
+		Withdrawn:    withdrawn,
+		OnHold:       onHold,
+		ExpiringSoon: expiringSoon,
+		Tier:         string(balance.Tier),
+	}
+}
+
+// FromDomainHold преобразует доменную блокировку баллов в DTO.
+func FromDomainHold(hold *domain.Hold) *HoldResponse {
+	sum, _ := hold.Amount.Float64()
+	return &HoldResponse{
+		ID:        hold.ID,
+		Order:     hold.OrderNumber,
+		Sum:       sum,
+		Status:    string(hold.Status),
+		CreatedAt: hold.CreatedAt.Format(time.RFC3339),
+		ExpiresAt: hold.ExpiresAt.Format(time.RFC3339),
+	}
+}
+
+// FromDomainTransfer преобразует доменный перевод в DTO.
+// login содержит логин получателя перевода.
+func FromDomainTransfer(transfer *domain.Transfer, login string) *TransferResponse {
+	sum, _ := transfer.Amount.Float64()
+	return &TransferResponse{
+		ID:        transfer.ID,
+		Login:     login,
+		Sum:       sum,
+		CreatedAt: transfer.CreatedAt.Format(time.RFC3339),
 	}
 }
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+)
+
+// DeadLetterOrderResponse представляет заказ, снятый с опроса системы начислений.
+type DeadLetterOrderResponse struct {
+	*OrderResponse
+	UserID         int64  `json:"user_id"`
+	Attempts       int    `json:"attempts"`
+	LastError      string `json:"last_error,omitempty"`
+	DeadLetteredAt string `json:"dead_lettered_at"`
+}
+
+// FromDomainDeadLetterOrders преобразует список снятых с опроса заказов в список DTO.
+func FromDomainDeadLetterOrders(orders []*domain.DeadLetterOrder) []*DeadLetterOrderResponse {
+	result := make([]*DeadLetterOrderResponse, len(orders))
+	for i, order := range orders {
+		result[i] = &DeadLetterOrderResponse{
+			OrderResponse:  FromDomainOrder(order.Order),
+			UserID:         order.Order.UserID,
+			Attempts:       order.Order.Attempts,
+			LastError:      order.LastError,
+			DeadLetteredAt: order.DeadLetteredAt.Format(time.RFC3339),
+		}
+	}
+	return result
+}
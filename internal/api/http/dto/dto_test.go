@@ -1,12 +1,14 @@
 package dto
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAuthRequest_IsValid(t *testing.T) {
@@ -81,15 +83,43 @@ func TestWithdrawRequest_IsValid(t *testing.T) {
 
 func TestFromDomainBalance(t *testing.T) {
 	balance := &domain.Balance{
-		UserID:    1,
-		Current:   decimal.NewFromFloat(500.50),
-		Withdrawn: decimal.NewFromFloat(100.25),
+		UserID:       1,
+		Current:      decimal.NewFromFloat(500.50),
+		Withdrawn:    decimal.NewFromFloat(100.25),
+		OnHold:       decimal.NewFromFloat(30.0),
+		ExpiringSoon: decimal.NewFromFloat(12.5),
+		Tier:         domain.TierGold,
 	}
 
 	response := FromDomainBalance(balance)
 
 	assert.Equal(t, 500.50, response.Current)
 	assert.Equal(t, 100.25, response.Withdrawn)
+	assert.Equal(t, 30.0, response.OnHold)
+	assert.Equal(t, 12.5, response.ExpiringSoon)
+	assert.Equal(t, "GOLD", response.Tier)
+}
+
+func TestFromDomainHold(t *testing.T) {
+	createdAt := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	hold := &domain.Hold{
+		ID:          4,
+		UserID:      1,
+		OrderNumber: "2377225624",
+		Amount:      decimal.NewFromFloat(75.5),
+		Status:      domain.HoldStatusActive,
+		CreatedAt:   createdAt,
+		ExpiresAt:   createdAt.Add(15 * time.Minute),
+	}
+
+	response := FromDomainHold(hold)
+
+	assert.Equal(t, int64(4), response.ID)
+	assert.Equal(t, "2377225624", response.Order)
+	assert.Equal(t, 75.5, response.Sum)
+	assert.Equal(t, "ACTIVE", response.Status)
+	assert.Equal(t, "2024-01-15T12:00:00Z", response.CreatedAt)
+	assert.Equal(t, "2024-01-15T12:15:00Z", response.ExpiresAt)
 }
 
 func TestFromDomainOrder(t *testing.T) {
@@ -147,6 +177,29 @@ func TestFromDomainOrders(t *testing.T) {
 	assert.Equal(t, "PROCESSING", responses[1].Status)
 }
 
+func TestFromDomainOrderDetails(t *testing.T) {
+	uploadedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	accrual := decimal.NewFromFloat(250.0)
+	details := &domain.OrderDetails{
+		Order: &domain.Order{Number: "12345678903", Status: domain.OrderStatusProcessed, Accrual: &accrual, UploadedAt: uploadedAt},
+		History: []*domain.OrderStatusChange{
+			{Status: domain.OrderStatusNew, ChangedAt: uploadedAt},
+			{Status: domain.OrderStatusProcessed, Accrual: &accrual, ChangedAt: uploadedAt.Add(time.Minute)},
+		},
+	}
+
+	response := FromDomainOrderDetails(details)
+
+	assert.Equal(t, "12345678903", response.Number)
+	require.Len(t, response.History, 2)
+	assert.Equal(t, "NEW", response.History[0].Status)
+	assert.Nil(t, response.History[0].Accrual)
+	assert.Equal(t, "PROCESSED", response.History[1].Status)
+	require.NotNil(t, response.History[1].Accrual)
+	assert.Equal(t, 250.0, *response.History[1].Accrual)
+	assert.Equal(t, "2024-01-15T10:31:00Z", response.History[1].ChangedAt)
+}
+
 func TestFromDomainOrders_Empty(t *testing.T) {
 	responses := FromDomainOrders([]*domain.Order{})
 	assert.Empty(t, responses)
@@ -167,6 +220,129 @@ func TestFromDomainWithdrawal(t *testing.T) {
 	assert.Equal(t, "2377225624", response.Order)
 	assert.Equal(t, 500.0, response.Sum)
 	assert.Equal(t, "2024-01-15T12:00:00Z", response.ProcessedAt)
+
+	data, err := json.Marshal(response)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"id"`)
+	assert.NotContains(t, string(data), `"refunded"`)
+}
+
+func TestFromDomainWithdrawalDetails(t *testing.T) {
+	processedAt := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	withdrawal := &domain.Withdrawal{
+		ID:          1,
+		UserID:      1,
+		OrderNumber: "2377225624",
+		Sum:         decimal.NewFromFloat(500.0),
+		Refunded:    decimal.NewFromFloat(120.5),
+		ProcessedAt: processedAt,
+	}
+
+	response := FromDomainWithdrawalDetails(withdrawal)
+
+	assert.Equal(t, int64(1), response.ID)
+	assert.Equal(t, "2377225624", response.Order)
+	assert.Equal(t, 500.0, response.Sum)
+	assert.Equal(t, 120.5, response.Refunded)
+	assert.Equal(t, "2024-01-15T12:00:00Z", response.ProcessedAt)
+}
+
+func TestFromDomainRefund(t *testing.T) {
+	processedAt := time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC)
+	refund := &domain.Refund{
+		ID:           3,
+		WithdrawalID: 1,
+		UserID:       1,
+		Sum:          decimal.NewFromFloat(120.5),
+		ProcessedAt:  processedAt,
+	}
+
+	response := FromDomainRefund(refund)
+
+	assert.Equal(t, int64(3), response.ID)
+	assert.Equal(t, int64(1), response.WithdrawalID)
+	assert.Equal(t, 120.5, response.Sum)
+	assert.Equal(t, "2024-01-16T09:00:00Z", response.ProcessedAt)
+}
+
+func TestRefundRequest_IsValid(t *testing.T) {
+	positive := 10.0
+	zero := 0.0
+
+	tests := []struct {
+		name     string
+		request  RefundRequest
+		expected bool
+	}{
+		{
+			name:     "без суммы",
+			request:  RefundRequest{},
+			expected: true,
+		},
+		{
+			name:     "положительная сумма",
+			request:  RefundRequest{Sum: &positive},
+			expected: true,
+		},
+		{
+			name:     "нулевая сумма",
+			request:  RefundRequest{Sum: &zero},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.request.IsValid())
+		})
+	}
+}
+
+func TestTransferRequest_IsValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  TransferRequest
+		expected bool
+	}{
+		{
+			name:     "корректный запрос",
+			request:  TransferRequest{Login: "friend", Sum: 10},
+			expected: true,
+		},
+		{
+			name:     "пустой логин",
+			request:  TransferRequest{Sum: 10},
+			expected: false,
+		},
+		{
+			name:     "нулевая сумма",
+			request:  TransferRequest{Login: "friend"},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.request.IsValid())
+		})
+	}
+}
+
+func TestFromDomainTransfer(t *testing.T) {
+	transfer := &domain.Transfer{
+		ID:         7,
+		FromUserID: 1,
+		ToUserID:   2,
+		Amount:     decimal.NewFromFloat(42.5),
+		CreatedAt:  time.Date(2024, 1, 17, 10, 0, 0, 0, time.UTC),
+	}
+
+	response := FromDomainTransfer(transfer, "friend")
+
+	assert.Equal(t, int64(7), response.ID)
+	assert.Equal(t, "friend", response.Login)
+	assert.Equal(t, 42.5, response.Sum)
+	assert.Equal(t, "2024-01-17T10:00:00Z", response.CreatedAt)
 }
 
 func TestFromDomainWithdrawals(t *testing.T) {
@@ -189,3 +365,198 @@ func TestFromDomainWithdrawals_Empty(t *testing.T) {
 	responses := FromDomainWithdrawals([]*domain.Withdrawal{})
 	assert.Empty(t, responses)
 }
+
+func TestFromDomainStatement(t *testing.T) {
+	statement := &domain.Statement{
+		Entries: []*domain.StatementEntry{
+			{
+				LedgerEntry: domain.LedgerEntry{
+					ID:          5,
+					Kind:        domain.LedgerEntryWithdrawal,
+					Amount:      decimal.NewFromFloat(-20.5),
+					OrderNumber: "2377225624",
+					CreatedAt:   time.Date(2024, 1, 18, 8, 0, 0, 0, time.UTC),
+				},
+				Balance: decimal.NewFromFloat(79.5),
+			},
+		},
+		NextCursor: 5,
+	}
+
+	response := FromDomainStatement(statement)
+
+	assert.Len(t, response.Entries, 1)
+	assert.Equal(t, int64(5), response.Entries[0].ID)
+	assert.Equal(t, "WITHDRAWAL", response.Entries[0].Kind)
+	assert.Equal(t, "2377225624", response.Entries[0].Order)
+	assert.Equal(t, -20.5, response.Entries[0].Amount)
+	assert.Equal(t, 79.5, response.Entries[0].Balance)
+	assert.Equal(t, "2024-01-18T08:00:00Z", response.Entries[0].CreatedAt)
+	assert.Equal(t, int64(5), response.NextCursor)
+}
+
+func TestExportRecord(t *testing.T) {
+	accrual := decimal.NewFromFloat(150.5)
+	order := &domain.Order{
+		Number:     "12345678903",
+		Status:     domain.OrderStatusProcessed,
+		Accrual:    &accrual,
+		UploadedAt: time.Date(2024, 1, 19, 7, 0, 0, 0, time.UTC),
+	}
+	withdrawal := &domain.Withdrawal{
+		OrderNumber: "2377225624",
+		Sum:         decimal.NewFromFloat(40.0),
+		ProcessedAt: time.Date(2024, 1, 20, 7, 0, 0, 0, time.UTC),
+	}
+
+	assert.Equal(t,
+		[]string{"order", "12345678903", "PROCESSED", "150.50", "2024-01-19T07:00:00Z"},
+		ExportRecordFromOrder(order).CSV())
+	assert.Equal(t,
+		[]string{"withdrawal", "2377225624", "", "40.00", "2024-01-20T07:00:00Z"},
+		ExportRecordFromWithdrawal(withdrawal).CSV())
+
+	precise := decimal.RequireFromString("0.1").Add(decimal.RequireFromString("0.2"))
+	order.Accrual = &precise
+	assert.Equal(t, "0.30", ExportRecordFromOrder(order).Amount)
+
+	order.Accrual = nil
+	assert.Equal(t, "", ExportRecordFromOrder(order).CSV()[3])
+}
+
+func TestFromDomainReconciliationReport(t *testing.T) {
+	report := &domain.ReconciliationReport{
+		CheckedAt: time.Date(2024, 1, 21, 6, 0, 0, 0, time.UTC),
+		Mismatches: []*domain.BalanceMismatch{
+			{
+				UserID:            3,
+				ExpectedCurrent:   decimal.NewFromFloat(80.0),
+				ActualCurrent:     decimal.NewFromFloat(50.0),
+				ExpectedWithdrawn: decimal.NewFromFloat(20.0),
+				ActualWithdrawn:   decimal.NewFromFloat(20.0),
+			},
+		},
+	}
+
+	response := FromDomainReconciliationReport(report)
+
+	assert.Equal(t, "2024-01-21T06:00:00Z", response.CheckedAt)
+	assert.Len(t, response.Mismatches, 1)
+	assert.Equal(t, int64(3), response.Mismatches[0].UserID)
+	assert.Equal(t, 80.0, response.Mismatches[0].ExpectedCurrent)
+	assert.Equal(t, 50.0, response.Mismatches[0].ActualCurrent)
+	assert.Zero(t, response.Mismatches[0].AdjustmentID)
+}
+
+func TestWithdrawRequestV2_IsValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      WithdrawRequestV2
+		expected bool
+	}{
+		{"корректный запрос", WithdrawRequestV2{Order: "79927398713", Sum: "0.30"}, true},
+		{"пустой номер заказа", WithdrawRequestV2{Order: "", Sum: "100"}, false},
+		{"пустая сумма", WithdrawRequestV2{Order: "79927398713", Sum: ""}, false},
+		{"сумма не число", WithdrawRequestV2{Order: "79927398713", Sum: "abc"}, false},
+		{"нулевая сумма", WithdrawRequestV2{Order: "79927398713", Sum: "0"}, false},
+		{"отрицательная сумма", WithdrawRequestV2{Order: "79927398713", Sum: "-1.5"}, false},
+		{"больше двух знаков после запятой", WithdrawRequestV2{Order: "79927398713", Sum: "0.001"}, false},
+		{"экспоненциальная запись мельче копейки", WithdrawRequestV2{Order: "79927398713", Sum: "1e-5"}, false},
+		{"экспоненциальная запись целой суммы", WithdrawRequestV2{Order: "79927398713", Sum: "1e2"}, true},
+		{"незначащие нули после запятой", WithdrawRequestV2{Order: "79927398713", Sum: "5.500"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.req.IsValid())
+		})
+	}
+}
+
+func TestWithdrawRequestV2_Amount(t *testing.T) {
+	req := WithdrawRequestV2{Order: "79927398713", Sum: "0.30"}
+
+	assert.True(t, decimal.RequireFromString("0.3").Equal(req.Amount()))
+}
+
+func TestFromDomainBalanceV2(t *testing.T) {
+	balance := &domain.Balance{
+		Current:      decimal.RequireFromString("0.1").Add(decimal.RequireFromString("0.2")),
+		Withdrawn:    decimal.RequireFromString("100.25"),
+		OnHold:       decimal.Zero,
+		ExpiringSoon: decimal.RequireFromString("12.5"),
+		Tier:         domain.TierBronze,
+	}
+
+	response := FromDomainBalanceV2(balance)
+
+	assert.Equal(t, "0.3", response.Current)
+	assert.Equal(t, "100.25", response.Withdrawn)
+	assert.Equal(t, "0", response.OnHold)
+	assert.Equal(t, "12.5", response.ExpiringSoon)
+	assert.Equal(t, "BRONZE", response.Tier)
+}
+
+func TestFromDomainOrdersV2(t *testing.T) {
+	uploadedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	accrual := decimal.RequireFromString("729.98")
+	orders := []*domain.Order{
+		{Number: "12345678903", Status: domain.OrderStatusProcessed, Accrual: &accrual, UploadedAt: uploadedAt},
+		{Number: "79927398713", Status: domain.OrderStatusNew, UploadedAt: uploadedAt},
+	}
+
+	response := FromDomainOrdersV2(orders)
+
+	assert.Len(t, response, 2)
+	if assert.NotNil(t, response[0].Accrual) {
+		assert.Equal(t, "729.98", *response[0].Accrual)
+	}
+	assert.Nil(t, response[1].Accrual)
+	assert.Equal(t, "2024-01-15T10:30:00Z", response[1].UploadedAt)
+}
+
+func TestFromDomainWithdrawalsV2(t *testing.T) {
+	processedAt := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	withdrawals := []*domain.Withdrawal{
+		{ID: 1, OrderNumber: "79927398713", Sum: decimal.RequireFromString("0.30"), ProcessedAt: processedAt},
+		{ID: 2, OrderNumber: "12345678903", Sum: decimal.RequireFromString("50"), Refunded: decimal.RequireFromString("10.01"), ProcessedAt: processedAt},
+	}
+
+	response := FromDomainWithdrawalsV2(withdrawals)
+
+	assert.Len(t, response, 2)
+	assert.Equal(t, "0.3", response[0].Sum)
+	assert.Empty(t, response[0].Refunded)
+	assert.Equal(t, "50", response[1].Sum)
+	assert.Equal(t, "10.01", response[1].Refunded)
+}
+
+func TestWithdrawalLimitsRequest(t *testing.T) {
+	daily := 500.0
+	negative := -1.0
+
+	assert.True(t, (&WithdrawalLimitsRequest{}).IsValid())
+	assert.True(t, (&WithdrawalLimitsRequest{Daily: &daily}).IsValid())
+	assert.False(t, (&WithdrawalLimitsRequest{Monthly: &negative}).IsValid())
+
+	override := (&WithdrawalLimitsRequest{Daily: &daily}).ToDomain(3)
+	assert.Equal(t, int64(3), override.UserID)
+	assert.Nil(t, override.PerTransaction)
+	if assert.NotNil(t, override.Daily) {
+		assert.True(t, decimal.NewFromInt(500).Equal(*override.Daily))
+	}
+}
+
+func TestFromDomainOrderSubmissions(t *testing.T) {
+	response := FromDomainOrderSubmissions([]*domain.OrderSubmission{
+		{Number: "12345678903", Result: domain.OrderSubmitAccepted},
+		{Number: "123", Result: domain.OrderSubmitInvalid, Reason: "не совпадает контрольная сумма"},
+	})
+
+	assert.Len(t, response, 2)
+	assert.Equal(t, "12345678903", response[0].Number)
+	assert.Equal(t, "ACCEPTED", response[0].Result)
+	assert.Equal(t, "INVALID", response[1].Result)
+	assert.Empty(t, response[0].Reason)
+	assert.Equal(t, "не совпадает контрольная сумма", response[1].Reason)
+}
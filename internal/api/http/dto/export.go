@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+)
+
+// Типы записей выгрузки истории.
+const (
+	ExportRecordOrder      = "order"
+	ExportRecordWithdrawal = "withdrawal"
+)
+
+// ExportCSVHeader задаёт заголовок CSV выгрузки в порядке полей ExportRecord.CSV.
+var ExportCSVHeader = []string{"type", "number", "status", "amount", "date"}
+
+// ExportRecord представляет запись выгрузки истории: заказ или списание.
+// Сумма передаётся десятичной строкой с двумя знаками после запятой, чтобы
+// выгрузка сходилась с учётом до копейки.
+type ExportRecord struct {
+	Type   string `json:"type"`
+	Number string `json:"number"`
+	Status string `json:"status,omitempty"`
+	Amount string `json:"amount,omitempty"`
+	Date   string `json:"date"`
+}
+
+// CSV возвращает поля записи для CSV выгрузки.
+func (r *ExportRecord) CSV() []string {
+	return []string{r.Type, r.Number, r.Status, r.Amount, r.Date}
+}
+
+// ExportRecordFromOrder преобразует заказ в запись выгрузки.
+func ExportRecordFromOrder(order *domain.Order) *ExportRecord {
+	record := &ExportRecord{
+		Type:   ExportRecordOrder,
+		Number: order.Number,
+		Status: string(order.Status),
+		Date:   order.UploadedAt.Format(time.RFC3339),
+	}
+	if order.Accrual != nil {
+		record.Amount = order.Accrual.StringFixed(2)
+	}
+	return record
+}
+
+// ExportRecordFromWithdrawal преобразует списание в запись выгрузки.
+func ExportRecordFromWithdrawal(w *domain.Withdrawal) *ExportRecord {
+	return &ExportRecord{
+		Type:   ExportRecordWithdrawal,
+		Number: w.OrderNumber,
+		Amount: w.Sum.StringFixed(2),
+		Date:   w.ProcessedAt.Format(time.RFC3339),
+	}
+}
@@ -37,3 +37,64 @@ func FromDomainOrders(orders []*domain.Order) []*OrderResponse {
 	}
 	return result
 }
+
+// OrderStatusChangeResponse представляет запись истории статусов заказа.
+type OrderStatusChangeResponse struct {
+	Status    string   `json:"status"`
+	Accrual   *float64 `json:"accrual,omitempty"`
+	ChangedAt string   `json:"changed_at"`
+}
+
+// OrderDetailsResponse представляет заказ с историей статусов и списаниями,
+// оформленными на его номер.
+type OrderDetailsResponse struct {
+	*OrderResponse
+	History     []*OrderStatusChangeResponse `json:"history"`
+	Withdrawals []*WithdrawalDetailsResponse `json:"withdrawals"`
+}
+
+// FromDomainOrderDetails преобразует сведения о заказе в DTO.
+func FromDomainOrderDetails(details *domain.OrderDetails) *OrderDetailsResponse {
+	history := make([]*OrderStatusChangeResponse, len(details.History))
+	for i, change := range details.History {
+		var accrual *float64
+		if change.Accrual != nil {
+			f, _ := change.Accrual.Float64()
+			accrual = &f
+		}
+		history[i] = &OrderStatusChangeResponse{
+			Status:    string(change.Status),
+			Accrual:   accrual,
+			ChangedAt: change.ChangedAt.Format(time.RFC3339),
+		}
+	}
+	withdrawals := make([]*WithdrawalDetailsResponse, len(details.Withdrawals))
+	for i, w := range details.Withdrawals {
+		withdrawals[i] = FromDomainWithdrawalDetails(w)
+	}
+	return &OrderDetailsResponse{
+		OrderResponse: FromDomainOrder(details.Order),
+		History:       history,
+		Withdrawals:   withdrawals,
+	}
+}
+
+// OrderSubmissionResponse представляет результат загрузки номера заказа из пакета.
+type OrderSubmissionResponse struct {
+	Number string `json:"number"`
+	Result string `json:"result"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// FromDomainOrderSubmissions преобразует результаты пакетной загрузки заказов в список DTO.
+func FromDomainOrderSubmissions(submissions []*domain.OrderSubmission) []*OrderSubmissionResponse {
+	result := make([]*OrderSubmissionResponse, len(submissions))
+	for i, submission := range submissions {
+		result[i] = &OrderSubmissionResponse{
+			Number: submission.Number,
+			Result: string(submission.Result),
+			Reason: submission.Reason,
+		}
+	}
+	return result
+}
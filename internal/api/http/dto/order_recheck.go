@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+)
+
+// AccrualCorrectionResponse представляет проводку, изменившую начисление по заказу.
+type AccrualCorrectionResponse struct {
+	ID          int64   `json:"id"`
+	Kind        string  `json:"kind"`
+	Amount      float64 `json:"amount"`
+	Description string  `json:"description,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+}
+
+// OrderRecheckResponse представляет результат перепроверки заказа.
+type OrderRecheckResponse struct {
+	UserID     int64                      `json:"user_id"`
+	Previous   *OrderResponse             `json:"previous"`
+	Order      *OrderResponse             `json:"order"`
+	Correction *AccrualCorrectionResponse `json:"correction,omitempty"`
+}
+
+// FromDomainOrderRecheck преобразует результат перепроверки заказа в DTO.
+func FromDomainOrderRecheck(recheck *domain.OrderRecheck) *OrderRecheckResponse {
+	response := &OrderRecheckResponse{
+		UserID:   recheck.Order.UserID,
+		Previous: FromDomainOrder(recheck.Previous),
+		Order:    FromDomainOrder(recheck.Order),
+	}
+	if entry := recheck.Correction; entry != nil {
+		amount, _ := entry.Amount.Float64()
+		response.Correction = &AccrualCorrectionResponse{
+			ID:          entry.ID,
+			Kind:        string(entry.Kind),
+			Amount:      amount,
+			Description: entry.Description,
+			CreatedAt:   entry.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return response
+}
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+)
+
+// BalanceMismatchResponse представляет расхождение баланса пользователя.
+type BalanceMismatchResponse struct {
+	UserID            int64   `json:"user_id"`
+	ExpectedCurrent   float64 `json:"expected_current"`
+	ActualCurrent     float64 `json:"actual_current"`
+	ExpectedWithdrawn float64 `json:"expected_withdrawn"`
+	ActualWithdrawn   float64 `json:"actual_withdrawn"`
+	AdjustmentID      int64   `json:"adjustment_id,omitempty"`
+}
+
+// ReconciliationResponse представляет отчёт о сверке балансов.
+type ReconciliationResponse struct {
+	CheckedAt  string                     `json:"checked_at"`
+	Mismatches []*BalanceMismatchResponse `json:"mismatches"`
+}
+
+// FromDomainReconciliationReport преобразует отчёт о сверке в DTO.
+func FromDomainReconciliationReport(report *domain.ReconciliationReport) *ReconciliationResponse {
+	mismatches := make([]*BalanceMismatchResponse, len(report.Mismatches))
+	for i, m := range report.Mismatches {
+		expectedCurrent, _ := m.ExpectedCurrent.Float64()
+		actualCurrent, _ := m.ActualCurrent.Float64()
+		expectedWithdrawn, _ := m.ExpectedWithdrawn.Float64()
+		actualWithdrawn, _ := m.ActualWithdrawn.Float64()
+		mismatches[i] = &BalanceMismatchResponse{
+			UserID:            m.UserID,
+			ExpectedCurrent:   expectedCurrent,
+			ActualCurrent:     actualCurrent,
+			ExpectedWithdrawn: expectedWithdrawn,
+			ActualWithdrawn:   actualWithdrawn,
+		}
+		if m.Adjustment != nil {
+			mismatches[i].AdjustmentID = m.Adjustment.ID
+		}
+	}
+	return &ReconciliationResponse{
+		CheckedAt:  report.CheckedAt.Format(time.RFC3339),
+		Mismatches: mismatches,
+	}
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+)
+
+// StatementEntryResponse представляет строку выписки по счёту.
+type StatementEntryResponse struct {
+	ID          int64   `json:"id"`
+	Kind        string  `json:"kind"`
+	Order       string  `json:"order,omitempty"`
+	Amount      float64 `json:"amount"`
+	Balance     float64 `json:"balance"`
+	Description string  `json:"description,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+}
+
+// StatementResponse представляет страницу выписки по счёту.
+type StatementResponse struct {
+	Entries    []*StatementEntryResponse `json:"entries"`
+	NextCursor int64                     `json:"next_cursor,omitempty"`
+}
+
+// FromDomainStatementEntry преобразует строку выписки в DTO.
+func FromDomainStatementEntry(e *domain.StatementEntry) *StatementEntryResponse {
+	amount, _ := e.Amount.Float64()
+	balance, _ := e.Balance.Float64()
+	return &StatementEntryResponse{
+		ID:          e.ID,
+		Kind:        string(e.Kind),
+		Order:       e.OrderNumber,
+		Amount:      amount,
+		Balance:     balance,
+		Description: e.Description,
+		CreatedAt:   e.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// FromDomainStatement преобразует страницу выписки в DTO.
+func FromDomainStatement(s *domain.Statement) *StatementResponse {
+	entries := make([]*StatementEntryResponse, len(s.Entries))
+	for i, e := range s.Entries {
+		entries[i] = FromDomainStatementEntry(e)
+	}
+	return &StatementResponse{
+		Entries:    entries,
+		NextCursor: s.NextCursor,
+	}
+}
@@ -0,0 +1,121 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/shopspring/decimal"
+)
+
+// Типы этого файла используются маршрутами /api/v2. В отличие от v1 суммы
+// передаются десятичными строками, что исключает потерю точности при
+// преобразовании через float64.
+
+// BalanceResponseV2 представляет ответ с информацией о балансе в API v2.
+type BalanceResponseV2 struct {
+	Current      string `json:"current"`
+	Withdrawn    string `json:"withdrawn"`
+	OnHold       string `json:"on_hold"`
+	ExpiringSoon string `json:"expiring_soon"`
+	Tier         string `json:"tier"`
+}
+
+// OrderResponseV2 представляет ответ с информацией о заказе в API v2.
+type OrderResponseV2 struct {
+	Number     string  `json:"number"`
+	Status     string  `json:"status"`
+	Accrual    *string `json:"accrual,omitempty"`
+	UploadedAt string  `json:"uploaded_at"`
+}
+
+// WithdrawRequestV2 представляет запрос на списание средств в API v2.
+type WithdrawRequestV2 struct {
+	Order string `json:"order"`
+	Sum   string `json:"sum"`
+}
+
+// IsValid проверяет корректность данных запроса. Сумма должна быть
+// положительной и содержать не более двух знаков после запятой: суммы
+// хранятся с точностью до копеек, и более точное значение было бы
+// молча округлено при записи.
+func (r *WithdrawRequestV2) IsValid() bool {
+	if r.Order == "" {
+		return false
+	}
+	sum, err := decimal.NewFromString(r.Sum)
+	return err == nil && sum.IsPositive() && sum.Equal(sum.Round(2))
+}
+
+// Amount возвращает сумму списания. Вызывается после успешной проверки IsValid.
+func (r *WithdrawRequestV2) Amount() decimal.Decimal {
+	sum, _ := decimal.NewFromString(r.Sum)
+	return sum
+}
+
+// WithdrawalResponseV2 представляет ответ с информацией о списании в API v2.
+type WithdrawalResponseV2 struct {
+	ID          int64  `json:"id"`
+	Order       string `json:"order"`
+	Sum         string `json:"sum"`
+	Refunded    string `json:"refunded,omitempty"`
+	ProcessedAt string `json:"processed_at"`
+}
+
+// FromDomainBalanceV2 преобразует доменный баланс в DTO API v2.
+func FromDomainBalanceV2(balance *domain.Balance) *BalanceResponseV2 {
+	return &BalanceResponseV2{
+		Current:      balance.Current.String(),
+		Withdrawn:    balance.Withdrawn.String(),
+		OnHold:       balance.OnHold.String(),
+		ExpiringSoon: balance.ExpiringSoon.String(),
+		Tier:         string(balance.Tier),
+	}
+}
+
+// FromDomainOrderV2 преобразует доменный заказ в DTO API v2.
+func FromDomainOrderV2(order *domain.Order) *OrderResponseV2 {
+	var accrual *string
+	if order.Accrual != nil {
+		s := order.Accrual.String()
+		accrual = &s
+	}
+	return &OrderResponseV2{
+		Number:     order.Number,
+		Status:     string(order.Status),
+		Accrual:    accrual,
+		UploadedAt: order.UploadedAt.Format(time.RFC3339),
+	}
+}
+
+// FromDomainOrdersV2 преобразует список доменных заказов в список DTO API v2.
+func FromDomainOrdersV2(orders []*domain.Order) []*OrderResponseV2 {
+	result := make([]*OrderResponseV2, len(orders))
+	for i, order := range orders {
+		result[i] = FromDomainOrderV2(order)
+	}
+	return result
+}
+
+// FromDomainWithdrawalV2 преобразует доменное списание в DTO API v2.
+func FromDomainWithdrawalV2(w *domain.Withdrawal) *WithdrawalResponseV2 {
+	var refunded string
+	if !w.Refunded.IsZero() {
+		refunded = w.Refunded.String()
+	}
+	return &WithdrawalResponseV2{
+		ID:          w.ID,
+		Order:       w.OrderNumber,
+		Sum:         w.Sum.String(),
+		Refunded:    refunded,
+		ProcessedAt: w.ProcessedAt.Format(time.RFC3339),
+	}
+}
+
+// FromDomainWithdrawalsV2 преобразует список доменных списаний в список DTO API v2.
+func FromDomainWithdrawalsV2(withdrawals []*domain.Withdrawal) []*WithdrawalResponseV2 {
+	result := make([]*WithdrawalResponseV2, len(withdrawals))
+	for i, w := range withdrawals {
+		result[i] = FromDomainWithdrawalV2(w)
+	}
+	return result
+}
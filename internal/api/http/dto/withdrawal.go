@@ -13,6 +13,36 @@ type WithdrawalResponse struct {
 	ProcessedAt string  `json:"processed_at"`
 }
 
+// WithdrawalDetailsResponse представляет списание с идентификатором
+// и уже возвращённой суммой. Используется в ответах, появившихся вместе
+// с возвратами, чтобы не менять формат истории списаний.
+type WithdrawalDetailsResponse struct {
+	ID          int64   `json:"id"`
+	Order       string  `json:"order"`
+	Sum         float64 `json:"sum"`
+	Refunded    float64 `json:"refunded,omitempty"`
+	ProcessedAt string  `json:"processed_at"`
+}
+
+// RefundRequest представляет запрос на возврат баллов по списанию.
+// Если сумма не указана, возвращается вся невозвращённая часть списания.
+type RefundRequest struct {
+	Sum *float64 `json:"sum,omitempty"`
+}
+
+// IsValid проверяет корректность данных запроса.
+func (r *RefundRequest) IsValid() bool {
+	return r.Sum == nil || *r.Sum > 0
+}
+
+// RefundResponse представляет ответ с информацией о возврате.
+type RefundResponse struct {
+	ID           int64   `json:"id"`
+	WithdrawalID int64   `json:"withdrawal_id"`
+	Sum          float64 `json:"sum"`
+	ProcessedAt  string  `json:"processed_at"`
+}
+
 // FromDomainWithdrawal преобразует доменное списание в DTO.
 func FromDomainWithdrawal(w *domain.Withdrawal) *WithdrawalResponse {
 	sum, _ := w.Sum.Float64()
@@ -23,6 +53,31 @@ func FromDomainWithdrawal(w *domain.Withdrawal) *WithdrawalResponse {
 	}
 }
 
+// FromDomainWithdrawalDetails преобразует доменное списание в DTO
+// с идентификатором и возвращённой суммой.
+func FromDomainWithdrawalDetails(w *domain.Withdrawal) *WithdrawalDetailsResponse {
+	sum, _ := w.Sum.Float64()
+	refunded, _ := w.Refunded.Float64()
+	return &WithdrawalDetailsResponse{
+		ID:          w.ID,
+		Order:       w.OrderNumber,
+		Sum:         sum,
+		Refunded:    refunded,
+		ProcessedAt: w.ProcessedAt.Format(time.RFC3339),
+	}
+}
+
+// FromDomainRefund преобразует доменный возврат в DTO.
+func FromDomainRefund(r *domain.Refund) *RefundResponse {
+	sum, _ := r.Sum.Float64()
+	return &RefundResponse{
+		ID:           r.ID,
+		WithdrawalID: r.WithdrawalID,
+		Sum:          sum,
+		ProcessedAt:  r.ProcessedAt.Format(time.RFC3339),
+	}
+}
+
 // FromDomainWithdrawals преобразует список доменных списаний в список DTO.
 func FromDomainWithdrawals(withdrawals []*domain.Withdrawal) []*WithdrawalResponse {
 	result := make([]*WithdrawalResponse, len(withdrawals))
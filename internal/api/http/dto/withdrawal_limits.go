@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/shopspring/decimal"
+)
+
+// WithdrawalLimitsRequest представляет запрос на назначение пользователю
+// индивидуальных лимитов списаний. Незаданный лимит наследуется от лимитов
+// по умолчанию, нулевой снимает ограничение.
+type WithdrawalLimitsRequest struct {
+	PerTransaction *float64 `json:"per_transaction,omitempty"`
+	Daily          *float64 `json:"daily,omitempty"`
+	Monthly        *float64 `json:"monthly,omitempty"`
+}
+
+// IsValid проверяет корректность данных запроса.
+func (r *WithdrawalLimitsRequest) IsValid() bool {
+	for _, limit := range []*float64{r.PerTransaction, r.Daily, r.Monthly} {
+		if limit != nil && *limit < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ToDomain преобразует запрос в индивидуальные лимиты пользователя userID.
+func (r *WithdrawalLimitsRequest) ToDomain(userID int64) *domain.WithdrawalLimitsOverride {
+	return &domain.WithdrawalLimitsOverride{
+		UserID:         userID,
+		PerTransaction: optionalDecimal(r.PerTransaction),
+		Daily:          optionalDecimal(r.Daily),
+		Monthly:        optionalDecimal(r.Monthly),
+	}
+}
+
+// WithdrawalLimitsResponse представляет лимиты списаний, действующие для пользователя.
+// Нулевое значение означает отсутствие лимита.
+type WithdrawalLimitsResponse struct {
+	UserID         int64   `json:"user_id"`
+	PerTransaction float64 `json:"per_transaction"`
+	Daily          float64 `json:"daily"`
+	Monthly        float64 `json:"monthly"`
+	Overridden     bool    `json:"overridden"`
+}
+
+// FromDomainUserWithdrawalLimits преобразует действующие лимиты пользователя в DTO.
+func FromDomainUserWithdrawalLimits(limits *domain.UserWithdrawalLimits) *WithdrawalLimitsResponse {
+	perTransaction, _ := limits.Limits.PerTransaction.Float64()
+	daily, _ := limits.Limits.Daily.Float64()
+	monthly, _ := limits.Limits.Monthly.Float64()
+	return &WithdrawalLimitsResponse{
+		UserID:         limits.UserID,
+		PerTransaction: perTransaction,
+		Daily:          daily,
+		Monthly:        monthly,
+		Overridden:     limits.Overridden,
+	}
+}
+
+func optionalDecimal(value *float64) *decimal.Decimal {
+	if value == nil {
+		return nil
+	}
+	d := decimal.NewFromFloat(*value)
+	return &d
+}
@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/arvaliullin/gophermart/internal/api/http/dto"
 	"github.com/arvaliullin/gophermart/internal/api/http/middleware"
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/go-chi/chi/v5"
 	"github.com/shopspring/decimal"
 )
 
@@ -43,6 +45,25 @@ func (h *BalanceHandler) Get(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(dto.FromDomainBalance(bal))
 }
 
+// GetV2 возвращает текущий баланс пользователя с суммами в виде десятичных строк.
+func (h *BalanceHandler) GetV2(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		http.Error(w, "пользователь не авторизован", http.StatusUnauthorized)
+		return
+	}
+
+	bal, err := h.balanceService.GetBalance(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromDomainBalanceV2(bal))
+}
+
 // Withdraw обрабатывает запрос на списание средств.
 func (h *BalanceHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
@@ -62,12 +83,82 @@ func (h *BalanceHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.balanceService.Withdraw(r.Context(), userID, req.Order, decimal.NewFromFloat(req.Sum))
+	h.withdraw(w, r, userID, req.Order, decimal.NewFromFloat(req.Sum))
+}
+
+// WithdrawV2 обрабатывает запрос на списание средств с суммой в виде десятичной строки.
+func (h *BalanceHandler) WithdrawV2(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		http.Error(w, "пользователь не авторизован", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.WithdrawRequestV2
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !req.IsValid() {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.withdraw(w, r, userID, req.Order, req.Amount())
+}
+
+func (h *BalanceHandler) withdraw(w http.ResponseWriter, r *http.Request, userID int64, order string, amount decimal.Decimal) {
+	err := h.balanceService.Withdraw(r.Context(), userID, order, amount)
 	if err != nil {
 		if errors.Is(err, domain.ErrInvalidOrderNumber) {
 			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 			return
 		}
+		if errors.Is(err, domain.ErrWithdrawalLimitExceeded) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, domain.ErrInsufficientBalance) {
+			http.Error(w, err.Error(), http.StatusPaymentRequired)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Hold обрабатывает запрос на блокировку баллов под оплату заказа.
+func (h *BalanceHandler) Hold(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		http.Error(w, "пользователь не авторизован", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.HoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !req.IsValid() {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	hold, err := h.balanceService.Hold(r.Context(), userID, req.Order, decimal.NewFromFloat(req.Sum))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidOrderNumber) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if errors.Is(err, domain.ErrWithdrawalLimitExceeded) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		if errors.Is(err, domain.ErrInsufficientBalance) {
 			http.Error(w, err.Error(), http.StatusPaymentRequired)
 			return
@@ -76,5 +167,117 @@ func (h *BalanceHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.FromDomainHold(hold))
+}
+
+// CaptureHold обрабатывает запрос на подтверждение блокировки и списание баллов.
+// Вызывается магазином с административным токеном для блокировки любого пользователя.
+func (h *BalanceHandler) CaptureHold(w http.ResponseWriter, r *http.Request) {
+	holdID, ok := parseHoldID(r)
+	if !ok {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	withdrawal, err := h.balanceService.CaptureHold(r.Context(), holdID)
+	if err != nil {
+		writeHoldError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromDomainWithdrawalDetails(withdrawal))
+}
+
+// ReleaseHold обрабатывает запрос на отмену блокировки баллов.
+// Вызывается магазином с административным токеном для блокировки любого пользователя.
+func (h *BalanceHandler) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	holdID, ok := parseHoldID(r)
+	if !ok {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	hold, err := h.balanceService.ReleaseHold(r.Context(), holdID)
+	if err != nil {
+		writeHoldError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromDomainHold(hold))
+}
+
+// Transfer обрабатывает запрос на перевод баллов другому пользователю.
+func (h *BalanceHandler) Transfer(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		http.Error(w, "пользователь не авторизован", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.TransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !req.IsValid() {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	transfer, err := h.balanceService.Transfer(r.Context(), userID, req.Login, decimal.NewFromFloat(req.Sum))
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, domain.ErrTransferToSelf) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, domain.ErrTransferLimitExceeded) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, domain.ErrInsufficientBalance) {
+			http.Error(w, err.Error(), http.StatusPaymentRequired)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromDomainTransfer(transfer, req.Login))
+}
+
+func parseHoldID(r *http.Request) (int64, bool) {
+	holdID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || holdID <= 0 {
+		return 0, false
+	}
+	return holdID, true
+}
+
+func writeHoldError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrHoldNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, domain.ErrHoldNotActive) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, domain.ErrWithdrawalLimitExceeded) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
 }
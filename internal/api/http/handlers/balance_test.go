@@ -7,11 +7,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/arvaliullin/gophermart/internal/api/http/handlers"
 	"github.com/arvaliullin/gophermart/internal/api/http/middleware"
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
+	"github.com/go-chi/chi/v5"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -25,6 +27,8 @@ func TestBalanceHandler_Get(t *testing.T) {
 		wantStatusCode int
 		wantCurrent    string
 		wantWithdrawn  string
+		wantOnHold     string
+		wantTier       string
 	}{
 		{
 			name:   "success",
@@ -36,11 +40,15 @@ func TestBalanceHandler_Get(t *testing.T) {
 						UserID:    1,
 						Current:   decimal.NewFromFloat(500.5),
 						Withdrawn: decimal.NewFromFloat(100.0),
+						OnHold:    decimal.NewFromFloat(25.0),
+						Tier:      domain.TierSilver,
 					}, nil)
 			},
 			wantStatusCode: http.StatusOK,
 			wantCurrent:    "500.5",
 			wantWithdrawn:  "100",
+			wantOnHold:     "25",
+			wantTier:       "SILVER",
 		},
 		{
 			name:           "unauthorized",
@@ -74,11 +82,15 @@ func TestBalanceHandler_Get(t *testing.T) {
 			assert.Equal(t, tt.wantStatusCode, rr.Code)
 
 			if tt.wantStatusCode == http.StatusOK {
-				var resp map[string]json.Number
-				err := json.NewDecoder(rr.Body).Decode(&resp)
+				var resp map[string]any
+				decoder := json.NewDecoder(rr.Body)
+				decoder.UseNumber()
+				err := decoder.Decode(&resp)
 				assert.NoError(t, err)
-				assert.Equal(t, tt.wantCurrent, resp["current"].String())
-				assert.Equal(t, tt.wantWithdrawn, resp["withdrawn"].String())
+				assert.Equal(t, json.Number(tt.wantCurrent), resp["current"])
+				assert.Equal(t, json.Number(tt.wantWithdrawn), resp["withdrawn"])
+				assert.Equal(t, json.Number(tt.wantOnHold), resp["on_hold"])
+				assert.Equal(t, tt.wantTier, resp["tier"])
 			}
 		})
 	}
@@ -114,6 +126,17 @@ func TestBalanceHandler_Withdraw(t *testing.T) {
 			},
 			wantStatusCode: http.StatusPaymentRequired,
 		},
+		{
+			name:   "withdrawal limit exceeded",
+			userID: 1,
+			body:   map[string]any{"order": "79927398713", "sum": 5000.0},
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					Withdraw(gomock.Any(), int64(1), "79927398713", gomock.Any()).
+					Return(domain.ErrWithdrawalLimitExceeded)
+			},
+			wantStatusCode: http.StatusForbidden,
+		},
 		{
 			name:   "invalid order number",
 			userID: 1,
@@ -189,3 +212,454 @@ func TestBalanceHandler_Withdraw(t *testing.T) {
 		})
 	}
 }
+
+func TestBalanceHandler_Hold(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         int64
+		body           any
+		setup          func(*mocks.MockBalanceService)
+		wantStatusCode int
+	}{
+		{
+			name:   "success",
+			userID: 1,
+			body:   map[string]any{"order": "79927398713", "sum": 100.0},
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					Hold(gomock.Any(), int64(1), "79927398713", gomock.Any()).
+					Return(&domain.Hold{
+						ID:          1,
+						UserID:      1,
+						OrderNumber: "79927398713",
+						Amount:      decimal.NewFromInt(100),
+						Status:      domain.HoldStatusActive,
+						CreatedAt:   time.Now(),
+						ExpiresAt:   time.Now().Add(15 * time.Minute),
+					}, nil)
+			},
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name:   "insufficient balance",
+			userID: 1,
+			body:   map[string]any{"order": "79927398713", "sum": 1000.0},
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					Hold(gomock.Any(), int64(1), "79927398713", gomock.Any()).
+					Return(nil, domain.ErrInsufficientBalance)
+			},
+			wantStatusCode: http.StatusPaymentRequired,
+		},
+		{
+			name:   "withdrawal limit exceeded",
+			userID: 1,
+			body:   map[string]any{"order": "79927398713", "sum": 1000.0},
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					Hold(gomock.Any(), int64(1), "79927398713", gomock.Any()).
+					Return(nil, domain.ErrWithdrawalLimitExceeded)
+			},
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:   "invalid order number",
+			userID: 1,
+			body:   map[string]any{"order": "12345", "sum": 100.0},
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					Hold(gomock.Any(), int64(1), "12345", gomock.Any()).
+					Return(nil, domain.ErrInvalidOrderNumber)
+			},
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "zero sum",
+			userID:         1,
+			body:           map[string]any{"order": "79927398713", "sum": 0},
+			setup:          func(balanceService *mocks.MockBalanceService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "unauthorized",
+			userID:         0,
+			body:           map[string]any{"order": "79927398713", "sum": 100.0},
+			setup:          func(balanceService *mocks.MockBalanceService) {},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			balanceService := mocks.NewMockBalanceService(ctrl)
+			tt.setup(balanceService)
+
+			handler := handlers.NewBalanceHandler(balanceService)
+
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/api/user/balance/holds", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.userID > 0 {
+				ctx := context.WithValue(req.Context(), middleware.UserIDKey, tt.userID)
+				req = req.WithContext(ctx)
+			}
+
+			rr := httptest.NewRecorder()
+
+			handler.Hold(rr, req)
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+		})
+	}
+}
+
+func TestBalanceHandler_CaptureAndReleaseHold(t *testing.T) {
+	tests := []struct {
+		name           string
+		action         string
+		holdID         string
+		setup          func(*mocks.MockBalanceService)
+		wantStatusCode int
+	}{
+		{
+			name:   "capture success",
+			action: "capture",
+			holdID: "5",
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					CaptureHold(gomock.Any(), int64(5)).
+					Return(&domain.Withdrawal{
+						ID:          9,
+						UserID:      1,
+						OrderNumber: "79927398713",
+						Sum:         decimal.NewFromInt(100),
+						ProcessedAt: time.Now(),
+					}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "capture expired hold",
+			action: "capture",
+			holdID: "5",
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					CaptureHold(gomock.Any(), int64(5)).
+					Return(nil, domain.ErrHoldNotActive)
+			},
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name:   "capture over withdrawal limit",
+			action: "capture",
+			holdID: "5",
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					CaptureHold(gomock.Any(), int64(5)).
+					Return(nil, domain.ErrWithdrawalLimitExceeded)
+			},
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:   "release success",
+			action: "release",
+			holdID: "5",
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					ReleaseHold(gomock.Any(), int64(5)).
+					Return(&domain.Hold{ID: 5, UserID: 1, Status: domain.HoldStatusReleased}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "release unknown hold",
+			action: "release",
+			holdID: "42",
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					ReleaseHold(gomock.Any(), int64(42)).
+					Return(nil, domain.ErrHoldNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "invalid hold id",
+			action:         "capture",
+			holdID:         "abc",
+			setup:          func(balanceService *mocks.MockBalanceService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			balanceService := mocks.NewMockBalanceService(ctrl)
+			tt.setup(balanceService)
+
+			handler := handlers.NewBalanceHandler(balanceService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/holds/"+tt.holdID+"/"+tt.action, nil)
+
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("id", tt.holdID)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			if tt.action == "capture" {
+				handler.CaptureHold(rr, req)
+			} else {
+				handler.ReleaseHold(rr, req)
+			}
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+			if tt.wantStatusCode == http.StatusOK {
+				assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestBalanceHandler_Transfer(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         int64
+		body           any
+		setup          func(*mocks.MockBalanceService)
+		wantStatusCode int
+	}{
+		{
+			name:   "success",
+			userID: 1,
+			body:   map[string]any{"login": "friend", "sum": 50.0},
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					Transfer(gomock.Any(), int64(1), "friend", gomock.Any()).
+					Return(&domain.Transfer{
+						ID:         1,
+						FromUserID: 1,
+						ToUserID:   2,
+						Amount:     decimal.NewFromInt(50),
+						CreatedAt:  time.Now(),
+					}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "unknown recipient",
+			userID: 1,
+			body:   map[string]any{"login": "ghost", "sum": 50.0},
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					Transfer(gomock.Any(), int64(1), "ghost", gomock.Any()).
+					Return(nil, domain.ErrUserNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:   "transfer to self",
+			userID: 1,
+			body:   map[string]any{"login": "me", "sum": 50.0},
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					Transfer(gomock.Any(), int64(1), "me", gomock.Any()).
+					Return(nil, domain.ErrTransferToSelf)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "limit exceeded",
+			userID: 1,
+			body:   map[string]any{"login": "friend", "sum": 50000.0},
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					Transfer(gomock.Any(), int64(1), "friend", gomock.Any()).
+					Return(nil, domain.ErrTransferLimitExceeded)
+			},
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:   "insufficient balance",
+			userID: 1,
+			body:   map[string]any{"login": "friend", "sum": 500.0},
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					Transfer(gomock.Any(), int64(1), "friend", gomock.Any()).
+					Return(nil, domain.ErrInsufficientBalance)
+			},
+			wantStatusCode: http.StatusPaymentRequired,
+		},
+		{
+			name:           "empty login",
+			userID:         1,
+			body:           map[string]any{"sum": 50.0},
+			setup:          func(balanceService *mocks.MockBalanceService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "unauthorized",
+			userID:         0,
+			body:           map[string]any{"login": "friend", "sum": 50.0},
+			setup:          func(balanceService *mocks.MockBalanceService) {},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			balanceService := mocks.NewMockBalanceService(ctrl)
+			tt.setup(balanceService)
+
+			handler := handlers.NewBalanceHandler(balanceService)
+
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/api/user/balance/transfer", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.userID > 0 {
+				ctx := context.WithValue(req.Context(), middleware.UserIDKey, tt.userID)
+				req = req.WithContext(ctx)
+			}
+
+			rr := httptest.NewRecorder()
+
+			handler.Transfer(rr, req)
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+		})
+	}
+}
+
+func TestBalanceHandler_GetV2(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	balanceService := mocks.NewMockBalanceService(ctrl)
+	balanceService.EXPECT().
+		GetBalance(gomock.Any(), int64(1)).
+		Return(&domain.Balance{
+			UserID:    1,
+			Current:   decimal.RequireFromString("0.1").Add(decimal.RequireFromString("0.2")),
+			Withdrawn: decimal.RequireFromString("100"),
+			Tier:      domain.TierGold,
+		}, nil)
+
+	handler := handlers.NewBalanceHandler(balanceService)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/user/balance", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
+	rr := httptest.NewRecorder()
+
+	handler.GetV2(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"current":"0.3","withdrawn":"100","on_hold":"0","expiring_soon":"0","tier":"GOLD"}`, rr.Body.String())
+}
+
+func TestBalanceHandler_WithdrawV2(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         int64
+		body           string
+		setup          func(*mocks.MockBalanceService)
+		wantStatusCode int
+	}{
+		{
+			name:   "success keeps exact amount",
+			userID: 1,
+			body:   `{"order":"79927398713","sum":"0.30"}`,
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					Withdraw(gomock.Any(), int64(1), "79927398713", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int64, _ string, amount decimal.Decimal) error {
+						assert.True(t, decimal.RequireFromString("0.3").Equal(amount), amount.String())
+						return nil
+					})
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "insufficient balance",
+			userID: 1,
+			body:   `{"order":"79927398713","sum":"1000"}`,
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					Withdraw(gomock.Any(), int64(1), "79927398713", gomock.Any()).
+					Return(domain.ErrInsufficientBalance)
+			},
+			wantStatusCode: http.StatusPaymentRequired,
+		},
+		{
+			name:           "numeric sum rejected",
+			userID:         1,
+			body:           `{"order":"79927398713","sum":100}`,
+			setup:          func(balanceService *mocks.MockBalanceService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed sum",
+			userID:         1,
+			body:           `{"order":"79927398713","sum":"1,5"}`,
+			setup:          func(balanceService *mocks.MockBalanceService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "sum finer than kopecks",
+			userID:         1,
+			body:           `{"order":"79927398713","sum":"0.001"}`,
+			setup:          func(balanceService *mocks.MockBalanceService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "exponent sum finer than kopecks",
+			userID:         1,
+			body:           `{"order":"79927398713","sum":"1e-5"}`,
+			setup:          func(balanceService *mocks.MockBalanceService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "unauthorized",
+			userID:         0,
+			body:           `{"order":"79927398713","sum":"100"}`,
+			setup:          func(balanceService *mocks.MockBalanceService) {},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			balanceService := mocks.NewMockBalanceService(ctrl)
+			tt.setup(balanceService)
+
+			handler := handlers.NewBalanceHandler(balanceService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v2/user/balance/withdraw", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.userID > 0 {
+				ctx := context.WithValue(req.Context(), middleware.UserIDKey, tt.userID)
+				req = req.WithContext(ctx)
+			}
+
+			rr := httptest.NewRecorder()
+
+			handler.WithdrawV2(rr, req)
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/arvaliullin/gophermart/internal/api/http/dto"
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/go-chi/chi/v5"
+)
+
+// DeadLetterHandler обрабатывает административные запросы к заказам,
+// снятым с опроса системы начислений.
+type DeadLetterHandler struct {
+	deadLetterService ports.DeadLetterService
+}
+
+// NewDeadLetterHandler создаёт новый обработчик снятых с опроса заказов.
+func NewDeadLetterHandler(deadLetterService ports.DeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		deadLetterService: deadLetterService,
+	}
+}
+
+// List возвращает снятые с опроса заказы. Поддерживает параметры
+// постраничной выборки limit, cursor, sort, from и to.
+func (h *DeadLetterHandler) List(w http.ResponseWriter, r *http.Request) {
+	query, err := parseListQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.deadLetterService.List(r.Context(), query)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidListQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(page.Orders) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	setNextPageHeaders(w, r, page.Next)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromDomainDeadLetterOrders(page.Orders))
+}
+
+// Requeue возвращает снятый с опроса заказ в опрос системы начислений.
+func (h *DeadLetterHandler) Requeue(w http.ResponseWriter, r *http.Request) {
+	number := chi.URLParam(r, "number")
+	if number == "" {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.deadLetterService.Requeue(r.Context(), number)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromDomainOrder(order))
+}
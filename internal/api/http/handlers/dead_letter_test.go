@@ -0,0 +1,172 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/api/http/handlers"
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDeadLetterHandler_List(t *testing.T) {
+	uploadedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	deadLetteredAt := uploadedAt.Add(7 * 24 * time.Hour)
+	next := &domain.PageCursor{Time: deadLetteredAt, ID: 1}
+
+	tests := []struct {
+		name           string
+		target         string
+		setup          func(*mocks.MockDeadLetterService)
+		wantStatusCode int
+		wantBody       string
+		wantNext       bool
+	}{
+		{
+			name:   "dead-lettered orders",
+			target: "/api/admin/orders/dead-letter?limit=1",
+			setup: func(service *mocks.MockDeadLetterService) {
+				service.EXPECT().
+					List(gomock.Any(), domain.ListQuery{Limit: 1}).
+					Return(&domain.DeadLetterPage{
+						Orders: []*domain.DeadLetterOrder{{
+							Order: &domain.Order{
+								ID: 1, UserID: 7, Number: "12345678903", Status: domain.OrderStatusNew,
+								UploadedAt: uploadedAt, Attempts: 42,
+							},
+							LastError:      "заказ не зарегистрирован в системе начислений",
+							DeadLetteredAt: deadLetteredAt,
+						}},
+						Next: next,
+					}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `"number":"12345678903","status":"NEW","uploaded_at":"2024-01-15T10:30:00Z","user_id":7,"attempts":42,"last_error":"заказ не зарегистрирован в системе начислений","dead_lettered_at":"2024-01-22T10:30:00Z"`,
+			wantNext:       true,
+		},
+		{
+			name:   "no dead-lettered orders",
+			target: "/api/admin/orders/dead-letter",
+			setup: func(service *mocks.MockDeadLetterService) {
+				service.EXPECT().
+					List(gomock.Any(), domain.ListQuery{}).
+					Return(&domain.DeadLetterPage{}, nil)
+			},
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:           "malformed query",
+			target:         "/api/admin/orders/dead-letter?limit=abc",
+			setup:          func(*mocks.MockDeadLetterService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid query",
+			target: "/api/admin/orders/dead-letter?limit=1000",
+			setup: func(service *mocks.MockDeadLetterService) {
+				service.EXPECT().
+					List(gomock.Any(), domain.ListQuery{Limit: 1000}).
+					Return(nil, domain.ErrInvalidListQuery)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "internal error",
+			target: "/api/admin/orders/dead-letter",
+			setup: func(service *mocks.MockDeadLetterService) {
+				service.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("db error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mocks.NewMockDeadLetterService(ctrl)
+			tt.setup(service)
+
+			handler := handlers.NewDeadLetterHandler(service)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			rr := httptest.NewRecorder()
+
+			handler.List(rr, req)
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+			if tt.wantBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.wantBody)
+			}
+			if tt.wantNext {
+				assert.Equal(t, next.String(), rr.Header().Get(handlers.NextCursorHeader))
+			}
+		})
+	}
+}
+
+func TestDeadLetterHandler_Requeue(t *testing.T) {
+	uploadedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		order          *domain.Order
+		err            error
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name:           "requeued",
+			order:          &domain.Order{ID: 1, UserID: 7, Number: "12345678903", Status: domain.OrderStatusNew, UploadedAt: uploadedAt},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"number":"12345678903","status":"NEW","uploaded_at":"2024-01-15T10:30:00Z"}`,
+		},
+		{
+			name:           "order not dead-lettered",
+			err:            domain.ErrOrderNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "internal error",
+			err:            errors.New("db error"),
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mocks.NewMockDeadLetterService(ctrl)
+			service.EXPECT().
+				Requeue(gomock.Any(), "12345678903").
+				Return(tt.order, tt.err)
+
+			handler := handlers.NewDeadLetterHandler(service)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/orders/12345678903/requeue", nil)
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("number", "12345678903")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+			rr := httptest.NewRecorder()
+
+			handler.Requeue(rr, req)
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
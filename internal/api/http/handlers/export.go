@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/arvaliullin/gophermart/internal/api/http/dto"
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+)
+
+// Форматы выгрузки истории, выбираемые по заголовку Accept.
+const (
+	ContentTypeCSV    = "text/csv"
+	ContentTypeNDJSON = "application/x-ndjson"
+)
+
+// exportEncoder записывает выгрузку в выбранном формате.
+// Written сообщает, была ли передана хотя бы одна запись.
+type exportEncoder interface {
+	WriteOrder(order *domain.Order) error
+	WriteWithdrawal(withdrawal *domain.Withdrawal) error
+	Written() bool
+	Flush() error
+}
+
+// negotiateExportFormat выбирает формат выгрузки по заголовку Accept.
+// Без заголовка или при произвольном типе выгрузка выполняется в CSV.
+func negotiateExportFormat(accept string) (string, bool) {
+	if accept == "" {
+		return ContentTypeCSV, true
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case ContentTypeCSV, "text/*", "*/*":
+			return ContentTypeCSV, true
+		case ContentTypeNDJSON, "application/ndjson":
+			return ContentTypeNDJSON, true
+		}
+	}
+
+	return "", false
+}
+
+func newExportEncoder(contentType string, w io.Writer) exportEncoder {
+	if contentType == ContentTypeNDJSON {
+		return &ndjsonExportEncoder{enc: json.NewEncoder(w)}
+	}
+	return &csvExportEncoder{w: csv.NewWriter(w)}
+}
+
+type csvExportEncoder struct {
+	w       *csv.Writer
+	written bool
+}
+
+func (e *csvExportEncoder) WriteOrder(order *domain.Order) error {
+	return e.write(dto.ExportRecordFromOrder(order))
+}
+
+func (e *csvExportEncoder) WriteWithdrawal(withdrawal *domain.Withdrawal) error {
+	return e.write(dto.ExportRecordFromWithdrawal(withdrawal))
+}
+
+func (e *csvExportEncoder) write(record *dto.ExportRecord) error {
+	if !e.written {
+		if err := e.w.Write(dto.ExportCSVHeader); err != nil {
+			return err
+		}
+		e.written = true
+	}
+	return e.w.Write(record.CSV())
+}
+
+func (e *csvExportEncoder) Written() bool {
+	return e.written
+}
+
+// Flush дописывает заголовок, если записей не было, и сбрасывает буфер.
+func (e *csvExportEncoder) Flush() error {
+	if !e.written {
+		if err := e.w.Write(dto.ExportCSVHeader); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type ndjsonExportEncoder struct {
+	enc     *json.Encoder
+	written bool
+}
+
+func (e *ndjsonExportEncoder) WriteOrder(order *domain.Order) error {
+	e.written = true
+	return e.enc.Encode(dto.ExportRecordFromOrder(order))
+}
+
+func (e *ndjsonExportEncoder) WriteWithdrawal(withdrawal *domain.Withdrawal) error {
+	e.written = true
+	return e.enc.Encode(dto.ExportRecordFromWithdrawal(withdrawal))
+}
+
+func (e *ndjsonExportEncoder) Written() bool {
+	return e.written
+}
+
+func (e *ndjsonExportEncoder) Flush() error {
+	return nil
+}
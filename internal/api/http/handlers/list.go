@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+)
+
+// NextCursorHeader содержит курсор следующей страницы списка.
+const NextCursorHeader = "X-Next-Cursor"
+
+// listParams перечисляет параметры запроса, включающие постраничную выборку.
+var listParams = []string{"limit", "cursor", "sort", "from", "to"}
+
+var errInvalidSort = errors.New("неверный порядок сортировки")
+
+// isListRequest сообщает, задан ли в запросе хотя бы один параметр выборки.
+// Без них списки возвращаются целиком, как раньше.
+func isListRequest(values url.Values, extra ...string) bool {
+	for _, name := range append(listParams, extra...) {
+		if values.Has(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseListQuery разбирает параметры постраничной выборки: limit, cursor,
+// sort (asc или desc) и период from, to в формате RFC3339.
+func parseListQuery(values url.Values) (domain.ListQuery, error) {
+	var query domain.ListQuery
+
+	from, to, err := parsePeriod(values)
+	if err != nil {
+		return query, err
+	}
+	query.From = from
+	query.To = to
+
+	if raw := values.Get("cursor"); raw != "" {
+		query.Cursor, err = domain.ParsePageCursor(raw)
+		if err != nil {
+			return query, err
+		}
+	}
+
+	if raw := values.Get("limit"); raw != "" {
+		query.Limit, err = strconv.Atoi(raw)
+		if err != nil {
+			return query, err
+		}
+	}
+
+	switch values.Get("sort") {
+	case "", "desc":
+	case "asc":
+		query.Ascending = true
+	default:
+		return query, errInvalidSort
+	}
+
+	return query, nil
+}
+
+// parseOrderListQuery дополнительно разбирает фильтр status: список статусов
+// заказа через запятую.
+func parseOrderListQuery(values url.Values) (domain.OrderListQuery, error) {
+	listQuery, err := parseListQuery(values)
+	if err != nil {
+		return domain.OrderListQuery{}, err
+	}
+
+	query := domain.OrderListQuery{ListQuery: listQuery}
+	if raw := values.Get("status"); raw != "" {
+		for _, status := range strings.Split(raw, ",") {
+			query.Statuses = append(query.Statuses, domain.OrderStatus(strings.ToUpper(strings.TrimSpace(status))))
+		}
+	}
+
+	return query, nil
+}
+
+// setNextPageHeaders сообщает клиенту курсор следующей страницы в заголовке
+// X-Next-Cursor и ссылку на неё в заголовке Link.
+func setNextPageHeaders(w http.ResponseWriter, r *http.Request, next *domain.PageCursor) {
+	if next == nil {
+		return
+	}
+
+	cursor := next.String()
+	values := r.URL.Query()
+	values.Set("cursor", cursor)
+	link := url.URL{Path: r.URL.Path, RawQuery: values.Encode()}
+
+	w.Header().Set(NextCursorHeader, cursor)
+	w.Header().Set("Link", "<"+link.String()+`>; rel="next"`)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/arvaliullin/gophermart/internal/api/http/middleware"
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/go-chi/chi/v5"
 )
 
 // OrderHandler обрабатывает HTTP запросы управления заказами.
@@ -67,16 +68,88 @@ func (h *OrderHandler) Submit(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusAccepted)
 }
 
+// SubmitBatch обрабатывает пакетную загрузку номеров заказов.
+//
+// Номера принимаются JSON-массивом строк при Content-Type application/json
+// или текстом по одному номеру в строке. В ответе возвращается результат
+// по каждому номеру.
+func (h *OrderHandler) SubmitBatch(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		http.Error(w, "пользователь не авторизован", http.StatusUnauthorized)
+		return
+	}
+
+	numbers, err := parseOrderNumbers(r)
+	if err != nil {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	submissions, err := h.orderService.SubmitOrders(r.Context(), userID, numbers)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidOrderBatch) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromDomainOrderSubmissions(submissions))
+}
+
 // List возвращает список заказов пользователя.
+//
+// Параметры limit, cursor, sort, from, to и status включают постраничную
+// выборку; курсор следующей страницы возвращается в заголовках X-Next-Cursor и Link.
 func (h *OrderHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, func(orders []*domain.Order) any {
+		return dto.FromDomainOrders(orders)
+	})
+}
+
+// ListV2 возвращает список заказов пользователя с начислениями в виде десятичных строк.
+func (h *OrderHandler) ListV2(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, func(orders []*domain.Order) any {
+		return dto.FromDomainOrdersV2(orders)
+	})
+}
+
+func (h *OrderHandler) list(w http.ResponseWriter, r *http.Request, toResponse func([]*domain.Order) any) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == 0 {
 		http.Error(w, "пользователь не авторизован", http.StatusUnauthorized)
 		return
 	}
 
-	orders, err := h.orderService.GetUserOrders(r.Context(), userID)
+	var (
+		orders []*domain.Order
+		next   *domain.PageCursor
+		err    error
+	)
+	if values := r.URL.Query(); isListRequest(values, "status") {
+		query, parseErr := parseOrderListQuery(values)
+		if parseErr != nil {
+			http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var page *domain.OrderPage
+		page, err = h.orderService.ListOrders(r.Context(), userID, query)
+		if page != nil {
+			orders, next = page.Orders, page.Next
+		}
+	} else {
+		orders, err = h.orderService.GetUserOrders(r.Context(), userID)
+	}
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidListQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -86,7 +159,98 @@ func (h *OrderHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setNextPageHeaders(w, r, next)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toResponse(orders))
+}
+
+// Get возвращает заказ пользователя по номеру с историей его статусов
+// и списаниями, оформленными на этот номер. Чужой или несуществующий
+// заказ отвечает 404.
+func (h *OrderHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		http.Error(w, "пользователь не авторизован", http.StatusUnauthorized)
+		return
+	}
+
+	number := chi.URLParam(r, "number")
+	if number == "" {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	details, err := h.orderService.GetOrder(r.Context(), userID, number)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dto.FromDomainOrders(orders))
+	json.NewEncoder(w).Encode(dto.FromDomainOrderDetails(details))
+}
+
+// Cancel отменяет заказ пользователя в статусе NEW и возвращает его.
+// Отменённый номер можно загрузить повторно.
+func (h *OrderHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		http.Error(w, "пользователь не авторизован", http.StatusUnauthorized)
+		return
+	}
+
+	number := chi.URLParam(r, "number")
+	if number == "" {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.orderService.CancelOrder(r.Context(), userID, number)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, domain.ErrOrderNotCancelable) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromDomainOrder(order))
+}
+
+func parseOrderNumbers(r *http.Request) ([]string, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var numbers []string
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.Unmarshal(body, &numbers); err != nil {
+			return nil, err
+		}
+		for i, number := range numbers {
+			numbers[i] = strings.TrimSpace(number)
+		}
+		return numbers, nil
+	}
+
+	for _, line := range strings.Split(string(body), "\n") {
+		if number := strings.TrimSpace(line); number != "" {
+			numbers = append(numbers, number)
+		}
+	}
+	return numbers, nil
 }
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/arvaliullin/gophermart/internal/api/http/dto"
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/arvaliullin/gophermart/internal/core/services/accrual"
+	"github.com/go-chi/chi/v5"
+)
+
+// OrderRecheckHandler обрабатывает административные запросы перепроверки заказов.
+type OrderRecheckHandler struct {
+	recheckService ports.OrderRecheckService
+}
+
+// NewOrderRecheckHandler создаёт новый обработчик перепроверки заказов.
+func NewOrderRecheckHandler(recheckService ports.OrderRecheckService) *OrderRecheckHandler {
+	return &OrderRecheckHandler{
+		recheckService: recheckService,
+	}
+}
+
+// Recheck повторно запрашивает заказ в системе начислений и применяет
+// изменение начисления к балансу владельца заказа. Если заказ уже
+// обрабатывается или расчёт по нему не завершён, возвращается 409. Если
+// запрос превысил бы лимит частоты системы начислений, сразу возвращается
+// 503 с заголовком Retry-After.
+func (h *OrderRecheckHandler) Recheck(w http.ResponseWriter, r *http.Request) {
+	number := chi.URLParam(r, "number")
+	if number == "" {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	recheck, err := h.recheckService.Recheck(r.Context(), number)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, domain.ErrOrderLocked) || errors.Is(err, domain.ErrAccrualNotFinal) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, domain.ErrAccrualRateLimited) {
+			var retryErr *accrual.RetryAfterError
+			if errors.As(err, &retryErr) {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryErr.Duration.Seconds()))))
+			}
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		if errors.Is(err, domain.ErrAccrualUnavailable) {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromDomainOrderRecheck(recheck))
+}
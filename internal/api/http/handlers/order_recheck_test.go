@@ -0,0 +1,109 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/api/http/handlers"
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
+	accrualservice "github.com/arvaliullin/gophermart/internal/core/services/accrual"
+	"github.com/go-chi/chi/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOrderRecheckHandler_Recheck(t *testing.T) {
+	uploadedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	accrual := decimal.NewFromInt(200)
+
+	tests := []struct {
+		name           string
+		result         *domain.OrderRecheck
+		err            error
+		wantStatusCode int
+		wantBody       string
+		wantRetryAfter string
+	}{
+		{
+			name: "accrual corrected",
+			result: &domain.OrderRecheck{
+				Previous: &domain.Order{UserID: 7, Number: "12345678903", Status: domain.OrderStatusInvalid, UploadedAt: uploadedAt},
+				Order:    &domain.Order{UserID: 7, Number: "12345678903", Status: domain.OrderStatusProcessed, Accrual: &accrual, UploadedAt: uploadedAt},
+				Correction: &domain.LedgerEntry{
+					ID: 10, Kind: domain.LedgerEntryAccrualCorrection, Amount: accrual,
+					Description: "перепроверка заказа", CreatedAt: uploadedAt.Add(time.Hour),
+				},
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `"correction":{"id":10,"kind":"ACCRUAL_CORRECTION","amount":200,`,
+		},
+		{
+			name:           "order not found",
+			err:            domain.ErrOrderNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "order locked",
+			err:            domain.ErrOrderLocked,
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name:           "accrual not final",
+			err:            domain.ErrAccrualNotFinal,
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name: "accrual rate limited",
+			err: fmt.Errorf("%w: %w", domain.ErrAccrualRateLimited,
+				&accrualservice.RetryAfterError{Duration: 1500 * time.Millisecond}),
+			wantStatusCode: http.StatusServiceUnavailable,
+			wantRetryAfter: "2",
+		},
+		{
+			name:           "accrual system unavailable",
+			err:            domain.ErrAccrualUnavailable,
+			wantStatusCode: http.StatusBadGateway,
+		},
+		{
+			name:           "internal error",
+			err:            errors.New("db error"),
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			recheckService := mocks.NewMockOrderRecheckService(ctrl)
+			recheckService.EXPECT().
+				Recheck(gomock.Any(), "12345678903").
+				Return(tt.result, tt.err)
+
+			handler := handlers.NewOrderRecheckHandler(recheckService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/orders/12345678903/recheck", nil)
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("number", "12345678903")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+			rr := httptest.NewRecorder()
+
+			handler.Recheck(rr, req)
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+			assert.Equal(t, tt.wantRetryAfter, rr.Header().Get("Retry-After"))
+			if tt.wantBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.wantBody)
+				assert.Contains(t, rr.Body.String(), `"previous":{"number":"12345678903","status":"INVALID"`)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package handlers_test
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -12,6 +13,8 @@ import (
 	"github.com/arvaliullin/gophermart/internal/api/http/middleware"
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
+	"github.com/arvaliullin/gophermart/internal/core/services/ordernumber"
+	"github.com/go-chi/chi/v5"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -112,6 +115,53 @@ func TestOrderHandler_Submit(t *testing.T) {
 	}
 }
 
+func TestOrderHandler_Submit_InvalidNumberReason(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderService := mocks.NewMockOrderService(ctrl)
+	orderService.EXPECT().
+		SubmitOrder(gomock.Any(), int64(1), "12345678901").
+		Return(false, fmt.Errorf("%w: %w (luhn)", domain.ErrInvalidOrderNumber, ordernumber.ErrChecksumMismatch))
+
+	handler := handlers.NewOrderHandler(orderService)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/orders", strings.NewReader("12345678901"))
+	req.Header.Set("Content-Type", "text/plain")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
+
+	rr := httptest.NewRecorder()
+
+	handler.Submit(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, rr.Body.String(), ordernumber.ErrChecksumMismatch.Error())
+	assert.Contains(t, rr.Body.String(), "luhn")
+}
+
+func TestOrderHandler_Submit_PrefixReason(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderService := mocks.NewMockOrderService(ctrl)
+	orderService.EXPECT().
+		SubmitOrder(gomock.Any(), int64(1), "79927398713").
+		Return(false, fmt.Errorf("%w: %w (57)", domain.ErrInvalidOrderNumber, ordernumber.ErrPrefixMismatch))
+
+	handler := handlers.NewOrderHandler(orderService)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/orders", strings.NewReader("79927398713"))
+	req.Header.Set("Content-Type", "text/plain")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
+
+	rr := httptest.NewRecorder()
+
+	handler.Submit(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, rr.Body.String(), ordernumber.ErrPrefixMismatch.Error())
+}
+
 func TestOrderHandler_List(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -190,3 +240,376 @@ func TestOrderHandler_List(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderHandler_ListV2(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accrual := decimal.RequireFromString("729.98")
+	orderService := mocks.NewMockOrderService(ctrl)
+	orderService.EXPECT().
+		GetUserOrders(gomock.Any(), int64(1)).
+		Return([]*domain.Order{
+			{
+				ID:         1,
+				UserID:     1,
+				Number:     "12345678903",
+				Status:     domain.OrderStatusProcessed,
+				Accrual:    &accrual,
+				UploadedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			},
+		}, nil)
+
+	handler := handlers.NewOrderHandler(orderService)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/user/orders", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
+	rr := httptest.NewRecorder()
+
+	handler.ListV2(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t,
+		`[{"number":"12345678903","status":"PROCESSED","accrual":"729.98","uploaded_at":"2024-01-15T10:30:00Z"}]`,
+		rr.Body.String())
+}
+
+func TestOrderHandler_SubmitBatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         int64
+		contentType    string
+		body           string
+		setup          func(*mocks.MockOrderService)
+		wantStatusCode int
+	}{
+		{
+			name:        "json array",
+			userID:      1,
+			contentType: "application/json",
+			body:        `["12345678903", " 79927398713 "]`,
+			setup: func(orderService *mocks.MockOrderService) {
+				orderService.EXPECT().
+					SubmitOrders(gomock.Any(), int64(1), []string{"12345678903", "79927398713"}).
+					Return([]*domain.OrderSubmission{
+						{Number: "12345678903", Result: domain.OrderSubmitAccepted},
+						{Number: "79927398713", Result: domain.OrderSubmitBelongsToOther},
+					}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:        "newline separated text",
+			userID:      1,
+			contentType: "text/plain",
+			body:        "12345678903\r\n\n79927398713\n",
+			setup: func(orderService *mocks.MockOrderService) {
+				orderService.EXPECT().
+					SubmitOrders(gomock.Any(), int64(1), []string{"12345678903", "79927398713"}).
+					Return([]*domain.OrderSubmission{
+						{Number: "12345678903", Result: domain.OrderSubmitAccepted},
+						{Number: "79927398713", Result: domain.OrderSubmitBelongsToOther},
+					}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "malformed json",
+			userID:         1,
+			contentType:    "application/json",
+			body:           `{"orders": 1}`,
+			setup:          func(orderService *mocks.MockOrderService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "empty batch",
+			userID:      1,
+			contentType: "text/plain",
+			body:        "",
+			setup: func(orderService *mocks.MockOrderService) {
+				orderService.EXPECT().
+					SubmitOrders(gomock.Any(), int64(1), gomock.Nil()).
+					Return(nil, domain.ErrInvalidOrderBatch)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "unauthorized",
+			userID:         0,
+			contentType:    "text/plain",
+			body:           "12345678903",
+			setup:          func(orderService *mocks.MockOrderService) {},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			orderService := mocks.NewMockOrderService(ctrl)
+			tt.setup(orderService)
+
+			handler := handlers.NewOrderHandler(orderService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/orders/batch", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			if tt.userID > 0 {
+				ctx := context.WithValue(req.Context(), middleware.UserIDKey, tt.userID)
+				req = req.WithContext(ctx)
+			}
+
+			rr := httptest.NewRecorder()
+
+			handler.SubmitBatch(rr, req)
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+			if tt.wantStatusCode == http.StatusOK {
+				assert.JSONEq(t,
+					`[{"number":"12345678903","result":"ACCEPTED"},{"number":"79927398713","result":"BELONGS_TO_OTHER"}]`,
+					rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestOrderHandler_List_Paginated(t *testing.T) {
+	next := &domain.PageCursor{Time: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), ID: 5}
+
+	tests := []struct {
+		name           string
+		target         string
+		setup          func(*mocks.MockOrderService)
+		wantStatusCode int
+		wantNext       bool
+	}{
+		{
+			name:   "first page with filters",
+			target: "/api/user/orders?limit=1&status=new,processed&sort=asc&from=2024-01-01T00:00:00Z",
+			setup: func(orderService *mocks.MockOrderService) {
+				orderService.EXPECT().
+					ListOrders(gomock.Any(), int64(1), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int64, query domain.OrderListQuery) (*domain.OrderPage, error) {
+						assert.Equal(t, 1, query.Limit)
+						assert.True(t, query.Ascending)
+						assert.NotNil(t, query.From)
+						assert.Nil(t, query.Cursor)
+						assert.Equal(t, []domain.OrderStatus{domain.OrderStatusNew, domain.OrderStatusProcessed}, query.Statuses)
+						return &domain.OrderPage{
+							Orders: []*domain.Order{{ID: 5, Number: "12345678903", Status: domain.OrderStatusNew}},
+							Next:   next,
+						}, nil
+					})
+			},
+			wantStatusCode: http.StatusOK,
+			wantNext:       true,
+		},
+		{
+			name:   "next page by cursor",
+			target: "/api/user/orders?limit=1&cursor=" + next.String(),
+			setup: func(orderService *mocks.MockOrderService) {
+				orderService.EXPECT().
+					ListOrders(gomock.Any(), int64(1), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int64, query domain.OrderListQuery) (*domain.OrderPage, error) {
+						if assert.NotNil(t, query.Cursor) {
+							assert.Equal(t, next.ID, query.Cursor.ID)
+						}
+						return &domain.OrderPage{
+							Orders: []*domain.Order{{ID: 4, Number: "79927398713", Status: domain.OrderStatusNew}},
+						}, nil
+					})
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invalid cursor",
+			target:         "/api/user/orders?cursor=broken!",
+			setup:          func(orderService *mocks.MockOrderService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid sort",
+			target:         "/api/user/orders?sort=sideways",
+			setup:          func(orderService *mocks.MockOrderService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid query",
+			target: "/api/user/orders?status=done",
+			setup: func(orderService *mocks.MockOrderService) {
+				orderService.EXPECT().
+					ListOrders(gomock.Any(), int64(1), gomock.Any()).
+					Return(nil, domain.ErrInvalidListQuery)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			orderService := mocks.NewMockOrderService(ctrl)
+			tt.setup(orderService)
+
+			handler := handlers.NewOrderHandler(orderService)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
+			rr := httptest.NewRecorder()
+
+			handler.List(rr, req)
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+			if tt.wantNext {
+				assert.Equal(t, next.String(), rr.Header().Get(handlers.NextCursorHeader))
+				assert.Contains(t, rr.Header().Get("Link"), "cursor="+next.String())
+				assert.Contains(t, rr.Header().Get("Link"), `rel="next"`)
+			} else {
+				assert.Empty(t, rr.Header().Get(handlers.NextCursorHeader))
+				assert.Empty(t, rr.Header().Get("Link"))
+			}
+		})
+	}
+}
+
+func TestOrderHandler_Get(t *testing.T) {
+	uploadedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	accrual := decimal.NewFromFloat(500)
+
+	tests := []struct {
+		name           string
+		userID         int64
+		setup          func(*mocks.MockOrderService)
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name:   "order with history",
+			userID: 1,
+			setup: func(orderService *mocks.MockOrderService) {
+				orderService.EXPECT().
+					GetOrder(gomock.Any(), int64(1), "12345678903").
+					Return(&domain.OrderDetails{
+						Order: &domain.Order{Number: "12345678903", Status: domain.OrderStatusProcessed, Accrual: &accrual, UploadedAt: uploadedAt},
+						History: []*domain.OrderStatusChange{
+							{Status: domain.OrderStatusNew, ChangedAt: uploadedAt},
+							{Status: domain.OrderStatusProcessed, Accrual: &accrual, ChangedAt: uploadedAt.Add(time.Minute)},
+						},
+					}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `"history":[{"status":"NEW","changed_at":"2024-01-15T10:30:00Z"},{"status":"PROCESSED","accrual":500,"changed_at":"2024-01-15T10:31:00Z"}],"withdrawals":[]`,
+		},
+		{
+			name:   "order with withdrawals",
+			userID: 1,
+			setup: func(orderService *mocks.MockOrderService) {
+				orderService.EXPECT().
+					GetOrder(gomock.Any(), int64(1), "12345678903").
+					Return(&domain.OrderDetails{
+						Order: &domain.Order{Number: "12345678903", Status: domain.OrderStatusNew, UploadedAt: uploadedAt},
+						Withdrawals: []*domain.Withdrawal{
+							{ID: 3, OrderNumber: "12345678903", Sum: decimal.NewFromInt(50), ProcessedAt: uploadedAt.Add(time.Hour)},
+						},
+					}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `"withdrawals":[{"id":3,"order":"12345678903","sum":50,`,
+		},
+		{
+			name:   "order not found",
+			userID: 1,
+			setup: func(orderService *mocks.MockOrderService) {
+				orderService.EXPECT().
+					GetOrder(gomock.Any(), int64(1), "12345678903").
+					Return(nil, domain.ErrOrderNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "unauthorized",
+			userID:         0,
+			setup:          func(orderService *mocks.MockOrderService) {},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			orderService := mocks.NewMockOrderService(ctrl)
+			tt.setup(orderService)
+
+			handler := handlers.NewOrderHandler(orderService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/orders/12345678903", nil)
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("number", "12345678903")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)
+			if tt.userID != 0 {
+				ctx = context.WithValue(ctx, middleware.UserIDKey, tt.userID)
+			}
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+
+			handler.Get(rr, req)
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+			if tt.wantBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestOrderHandler_Cancel(t *testing.T) {
+	uploadedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		err            error
+		wantStatusCode int
+	}{
+		{name: "canceled", wantStatusCode: http.StatusOK},
+		{name: "not found", err: domain.ErrOrderNotFound, wantStatusCode: http.StatusNotFound},
+		{name: "already processing", err: domain.ErrOrderNotCancelable, wantStatusCode: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var canceled *domain.Order
+			if tt.err == nil {
+				canceled = &domain.Order{Number: "12345678903", Status: domain.OrderStatusCanceled, UploadedAt: uploadedAt}
+			}
+			orderService := mocks.NewMockOrderService(ctrl)
+			orderService.EXPECT().
+				CancelOrder(gomock.Any(), int64(1), "12345678903").
+				Return(canceled, tt.err)
+
+			handler := handlers.NewOrderHandler(orderService)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/user/orders/12345678903", nil)
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("number", "12345678903")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)
+			req = req.WithContext(context.WithValue(ctx, middleware.UserIDKey, int64(1)))
+			rr := httptest.NewRecorder()
+
+			handler.Cancel(rr, req)
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+			if tt.err == nil {
+				assert.Contains(t, rr.Body.String(), `"status":"CANCELED"`)
+			}
+		})
+	}
+}
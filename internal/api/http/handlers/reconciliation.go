@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/arvaliullin/gophermart/internal/api/http/dto"
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+)
+
+// ReconciliationHandler обрабатывает административные запросы сверки балансов.
+type ReconciliationHandler struct {
+	reconciliationService ports.ReconciliationService
+}
+
+// NewReconciliationHandler создаёт новый обработчик сверки балансов.
+func NewReconciliationHandler(reconciliationService ports.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		reconciliationService: reconciliationService,
+	}
+}
+
+// Report выполняет сверку и возвращает найденные расхождения.
+func (h *ReconciliationHandler) Report(w http.ResponseWriter, r *http.Request) {
+	report, err := h.reconciliationService.Report(r.Context())
+	writeReconciliationReport(w, report, err)
+}
+
+// Repair выполняет сверку и исправляет расхождения корректирующими проводками.
+func (h *ReconciliationHandler) Repair(w http.ResponseWriter, r *http.Request) {
+	report, err := h.reconciliationService.Repair(r.Context())
+	writeReconciliationReport(w, report, err)
+}
+
+func writeReconciliationReport(w http.ResponseWriter, report *domain.ReconciliationReport, err error) {
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromDomainReconciliationReport(report))
+}
@@ -0,0 +1,99 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/api/http/dto"
+	"github.com/arvaliullin/gophermart/internal/api/http/handlers"
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReconciliationHandler(t *testing.T) {
+	report := &domain.ReconciliationReport{
+		CheckedAt: time.Now(),
+		Mismatches: []*domain.BalanceMismatch{
+			{
+				UserID:            7,
+				ExpectedCurrent:   decimal.NewFromInt(100),
+				ActualCurrent:     decimal.Zero,
+				ExpectedWithdrawn: decimal.Zero,
+				ActualWithdrawn:   decimal.Zero,
+				Adjustment:        &domain.LedgerEntry{ID: 42},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		setup          func(*mocks.MockReconciliationService)
+		wantStatusCode int
+	}{
+		{
+			name:   "report",
+			method: http.MethodGet,
+			setup: func(service *mocks.MockReconciliationService) {
+				service.EXPECT().Report(gomock.Any()).Return(report, nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "repair",
+			method: http.MethodPost,
+			setup: func(service *mocks.MockReconciliationService) {
+				service.EXPECT().Repair(gomock.Any()).Return(report, nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "service error",
+			method: http.MethodGet,
+			setup: func(service *mocks.MockReconciliationService) {
+				service.EXPECT().Report(gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mocks.NewMockReconciliationService(ctrl)
+			tt.setup(service)
+
+			handler := handlers.NewReconciliationHandler(service)
+
+			req := httptest.NewRequest(tt.method, "/api/admin/reconciliation", nil)
+			rr := httptest.NewRecorder()
+
+			if tt.method == http.MethodPost {
+				handler.Repair(rr, req)
+			} else {
+				handler.Report(rr, req)
+			}
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+
+			if tt.wantStatusCode == http.StatusOK {
+				var response dto.ReconciliationResponse
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+				require.Len(t, response.Mismatches, 1)
+				assert.Equal(t, int64(7), response.Mismatches[0].UserID)
+				assert.Equal(t, 100.0, response.Mismatches[0].ExpectedCurrent)
+				assert.Equal(t, int64(42), response.Mismatches[0].AdjustmentID)
+			}
+		})
+	}
+}
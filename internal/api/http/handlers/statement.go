@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/api/http/dto"
+	"github.com/arvaliullin/gophermart/internal/api/http/middleware"
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+)
+
+// StatementHandler обрабатывает HTTP запросы выписки по счёту.
+type StatementHandler struct {
+	statementService ports.StatementService
+}
+
+// NewStatementHandler создаёт новый обработчик выписки.
+func NewStatementHandler(statementService ports.StatementService) *StatementHandler {
+	return &StatementHandler{
+		statementService: statementService,
+	}
+}
+
+// Get возвращает выписку по счёту пользователя.
+//
+// Параметры запроса: from и to в формате RFC3339 задают период,
+// cursor — значение next_cursor предыдущей страницы, limit — размер страницы.
+func (h *StatementHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		http.Error(w, "пользователь не авторизован", http.StatusUnauthorized)
+		return
+	}
+
+	query, err := parseStatementQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	statement, err := h.statementService.GetStatement(r.Context(), userID, query)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidStatementQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(statement.Entries) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromDomainStatement(statement))
+}
+
+// Export выгружает заказы и списания пользователя за период в CSV или NDJSON.
+//
+// Формат выбирается по заголовку Accept, период задаётся параметрами from и to
+// в формате RFC3339. Записи передаются клиенту по мере чтения из хранилища.
+func (h *StatementHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		http.Error(w, "пользователь не авторизован", http.StatusUnauthorized)
+		return
+	}
+
+	contentType, ok := negotiateExportFormat(r.Header.Get("Accept"))
+	if !ok {
+		http.Error(w, "неподдерживаемый формат выгрузки", http.StatusNotAcceptable)
+		return
+	}
+
+	from, to, err := parsePeriod(r.URL.Query())
+	if err != nil {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	encoder := newExportEncoder(contentType, w)
+
+	err = h.statementService.Export(r.Context(), userID, from, to, encoder)
+	if err == nil {
+		err = encoder.Flush()
+	}
+	if err != nil {
+		if encoder.Written() {
+			// Часть выгрузки уже отправлена: обрываем соединение, чтобы клиент
+			// не принял усечённый ответ за полный.
+			panic(http.ErrAbortHandler)
+		}
+		if errors.Is(err, domain.ErrInvalidStatementQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func parsePeriod(values url.Values) (from, to *time.Time, err error) {
+	for _, param := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"from", &from},
+		{"to", &to},
+	} {
+		raw := values.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		*param.dst = &t
+	}
+
+	return from, to, nil
+}
+
+func parseStatementQuery(values url.Values) (domain.StatementQuery, error) {
+	var query domain.StatementQuery
+
+	from, to, err := parsePeriod(values)
+	if err != nil {
+		return query, err
+	}
+	query.From = from
+	query.To = to
+
+	if raw := values.Get("cursor"); raw != "" {
+		cursor, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return query, err
+		}
+		query.Cursor = cursor
+	}
+
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return query, err
+		}
+		query.Limit = limit
+	}
+
+	return query, nil
+}
@@ -0,0 +1,314 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/api/http/dto"
+	"github.com/arvaliullin/gophermart/internal/api/http/handlers"
+	"github.com/arvaliullin/gophermart/internal/api/http/middleware"
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStatementHandler_Get(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		userID         int64
+		query          string
+		setup          func(*mocks.MockStatementService)
+		wantStatusCode int
+	}{
+		{
+			name:   "success",
+			userID: 1,
+			query:  "?from=2024-01-01T00:00:00Z&to=2024-02-01T00:00:00Z&cursor=10&limit=2",
+			setup: func(statementService *mocks.MockStatementService) {
+				statementService.EXPECT().
+					GetStatement(gomock.Any(), int64(1), domain.StatementQuery{From: &from, To: &to, Cursor: 10, Limit: 2}).
+					Return(&domain.Statement{
+						Entries: []*domain.StatementEntry{
+							{
+								LedgerEntry: domain.LedgerEntry{
+									ID:          11,
+									Kind:        domain.LedgerEntryAccrual,
+									Amount:      decimal.NewFromInt(100),
+									OrderNumber: "12345678903",
+									CreatedAt:   from,
+								},
+								Balance: decimal.NewFromInt(100),
+							},
+							{
+								LedgerEntry: domain.LedgerEntry{
+									ID:          12,
+									Kind:        domain.LedgerEntryWithdrawal,
+									Amount:      decimal.NewFromInt(-30),
+									OrderNumber: "79927398713",
+									CreatedAt:   from.Add(time.Hour),
+								},
+								Balance: decimal.NewFromInt(70),
+							},
+						},
+						NextCursor: 12,
+					}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "empty statement",
+			userID: 1,
+			setup: func(statementService *mocks.MockStatementService) {
+				statementService.EXPECT().
+					GetStatement(gomock.Any(), int64(1), domain.StatementQuery{}).
+					Return(&domain.Statement{}, nil)
+			},
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:           "invalid date",
+			userID:         1,
+			query:          "?from=yesterday",
+			setup:          func(statementService *mocks.MockStatementService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid cursor",
+			userID:         1,
+			query:          "?cursor=abc",
+			setup:          func(statementService *mocks.MockStatementService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid query",
+			userID: 1,
+			query:  "?limit=100000",
+			setup: func(statementService *mocks.MockStatementService) {
+				statementService.EXPECT().
+					GetStatement(gomock.Any(), int64(1), gomock.Any()).
+					Return(nil, domain.ErrInvalidStatementQuery)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "service error",
+			userID: 1,
+			setup: func(statementService *mocks.MockStatementService) {
+				statementService.EXPECT().
+					GetStatement(gomock.Any(), int64(1), gomock.Any()).
+					Return(nil, errors.New("database error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name:           "unauthorized",
+			userID:         0,
+			setup:          func(statementService *mocks.MockStatementService) {},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			statementService := mocks.NewMockStatementService(ctrl)
+			tt.setup(statementService)
+
+			handler := handlers.NewStatementHandler(statementService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/statement"+tt.query, nil)
+
+			if tt.userID > 0 {
+				ctx := context.WithValue(req.Context(), middleware.UserIDKey, tt.userID)
+				req = req.WithContext(ctx)
+			}
+
+			rr := httptest.NewRecorder()
+
+			handler.Get(rr, req)
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+
+			if tt.wantStatusCode == http.StatusOK {
+				var response dto.StatementResponse
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+				assert.Len(t, response.Entries, 2)
+				assert.Equal(t, 70.0, response.Entries[1].Balance)
+				assert.Equal(t, int64(12), response.NextCursor)
+			}
+		})
+	}
+}
+
+func TestStatementHandler_Export(t *testing.T) {
+	accrual := decimal.NewFromInt(100)
+	order := &domain.Order{
+		Number:     "12345678903",
+		Status:     domain.OrderStatusProcessed,
+		Accrual:    &accrual,
+		UploadedAt: time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+	}
+	withdrawal := &domain.Withdrawal{
+		OrderNumber: "79927398713",
+		Sum:         decimal.NewFromInt(30),
+		ProcessedAt: time.Date(2024, 1, 11, 12, 0, 0, 0, time.UTC),
+	}
+
+	streamAll := func(statementService *mocks.MockStatementService) {
+		statementService.EXPECT().
+			Export(gomock.Any(), int64(1), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ int64, _, _ *time.Time, sink ports.ExportSink) error {
+				if err := sink.WriteOrder(order); err != nil {
+					return err
+				}
+				return sink.WriteWithdrawal(withdrawal)
+			})
+	}
+
+	tests := []struct {
+		name            string
+		userID          int64
+		accept          string
+		query           string
+		setup           func(*mocks.MockStatementService)
+		wantStatusCode  int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "csv",
+			userID:          1,
+			accept:          "text/csv",
+			query:           "?from=2024-01-01T00:00:00Z",
+			setup:           streamAll,
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "text/csv",
+			wantBody: "type,number,status,amount,date\n" +
+				"order,12345678903,PROCESSED,100.00,2024-01-10T12:00:00Z\n" +
+				"withdrawal,79927398713,,30.00,2024-01-11T12:00:00Z\n",
+		},
+		{
+			name:            "ndjson",
+			userID:          1,
+			accept:          "application/x-ndjson",
+			setup:           streamAll,
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "application/x-ndjson",
+			wantBody: `{"type":"order","number":"12345678903","status":"PROCESSED","amount":"100.00","date":"2024-01-10T12:00:00Z"}` + "\n" +
+				`{"type":"withdrawal","number":"79927398713","amount":"30.00","date":"2024-01-11T12:00:00Z"}` + "\n",
+		},
+		{
+			name:   "empty csv",
+			userID: 1,
+			setup: func(statementService *mocks.MockStatementService) {
+				statementService.EXPECT().
+					Export(gomock.Any(), int64(1), nil, nil, gomock.Any()).
+					Return(nil)
+			},
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "text/csv",
+			wantBody:        "type,number,status,amount,date\n",
+		},
+		{
+			name:           "unsupported format",
+			userID:         1,
+			accept:         "application/xml",
+			setup:          func(statementService *mocks.MockStatementService) {},
+			wantStatusCode: http.StatusNotAcceptable,
+		},
+		{
+			name:           "invalid period",
+			userID:         1,
+			query:          "?to=tomorrow",
+			setup:          func(statementService *mocks.MockStatementService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "service error before output",
+			userID: 1,
+			setup: func(statementService *mocks.MockStatementService) {
+				statementService.EXPECT().
+					Export(gomock.Any(), int64(1), nil, nil, gomock.Any()).
+					Return(errors.New("database error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name:           "unauthorized",
+			userID:         0,
+			setup:          func(statementService *mocks.MockStatementService) {},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			statementService := mocks.NewMockStatementService(ctrl)
+			tt.setup(statementService)
+
+			handler := handlers.NewStatementHandler(statementService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/statement/export"+tt.query, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			if tt.userID > 0 {
+				ctx := context.WithValue(req.Context(), middleware.UserIDKey, tt.userID)
+				req = req.WithContext(ctx)
+			}
+
+			rr := httptest.NewRecorder()
+
+			handler.Export(rr, req)
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+			if tt.wantContentType != "" {
+				assert.Equal(t, tt.wantContentType, rr.Header().Get("Content-Type"))
+				assert.Equal(t, tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestStatementHandler_Export_AbortsAfterPartialOutput(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	statementService := mocks.NewMockStatementService(ctrl)
+	statementService.EXPECT().
+		Export(gomock.Any(), int64(1), nil, nil, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int64, _, _ *time.Time, sink ports.ExportSink) error {
+			if err := sink.WriteOrder(&domain.Order{Number: "12345678903"}); err != nil {
+				return err
+			}
+			return errors.New("connection lost")
+		})
+
+	handler := handlers.NewStatementHandler(statementService)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/statement/export", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.Export(httptest.NewRecorder(), req)
+	})
+}
@@ -2,11 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/arvaliullin/gophermart/internal/api/http/dto"
 	"github.com/arvaliullin/gophermart/internal/api/http/middleware"
+	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/go-chi/chi/v5"
+	"github.com/shopspring/decimal"
 )
 
 // WithdrawalHandler обрабатывает HTTP запросы истории списаний.
@@ -22,15 +28,54 @@ func NewWithdrawalHandler(balanceService ports.BalanceService) *WithdrawalHandle
 }
 
 // List возвращает историю списаний пользователя.
+//
+// Параметры limit, cursor, sort, from и to включают постраничную выборку;
+// курсор следующей страницы возвращается в заголовках X-Next-Cursor и Link.
 func (h *WithdrawalHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, func(withdrawals []*domain.Withdrawal) any {
+		return dto.FromDomainWithdrawals(withdrawals)
+	})
+}
+
+// ListV2 возвращает историю списаний пользователя с суммами в виде десятичных строк.
+func (h *WithdrawalHandler) ListV2(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, func(withdrawals []*domain.Withdrawal) any {
+		return dto.FromDomainWithdrawalsV2(withdrawals)
+	})
+}
+
+func (h *WithdrawalHandler) list(w http.ResponseWriter, r *http.Request, toResponse func([]*domain.Withdrawal) any) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == 0 {
 		http.Error(w, "пользователь не авторизован", http.StatusUnauthorized)
 		return
 	}
 
-	withdrawals, err := h.balanceService.GetWithdrawals(r.Context(), userID)
+	var (
+		withdrawals []*domain.Withdrawal
+		next        *domain.PageCursor
+		err         error
+	)
+	if values := r.URL.Query(); isListRequest(values) {
+		query, parseErr := parseListQuery(values)
+		if parseErr != nil {
+			http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var page *domain.WithdrawalPage
+		page, err = h.balanceService.ListWithdrawals(r.Context(), userID, query)
+		if page != nil {
+			withdrawals, next = page.Withdrawals, page.Next
+		}
+	} else {
+		withdrawals, err = h.balanceService.GetWithdrawals(r.Context(), userID)
+	}
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidListQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -40,7 +85,58 @@ func (h *WithdrawalHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setNextPageHeaders(w, r, next)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toResponse(withdrawals))
+}
+
+// Refund обрабатывает административный запрос на возврат баллов по списанию
+// любого пользователя. Тело запроса необязательно: без суммы возвращается
+// вся невозвращённая часть списания.
+func (h *WithdrawalHandler) Refund(w http.ResponseWriter, r *http.Request) {
+	withdrawalID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || withdrawalID <= 0 {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req dto.RefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !req.IsValid() {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var amount *decimal.Decimal
+	if req.Sum != nil {
+		sum := decimal.NewFromFloat(*req.Sum)
+		amount = &sum
+	}
+
+	refund, err := h.balanceService.Refund(r.Context(), withdrawalID, amount)
+	if err != nil {
+		if errors.Is(err, domain.ErrWithdrawalNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, domain.ErrRefundExceedsWithdrawal) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidRefundAmount) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dto.FromDomainWithdrawals(withdrawals))
+	json.NewEncoder(w).Encode(dto.FromDomainRefund(refund))
 }
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/arvaliullin/gophermart/internal/api/http/dto"
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/go-chi/chi/v5"
+)
+
+// WithdrawalLimitHandler обрабатывает административные запросы управления
+// индивидуальными лимитами списаний.
+type WithdrawalLimitHandler struct {
+	limitService ports.WithdrawalLimitService
+}
+
+// NewWithdrawalLimitHandler создаёт новый обработчик лимитов списаний.
+func NewWithdrawalLimitHandler(limitService ports.WithdrawalLimitService) *WithdrawalLimitHandler {
+	return &WithdrawalLimitHandler{
+		limitService: limitService,
+	}
+}
+
+// Get возвращает лимиты списаний, действующие для пользователя.
+func (h *WithdrawalLimitHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseUserID(r)
+	if !ok {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limits, err := h.limitService.Get(r.Context(), userID)
+	writeWithdrawalLimits(w, limits, err)
+}
+
+// Set назначает пользователю индивидуальные лимиты списаний.
+func (h *WithdrawalLimitHandler) Set(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseUserID(r)
+	if !ok {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req dto.WithdrawalLimitsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !req.IsValid() {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limits, err := h.limitService.Set(r.Context(), req.ToDomain(userID))
+	writeWithdrawalLimits(w, limits, err)
+}
+
+// Reset снимает индивидуальные лимиты списаний пользователя.
+func (h *WithdrawalLimitHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseUserID(r)
+	if !ok {
+		http.Error(w, ErrInvalidRequestFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limits, err := h.limitService.Reset(r.Context(), userID)
+	writeWithdrawalLimits(w, limits, err)
+}
+
+func parseUserID(r *http.Request) (int64, bool) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, false
+	}
+	return userID, true
+}
+
+func writeWithdrawalLimits(w http.ResponseWriter, limits *domain.UserWithdrawalLimits, err error) {
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidWithdrawalLimits) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.FromDomainUserWithdrawalLimits(limits))
+}
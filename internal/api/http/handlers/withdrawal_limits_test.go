@@ -0,0 +1,141 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/arvaliullin/gophermart/internal/api/http/dto"
+	"github.com/arvaliullin/gophermart/internal/api/http/handlers"
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWithdrawalLimitHandler(t *testing.T) {
+	result := &domain.UserWithdrawalLimits{
+		UserID: 7,
+		Limits: domain.WithdrawalLimits{
+			PerTransaction: decimal.NewFromInt(100),
+			Daily:          decimal.NewFromInt(500),
+		},
+		Overridden: true,
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		userID         string
+		body           string
+		setup          func(*mocks.MockWithdrawalLimitService)
+		wantStatusCode int
+	}{
+		{
+			name:   "get",
+			method: http.MethodGet,
+			userID: "7",
+			setup: func(service *mocks.MockWithdrawalLimitService) {
+				service.EXPECT().Get(gomock.Any(), int64(7)).Return(result, nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "set",
+			method: http.MethodPut,
+			userID: "7",
+			body:   `{"per_transaction":100,"daily":500}`,
+			setup: func(service *mocks.MockWithdrawalLimitService) {
+				service.EXPECT().Set(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, override *domain.WithdrawalLimitsOverride) (*domain.UserWithdrawalLimits, error) {
+						assert.Equal(t, int64(7), override.UserID)
+						if assert.NotNil(t, override.PerTransaction) {
+							assert.True(t, decimal.NewFromInt(100).Equal(*override.PerTransaction))
+						}
+						assert.Nil(t, override.Monthly)
+						return result, nil
+					})
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "reset",
+			method: http.MethodDelete,
+			userID: "7",
+			setup: func(service *mocks.MockWithdrawalLimitService) {
+				service.EXPECT().Reset(gomock.Any(), int64(7)).Return(result, nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "negative limit",
+			method:         http.MethodPut,
+			userID:         "7",
+			body:           `{"daily":-1}`,
+			setup:          func(service *mocks.MockWithdrawalLimitService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid user id",
+			method:         http.MethodGet,
+			userID:         "abc",
+			setup:          func(service *mocks.MockWithdrawalLimitService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "user not found",
+			method: http.MethodGet,
+			userID: "8",
+			setup: func(service *mocks.MockWithdrawalLimitService) {
+				service.EXPECT().Get(gomock.Any(), int64(8)).Return(nil, domain.ErrUserNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mocks.NewMockWithdrawalLimitService(ctrl)
+			tt.setup(service)
+
+			handler := handlers.NewWithdrawalLimitHandler(service)
+
+			req := httptest.NewRequest(tt.method, "/api/admin/users/"+tt.userID+"/withdrawal-limits",
+				bytes.NewBufferString(tt.body))
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("id", tt.userID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+			rr := httptest.NewRecorder()
+
+			switch tt.method {
+			case http.MethodGet:
+				handler.Get(rr, req)
+			case http.MethodPut:
+				handler.Set(rr, req)
+			case http.MethodDelete:
+				handler.Reset(rr, req)
+			}
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+
+			if tt.wantStatusCode == http.StatusOK {
+				var resp dto.WithdrawalLimitsResponse
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+				assert.Equal(t, int64(7), resp.UserID)
+				assert.Equal(t, 100.0, resp.PerTransaction)
+				assert.Equal(t, 500.0, resp.Daily)
+				assert.Zero(t, resp.Monthly)
+				assert.True(t, resp.Overridden)
+			}
+		})
+	}
+}
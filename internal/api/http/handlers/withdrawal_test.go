@@ -1,6 +1,7 @@
 package handlers_test
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"net/http/httptest"
@@ -11,6 +12,7 @@ import (
 	"github.com/arvaliullin/gophermart/internal/api/http/middleware"
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
+	"github.com/go-chi/chi/v5"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -99,3 +101,145 @@ func TestWithdrawalHandler_List(t *testing.T) {
 		})
 	}
 }
+
+func TestWithdrawalHandler_List_Paginated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	next := &domain.PageCursor{Time: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC), ID: 2}
+	balanceService := mocks.NewMockBalanceService(ctrl)
+	balanceService.EXPECT().
+		ListWithdrawals(gomock.Any(), int64(1), domain.ListQuery{Limit: 1}).
+		Return(&domain.WithdrawalPage{
+			Withdrawals: []*domain.Withdrawal{{ID: 2, OrderNumber: "79927398713", Sum: decimal.NewFromInt(50)}},
+			Next:        next,
+		}, nil)
+
+	handler := handlers.NewWithdrawalHandler(balanceService)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/withdrawals?limit=1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
+	rr := httptest.NewRecorder()
+
+	handler.List(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, next.String(), rr.Header().Get(handlers.NextCursorHeader))
+	assert.Equal(t, "</api/user/withdrawals?cursor="+next.String()+`&limit=1>; rel="next"`, rr.Header().Get("Link"))
+}
+
+func TestWithdrawalHandler_Refund(t *testing.T) {
+	tests := []struct {
+		name           string
+		withdrawalID   string
+		body           string
+		setup          func(*mocks.MockBalanceService)
+		wantStatusCode int
+		wantBody       bool
+	}{
+		{
+			name:         "full refund without body",
+			withdrawalID: "7",
+			body:         "",
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					Refund(gomock.Any(), int64(7), (*decimal.Decimal)(nil)).
+					Return(&domain.Refund{
+						ID:           1,
+						WithdrawalID: 7,
+						UserID:       1,
+						Sum:          decimal.NewFromInt(100),
+						ProcessedAt:  time.Now(),
+					}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       true,
+		},
+		{
+			name:         "partial refund",
+			withdrawalID: "7",
+			body:         `{"sum": 40.5}`,
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					Refund(gomock.Any(), int64(7), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int64, amount *decimal.Decimal) (*domain.Refund, error) {
+						assert.True(t, decimal.NewFromFloat(40.5).Equal(*amount))
+						return &domain.Refund{ID: 2, WithdrawalID: 7, UserID: 1, Sum: *amount}, nil
+					})
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       true,
+		},
+		{
+			name:         "refund exceeds withdrawal",
+			withdrawalID: "7",
+			body:         `{"sum": 1000}`,
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					Refund(gomock.Any(), int64(7), gomock.Any()).
+					Return(nil, domain.ErrRefundExceedsWithdrawal)
+			},
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name:         "withdrawal not found",
+			withdrawalID: "99",
+			body:         "",
+			setup: func(balanceService *mocks.MockBalanceService) {
+				balanceService.EXPECT().
+					Refund(gomock.Any(), int64(99), gomock.Any()).
+					Return(nil, domain.ErrWithdrawalNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "invalid withdrawal id",
+			withdrawalID:   "abc",
+			setup:          func(balanceService *mocks.MockBalanceService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "non-positive sum",
+			withdrawalID:   "7",
+			body:           `{"sum": 0}`,
+			setup:          func(balanceService *mocks.MockBalanceService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid json",
+			withdrawalID:   "7",
+			body:           "invalid",
+			setup:          func(balanceService *mocks.MockBalanceService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			balanceService := mocks.NewMockBalanceService(ctrl)
+			tt.setup(balanceService)
+
+			handler := handlers.NewWithdrawalHandler(balanceService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/withdrawals/"+tt.withdrawalID+"/refund", bytes.NewReader([]byte(tt.body)))
+
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("id", tt.withdrawalID)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			handler.Refund(rr, req)
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+			if tt.wantBody {
+				assert.NotEmpty(t, rr.Body.String())
+				assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// AdminTokenHeader задаёт заголовок со служебным токеном администратора.
+const AdminTokenHeader = "X-Admin-Token"
+
+// AdminAuth создаёт middleware для проверки служебного токена администратора.
+// Если токен не задан в конфигурации, административные запросы запрещены.
+func AdminAuth(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				http.Error(w, "административный доступ отключён", http.StatusForbidden)
+				return
+			}
+
+			provided := r.Header.Get(AdminTokenHeader)
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				http.Error(w, "недействительный токен администратора", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
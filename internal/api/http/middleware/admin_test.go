@@ -0,0 +1,42 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/arvaliullin/gophermart/internal/api/http/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name           string
+		configured     string
+		provided       string
+		wantStatusCode int
+	}{
+		{"верный токен", "admin-secret", "admin-secret", http.StatusOK},
+		{"неверный токен", "admin-secret", "wrong", http.StatusUnauthorized},
+		{"без токена", "admin-secret", "", http.StatusUnauthorized},
+		{"токен не настроен", "", "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := middleware.AdminAuth(tt.configured)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/reconciliation", nil)
+			if tt.provided != "" {
+				req.Header.Set(middleware.AdminTokenHeader, tt.provided)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// IdempotencyKeyHeader задаёт заголовок с ключом идемпотентности запроса.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader выставляется в ответах, воспроизведённых из сохранённого результата.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+
+	// DefaultIdempotencyLockTTL задаёт время, на которое запрос занимает ключ
+	// идемпотентности, если иное не указано.
+	DefaultIdempotencyLockTTL = time.Minute
+
+	msgIdempotencyKeyTooLong    = "слишком длинный ключ идемпотентности"
+	msgIdempotencyKeyReused     = "ключ идемпотентности уже использован для другого запроса"
+	msgIdempotencyKeyInProgress = "запрос с этим ключом идемпотентности ещё выполняется"
+	msgIdempotencyStorageError  = "ошибка хранилища ключей идемпотентности"
+	msgIdempotencyReleaseError  = "ошибка освобождения ключа идемпотентности"
+	msgIdempotencyExtendError   = "ошибка продления блокировки ключа идемпотентности"
+	msgIdempotencyCompleteError = "ошибка сохранения ответа по ключу идемпотентности"
+)
+
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *recordingResponseWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingResponseWriter) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+// Idempotency создаёт middleware, обеспечивающее идемпотентность изменяющих запросов.
+//
+// Для запросов с заголовком Idempotency-Key ответ сохраняется в repo. Повторный
+// запрос с тем же ключом и телом получает сохранённый ответ без повторного выполнения,
+// а повтор ключа с другим телом отклоняется. Ключ занимается на lockTTL и
+// продлевается каждые lockTTL/2, пока обработчик выполняется, поэтому долгий
+// запрос не теряет ключ. Если блокировку перестали продлевать, например из-за
+// падения процесса, по истечении lockTTL ключ может занять повторный запрос.
+// Должно подключаться после Auth.
+func Idempotency(repo ports.IdempotencyRepository, logger zerolog.Logger, lockTTL time.Duration) func(http.Handler) http.Handler {
+	if lockTTL <= 0 {
+		lockTTL = DefaultIdempotencyLockTTL
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			userID := GetUserID(r.Context())
+			if key == "" || userID == 0 || !isMutatingMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				http.Error(w, msgIdempotencyKeyTooLong, http.StatusBadRequest)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "неверный формат запроса", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			requestHash := hashRequest(r, body)
+			record, created, err := repo.Reserve(r.Context(), userID, key, requestHash, lockTTL)
+			if err != nil {
+				http.Error(w, msgIdempotencyStorageError, http.StatusInternalServerError)
+				return
+			}
+
+			if !created {
+				replayIdempotentResponse(w, record, requestHash)
+				return
+			}
+
+			// Ответ сохраняется даже если клиент уже отключился.
+			ctx := context.WithoutCancel(r.Context())
+			release := func() {
+				if err := repo.Release(ctx, userID, key); err != nil {
+					logger.Error().Err(err).
+						Int64("user_id", userID).
+						Str("key", key).
+						Msg(msgIdempotencyReleaseError)
+				}
+			}
+
+			stopExtending := keepLocked(ctx, repo, logger, userID, key, lockTTL)
+			defer func() {
+				if rec := recover(); rec != nil {
+					stopExtending()
+					release()
+					panic(rec)
+				}
+			}()
+
+			recorder := &recordingResponseWriter{
+				ResponseWriter: w,
+				statusCode:     http.StatusOK,
+			}
+			next.ServeHTTP(recorder, r)
+			stopExtending()
+
+			if recorder.statusCode >= http.StatusInternalServerError {
+				release()
+				return
+			}
+
+			record.StatusCode = recorder.statusCode
+			record.ContentType = w.Header().Get("Content-Type")
+			record.ResponseBody = recorder.body.Bytes()
+
+			if err := repo.Complete(ctx, record); err != nil {
+				logger.Error().Err(err).
+					Int64("user_id", userID).
+					Str("key", key).
+					Msg(msgIdempotencyCompleteError)
+				release()
+			}
+		})
+	}
+}
+
+// keepLocked продлевает блокировку ключа каждые lockTTL/2 до вызова
+// возвращённой функции. Функция останова дожидается завершения продления,
+// поэтому после неё блокировку можно безопасно снять или заменить ответом.
+func keepLocked(ctx context.Context, repo ports.IdempotencyRepository, logger zerolog.Logger, userID int64, key string, lockTTL time.Duration) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(lockTTL / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := repo.Extend(ctx, userID, key, lockTTL); err != nil && ctx.Err() == nil {
+					logger.Error().Err(err).
+						Int64("user_id", userID).
+						Str("key", key).
+						Msg(msgIdempotencyExtendError)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func replayIdempotentResponse(w http.ResponseWriter, record *domain.IdempotencyRecord, requestHash string) {
+	if record.RequestHash != requestHash {
+		http.Error(w, msgIdempotencyKeyReused, http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !record.IsCompleted() {
+		http.Error(w, msgIdempotencyKeyInProgress, http.StatusConflict)
+		return
+	}
+
+	if record.ContentType != "" {
+		w.Header().Set("Content-Type", record.ContentType)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.StatusCode)
+	w.Write(record.ResponseBody)
+}
+
+func hashRequest(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.RawQuery))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,354 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/api/http/middleware"
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newIdempotentRequest(method, body, key string, userID int64) *http.Request {
+	req := httptest.NewRequest(method, "/api/user/balance/withdraw", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	if userID > 0 {
+		ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID)
+		req = req.WithContext(ctx)
+	}
+	return req
+}
+
+func TestIdempotency_FirstRequestStoresResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+
+	var reserved *domain.IdempotencyRecord
+	repo.EXPECT().
+		Reserve(gomock.Any(), int64(1), "key-1", gomock.Any(), time.Minute).
+		DoAndReturn(func(_ context.Context, userID int64, key, hash string, _ time.Duration) (*domain.IdempotencyRecord, bool, error) {
+			reserved = &domain.IdempotencyRecord{UserID: userID, Key: key, RequestHash: hash}
+			return reserved, true, nil
+		})
+	repo.EXPECT().
+		Complete(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, record *domain.IdempotencyRecord) error {
+			assert.Equal(t, http.StatusAccepted, record.StatusCode)
+			assert.Equal(t, "application/json", record.ContentType)
+			assert.Equal(t, `{"ok":true}`, string(record.ResponseBody))
+			return nil
+		})
+
+	calls := 0
+	handler := middleware.Idempotency(repo, zerolog.Nop(), time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"order":"79927398713","sum":100}`, string(body))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newIdempotentRequest(http.MethodPost, `{"order":"79927398713","sum":100}`, "key-1", 1))
+
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, rr.Header().Get(middleware.IdempotentReplayedHeader))
+}
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+
+	var firstHash string
+	gomock.InOrder(
+		repo.EXPECT().
+			Reserve(gomock.Any(), int64(1), "key-1", gomock.Any(), time.Minute).
+			DoAndReturn(func(_ context.Context, userID int64, key, hash string, _ time.Duration) (*domain.IdempotencyRecord, bool, error) {
+				firstHash = hash
+				return &domain.IdempotencyRecord{UserID: userID, Key: key, RequestHash: hash}, true, nil
+			}),
+		repo.EXPECT().Complete(gomock.Any(), gomock.Any()).Return(nil),
+		repo.EXPECT().
+			Reserve(gomock.Any(), int64(1), "key-1", gomock.Any(), time.Minute).
+			DoAndReturn(func(_ context.Context, userID int64, key, hash string, _ time.Duration) (*domain.IdempotencyRecord, bool, error) {
+				return &domain.IdempotencyRecord{
+					UserID:       userID,
+					Key:          key,
+					RequestHash:  firstHash,
+					StatusCode:   http.StatusOK,
+					ContentType:  "text/plain",
+					ResponseBody: []byte("done"),
+				}, false, nil
+			}),
+	)
+
+	calls := 0
+	handler := middleware.Idempotency(repo, zerolog.Nop(), time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("done"))
+	}))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, newIdempotentRequest(http.MethodPost, "12345678903", "key-1", 1))
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, newIdempotentRequest(http.MethodPost, "12345678903", "key-1", 1))
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, "done", second.Body.String())
+	assert.Equal(t, "text/plain", second.Header().Get("Content-Type"))
+	assert.Equal(t, "true", second.Header().Get(middleware.IdempotentReplayedHeader))
+}
+
+func TestIdempotency_RejectsKeyReuseWithDifferentBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+	repo.EXPECT().
+		Reserve(gomock.Any(), int64(1), "key-1", gomock.Any(), time.Minute).
+		Return(&domain.IdempotencyRecord{
+			UserID:      1,
+			Key:         "key-1",
+			RequestHash: "другой-хеш",
+			StatusCode:  http.StatusOK,
+		}, false, nil)
+
+	handler := middleware.Idempotency(repo, zerolog.Nop(), time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("обработчик не должен вызываться")
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newIdempotentRequest(http.MethodPost, "79927398713", "key-1", 1))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
+
+func TestIdempotency_RequestInProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+	repo.EXPECT().
+		Reserve(gomock.Any(), int64(1), "key-1", gomock.Any(), time.Minute).
+		DoAndReturn(func(_ context.Context, userID int64, key, hash string, _ time.Duration) (*domain.IdempotencyRecord, bool, error) {
+			return &domain.IdempotencyRecord{UserID: userID, Key: key, RequestHash: hash}, false, nil
+		})
+
+	handler := middleware.Idempotency(repo, zerolog.Nop(), time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("обработчик не должен вызываться")
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newIdempotentRequest(http.MethodPost, "79927398713", "key-1", 1))
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestIdempotency_ReleasesKeyOnServerError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+	repo.EXPECT().
+		Reserve(gomock.Any(), int64(1), "key-1", gomock.Any(), time.Minute).
+		DoAndReturn(func(_ context.Context, userID int64, key, hash string, _ time.Duration) (*domain.IdempotencyRecord, bool, error) {
+			return &domain.IdempotencyRecord{UserID: userID, Key: key, RequestHash: hash}, true, nil
+		})
+	repo.EXPECT().Release(gomock.Any(), int64(1), "key-1").Return(nil)
+
+	handler := middleware.Idempotency(repo, zerolog.Nop(), time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newIdempotentRequest(http.MethodPost, "79927398713", "key-1", 1))
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
+func TestIdempotency_ExtendsLockWhileHandlerRuns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const lockTTL = 20 * time.Millisecond
+
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+	repo.EXPECT().
+		Reserve(gomock.Any(), int64(1), "key-1", gomock.Any(), lockTTL).
+		DoAndReturn(func(_ context.Context, userID int64, key, hash string, _ time.Duration) (*domain.IdempotencyRecord, bool, error) {
+			return &domain.IdempotencyRecord{UserID: userID, Key: key, RequestHash: hash}, true, nil
+		})
+
+	completed := false
+	extend := repo.EXPECT().
+		Extend(gomock.Any(), int64(1), "key-1", lockTTL).
+		DoAndReturn(func(context.Context, int64, string, time.Duration) error {
+			assert.False(t, completed, "блокировка продлена после сохранения ответа")
+			return nil
+		}).
+		MinTimes(1)
+	repo.EXPECT().
+		Complete(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, *domain.IdempotencyRecord) error {
+			completed = true
+			return nil
+		}).
+		After(extend)
+
+	handler := middleware.Idempotency(repo, zerolog.Nop(), lockTTL)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * lockTTL)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newIdempotentRequest(http.MethodPost, "79927398713", "key-1", 1))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestIdempotency_ReleasesKeyOnPanic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+	repo.EXPECT().
+		Reserve(gomock.Any(), int64(1), "key-1", gomock.Any(), time.Minute).
+		DoAndReturn(func(_ context.Context, userID int64, key, hash string, _ time.Duration) (*domain.IdempotencyRecord, bool, error) {
+			return &domain.IdempotencyRecord{UserID: userID, Key: key, RequestHash: hash}, true, nil
+		})
+	repo.EXPECT().Release(gomock.Any(), int64(1), "key-1").Return(nil)
+
+	handler := middleware.Idempotency(repo, zerolog.Nop(), time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("сбой обработчика")
+	}))
+
+	assert.PanicsWithValue(t, "сбой обработчика", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest(http.MethodPost, "79927398713", "key-1", 1))
+	})
+}
+
+func TestIdempotency_LogsReleaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+	repo.EXPECT().
+		Reserve(gomock.Any(), int64(1), "key-1", gomock.Any(), time.Minute).
+		DoAndReturn(func(_ context.Context, userID int64, key, hash string, _ time.Duration) (*domain.IdempotencyRecord, bool, error) {
+			return &domain.IdempotencyRecord{UserID: userID, Key: key, RequestHash: hash}, true, nil
+		})
+	repo.EXPECT().Release(gomock.Any(), int64(1), "key-1").Return(errors.New("database error"))
+
+	var logs strings.Builder
+	handler := middleware.Idempotency(repo, zerolog.New(&logs), time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest(http.MethodPost, "79927398713", "key-1", 1))
+
+	assert.Contains(t, logs.String(), "database error")
+	assert.Contains(t, logs.String(), `"key":"key-1"`)
+}
+
+func TestIdempotency_HashIncludesQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+
+	var hashes []string
+	repo.EXPECT().
+		Reserve(gomock.Any(), int64(1), "key-1", gomock.Any(), time.Minute).
+		DoAndReturn(func(_ context.Context, userID int64, key, hash string, _ time.Duration) (*domain.IdempotencyRecord, bool, error) {
+			hashes = append(hashes, hash)
+			return nil, false, errors.New("database error")
+		}).
+		Times(2)
+
+	handler := middleware.Idempotency(repo, zerolog.Nop(), time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("обработчик не должен вызываться")
+	}))
+
+	for _, query := range []string{"format=csv", "format=ndjson"} {
+		req := newIdempotentRequest(http.MethodPost, "79927398713", "key-1", 1)
+		req.URL.RawQuery = query
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	require.Len(t, hashes, 2)
+	assert.NotEqual(t, hashes[0], hashes[1])
+}
+
+func TestIdempotency_StorageError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+	repo.EXPECT().
+		Reserve(gomock.Any(), int64(1), "key-1", gomock.Any(), time.Minute).
+		Return(nil, false, errors.New("database error"))
+
+	handler := middleware.Idempotency(repo, zerolog.Nop(), time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("обработчик не должен вызываться")
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newIdempotentRequest(http.MethodPost, "79927398713", "key-1", 1))
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
+func TestIdempotency_PassThrough(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		key    string
+	}{
+		{"без ключа", http.MethodPost, ""},
+		{"неизменяющий метод", http.MethodGet, "key-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockIdempotencyRepository(ctrl)
+
+			calls := 0
+			handler := middleware.Idempotency(repo, zerolog.Nop(), time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, newIdempotentRequest(tt.method, "", tt.key, 1))
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, 1, calls)
+		})
+	}
+}
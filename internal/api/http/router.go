@@ -2,9 +2,11 @@ package http
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/arvaliullin/gophermart/internal/api/http/handlers"
 	"github.com/arvaliullin/gophermart/internal/api/http/middleware"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
 	"github.com/arvaliullin/gophermart/internal/pkg/jwt"
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog"
@@ -16,8 +18,19 @@ type RouterConfig struct {
 	OrderHandler      *handlers.OrderHandler
 	BalanceHandler    *handlers.BalanceHandler
 	WithdrawalHandler *handlers.WithdrawalHandler
+	StatementHandler  *handlers.StatementHandler
+	IdempotencyRepo   ports.IdempotencyRepository
 	JWTManager        *jwt.Manager
-	Logger            zerolog.Logger
+
+	IdempotencyLockTTL time.Duration
+
+	ReconciliationHandler  *handlers.ReconciliationHandler
+	WithdrawalLimitHandler *handlers.WithdrawalLimitHandler
+	OrderRecheckHandler    *handlers.OrderRecheckHandler
+	DeadLetterHandler      *handlers.DeadLetterHandler
+	AdminToken             string
+
+	Logger zerolog.Logger
 }
 
 // NewRouter создаёт и настраивает HTTP роутер.
@@ -37,12 +50,53 @@ func NewRouter(cfg *RouterConfig) http.Handler {
 
 	router.Group(func(r chi.Router) {
 		r.Use(middleware.Auth(cfg.JWTManager))
+		r.Use(middleware.Idempotency(cfg.IdempotencyRepo, cfg.Logger, cfg.IdempotencyLockTTL))
 
 		r.Post("/api/user/orders", cfg.OrderHandler.Submit)
+		r.Post("/api/user/orders/batch", cfg.OrderHandler.SubmitBatch)
 		r.Get("/api/user/orders", cfg.OrderHandler.List)
+		r.Get("/api/user/orders/{number}", cfg.OrderHandler.Get)
+		r.Delete("/api/user/orders/{number}", cfg.OrderHandler.Cancel)
 		r.Get("/api/user/balance", cfg.BalanceHandler.Get)
 		r.Post("/api/user/balance/withdraw", cfg.BalanceHandler.Withdraw)
+		r.Post("/api/user/balance/holds", cfg.BalanceHandler.Hold)
+		r.Post("/api/user/balance/transfer", cfg.BalanceHandler.Transfer)
 		r.Get("/api/user/withdrawals", cfg.WithdrawalHandler.List)
+		r.Get("/api/user/statement", cfg.StatementHandler.Get)
+		r.Get("/api/user/statement/export", cfg.StatementHandler.Export)
+	})
+
+	router.Route("/api/v2", func(r chi.Router) {
+		r.Post("/user/register", cfg.AuthHandler.Register)
+		r.Post("/user/login", cfg.AuthHandler.Login)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Auth(cfg.JWTManager))
+			r.Use(middleware.Idempotency(cfg.IdempotencyRepo, cfg.Logger, cfg.IdempotencyLockTTL))
+
+			r.Post("/user/orders", cfg.OrderHandler.Submit)
+			r.Post("/user/orders/batch", cfg.OrderHandler.SubmitBatch)
+			r.Get("/user/orders", cfg.OrderHandler.ListV2)
+			r.Get("/user/balance", cfg.BalanceHandler.GetV2)
+			r.Post("/user/balance/withdraw", cfg.BalanceHandler.WithdrawV2)
+			r.Get("/user/withdrawals", cfg.WithdrawalHandler.ListV2)
+		})
+	})
+
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.AdminAuth(cfg.AdminToken))
+
+		r.Post("/api/admin/holds/{id}/capture", cfg.BalanceHandler.CaptureHold)
+		r.Post("/api/admin/holds/{id}/release", cfg.BalanceHandler.ReleaseHold)
+		r.Post("/api/admin/withdrawals/{id}/refund", cfg.WithdrawalHandler.Refund)
+		r.Get("/api/admin/reconciliation", cfg.ReconciliationHandler.Report)
+		r.Post("/api/admin/reconciliation/repair", cfg.ReconciliationHandler.Repair)
+		r.Get("/api/admin/users/{id}/withdrawal-limits", cfg.WithdrawalLimitHandler.Get)
+		r.Put("/api/admin/users/{id}/withdrawal-limits", cfg.WithdrawalLimitHandler.Set)
+		r.Delete("/api/admin/users/{id}/withdrawal-limits", cfg.WithdrawalLimitHandler.Reset)
+		r.Post("/api/admin/orders/{number}/recheck", cfg.OrderRecheckHandler.Recheck)
+		r.Get("/api/admin/orders/dead-letter", cfg.DeadLetterHandler.List)
+		r.Post("/api/admin/orders/{number}/requeue", cfg.DeadLetterHandler.Requeue)
 	})
 
 	return router
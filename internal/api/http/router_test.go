@@ -30,6 +30,8 @@ func TestNewRouter_Ping(t *testing.T) {
 		OrderHandler:      handlers.NewOrderHandler(orderService),
 		BalanceHandler:    handlers.NewBalanceHandler(balanceService),
 		WithdrawalHandler: handlers.NewWithdrawalHandler(balanceService),
+		StatementHandler:  handlers.NewStatementHandler(mocks.NewMockStatementService(ctrl)),
+		IdempotencyRepo:   mocks.NewMockIdempotencyRepository(ctrl),
 		JWTManager:        jwtManager,
 		Logger:            logger,
 	})
@@ -58,6 +60,8 @@ func TestNewRouter_ProtectedRoutes_Unauthorized(t *testing.T) {
 		OrderHandler:      handlers.NewOrderHandler(orderService),
 		BalanceHandler:    handlers.NewBalanceHandler(balanceService),
 		WithdrawalHandler: handlers.NewWithdrawalHandler(balanceService),
+		StatementHandler:  handlers.NewStatementHandler(mocks.NewMockStatementService(ctrl)),
+		IdempotencyRepo:   mocks.NewMockIdempotencyRepository(ctrl),
 		JWTManager:        jwtManager,
 		Logger:            logger,
 	})
@@ -67,10 +71,23 @@ func TestNewRouter_ProtectedRoutes_Unauthorized(t *testing.T) {
 		path   string
 	}{
 		{http.MethodPost, "/api/user/orders"},
+		{http.MethodPost, "/api/user/orders/batch"},
 		{http.MethodGet, "/api/user/orders"},
+		{http.MethodGet, "/api/user/orders/12345678903"},
+		{http.MethodDelete, "/api/user/orders/12345678903"},
 		{http.MethodGet, "/api/user/balance"},
 		{http.MethodPost, "/api/user/balance/withdraw"},
+		{http.MethodPost, "/api/user/balance/holds"},
+		{http.MethodPost, "/api/user/balance/transfer"},
 		{http.MethodGet, "/api/user/withdrawals"},
+		{http.MethodGet, "/api/user/statement"},
+		{http.MethodGet, "/api/user/statement/export"},
+		{http.MethodPost, "/api/v2/user/orders"},
+		{http.MethodPost, "/api/v2/user/orders/batch"},
+		{http.MethodGet, "/api/v2/user/orders"},
+		{http.MethodGet, "/api/v2/user/balance"},
+		{http.MethodPost, "/api/v2/user/balance/withdraw"},
+		{http.MethodGet, "/api/v2/user/withdrawals"},
 	}
 
 	for _, route := range protectedRoutes {
@@ -84,3 +101,67 @@ func TestNewRouter_ProtectedRoutes_Unauthorized(t *testing.T) {
 		})
 	}
 }
+
+func TestNewRouter_AdminRoutes(t *testing.T) {
+	tests := []struct {
+		name           string
+		adminToken     string
+		providedToken  string
+		wantStatusCode int
+	}{
+		{"токен не настроен", "", "", http.StatusForbidden},
+		{"неверный токен", "admin-secret", "wrong", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			balanceService := mocks.NewMockBalanceService(ctrl)
+
+			router := httpapi.NewRouter(&httpapi.RouterConfig{
+				AuthHandler:            handlers.NewAuthHandler(mocks.NewMockAuthService(ctrl)),
+				OrderHandler:           handlers.NewOrderHandler(mocks.NewMockOrderService(ctrl)),
+				BalanceHandler:         handlers.NewBalanceHandler(balanceService),
+				WithdrawalHandler:      handlers.NewWithdrawalHandler(balanceService),
+				StatementHandler:       handlers.NewStatementHandler(mocks.NewMockStatementService(ctrl)),
+				ReconciliationHandler:  handlers.NewReconciliationHandler(mocks.NewMockReconciliationService(ctrl)),
+				WithdrawalLimitHandler: handlers.NewWithdrawalLimitHandler(mocks.NewMockWithdrawalLimitService(ctrl)),
+				OrderRecheckHandler:    handlers.NewOrderRecheckHandler(mocks.NewMockOrderRecheckService(ctrl)),
+				DeadLetterHandler:      handlers.NewDeadLetterHandler(mocks.NewMockDeadLetterService(ctrl)),
+				IdempotencyRepo:        mocks.NewMockIdempotencyRepository(ctrl),
+				JWTManager:             jwt.NewManager("test-secret"),
+				Logger:                 zerolog.Nop(),
+				AdminToken:             tt.adminToken,
+			})
+
+			for _, route := range []struct {
+				method string
+				path   string
+			}{
+				{http.MethodPost, "/api/admin/holds/1/capture"},
+				{http.MethodPost, "/api/admin/holds/1/release"},
+				{http.MethodPost, "/api/admin/withdrawals/1/refund"},
+				{http.MethodGet, "/api/admin/reconciliation"},
+				{http.MethodPost, "/api/admin/reconciliation/repair"},
+				{http.MethodGet, "/api/admin/users/1/withdrawal-limits"},
+				{http.MethodPut, "/api/admin/users/1/withdrawal-limits"},
+				{http.MethodDelete, "/api/admin/users/1/withdrawal-limits"},
+				{http.MethodPost, "/api/admin/orders/12345678903/recheck"},
+				{http.MethodGet, "/api/admin/orders/dead-letter"},
+				{http.MethodPost, "/api/admin/orders/12345678903/requeue"},
+			} {
+				req := httptest.NewRequest(route.method, route.path, nil)
+				if tt.providedToken != "" {
+					req.Header.Set("X-Admin-Token", tt.providedToken)
+				}
+				rr := httptest.NewRecorder()
+
+				router.ServeHTTP(rr, req)
+
+				assert.Equal(t, tt.wantStatusCode, rr.Code, route.path)
+			}
+		})
+	}
+}
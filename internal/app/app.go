@@ -6,17 +6,26 @@ import (
 
 	"github.com/arvaliullin/gophermart/internal/config"
 	accrualworker "github.com/arvaliullin/gophermart/internal/core/services/accrual"
+	"github.com/arvaliullin/gophermart/internal/core/services/balance"
+	"github.com/arvaliullin/gophermart/internal/core/services/idempotency"
+	"github.com/arvaliullin/gophermart/internal/core/services/reconciliation"
 	"github.com/arvaliullin/gophermart/internal/repository/postgres"
 	"github.com/rs/zerolog"
 )
 
 // App представляет основное приложение Gophermart.
 type App struct {
-	logger        zerolog.Logger
-	config        *config.AppConfig
-	server        *http.Server
-	db            *postgres.DB
-	accrualWorker *accrualworker.Worker
+	logger         zerolog.Logger
+	config         *config.AppConfig
+	server         *http.Server
+	db             *postgres.DB
+	accrualWorker  *accrualworker.Worker
+	holdExpirer    *balance.HoldExpirer
+	pointsExpirer  *balance.PointsExpirer
+	reconJob       *reconciliation.Job
+	tierRecomputer *balance.TierRecomputer
+
+	idempotencyPurger *idempotency.Purger
 }
 
 // New создаёт новый экземпляр приложения с инициализированными зависимостями.
@@ -29,6 +38,11 @@ func New(ctx context.Context) (*App, error) {
 		WithRepositories().
 		WithServices().
 		WithAccrualWorker().
+		WithHoldExpirer().
+		WithPointsExpirer().
+		WithReconciliationJob().
+		WithTierRecomputer().
+		WithIdempotencyPurger().
 		WithHTTPServer().
 		Build()
 }
@@ -11,17 +11,26 @@ import (
 	"github.com/arvaliullin/gophermart/internal/api/http/client/accrual"
 	"github.com/arvaliullin/gophermart/internal/api/http/handlers"
 	"github.com/arvaliullin/gophermart/internal/config"
+	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/arvaliullin/gophermart/internal/core/ports"
 	accrualworker "github.com/arvaliullin/gophermart/internal/core/services/accrual"
 	"github.com/arvaliullin/gophermart/internal/core/services/auth"
 	"github.com/arvaliullin/gophermart/internal/core/services/balance"
+	"github.com/arvaliullin/gophermart/internal/core/services/deadletter"
+	"github.com/arvaliullin/gophermart/internal/core/services/idempotency"
+	"github.com/arvaliullin/gophermart/internal/core/services/limits"
 	"github.com/arvaliullin/gophermart/internal/core/services/order"
+	"github.com/arvaliullin/gophermart/internal/core/services/ordernumber"
+	"github.com/arvaliullin/gophermart/internal/core/services/reconciliation"
+	"github.com/arvaliullin/gophermart/internal/core/services/statement"
 	"github.com/arvaliullin/gophermart/internal/pkg/jwt"
 	"github.com/arvaliullin/gophermart/internal/pkg/retry"
 	"github.com/arvaliullin/gophermart/internal/repository/postgres"
 	retryadapter "github.com/arvaliullin/gophermart/internal/repository/retry"
+	"github.com/arvaliullin/gophermart/migrations"
 	"github.com/go-resty/resty/v2"
 	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
 )
 
 // Builder используется для пошагового построения приложения.
@@ -34,17 +43,34 @@ type Builder struct {
 	jwtManager    *jwt.Manager
 	retryStrategy *retry.Strategy
 
-	userRepo       ports.UserRepository
-	orderRepo      ports.OrderRepository
-	balanceRepo    ports.BalanceRepository
-	withdrawalRepo ports.WithdrawalRepository
+	userRepo        ports.UserRepository
+	orderRepo       ports.OrderRepository
+	balanceRepo     ports.BalanceRepository
+	withdrawalRepo  ports.WithdrawalRepository
+	ledgerRepo      ports.LedgerRepository
+	historyRepo     ports.HistoryRepository
+	reconRepo       ports.ReconciliationRepository
+	tierRepo        ports.TierRepository
+	limitRepo       ports.WithdrawalLimitRepository
+	uow             ports.UnitOfWork
+	idempotencyRepo ports.IdempotencyRepository
 
-	authService    *auth.Service
-	orderService   *order.Service
-	balanceService *balance.Service
+	authService       *auth.Service
+	orderService      *order.Service
+	balanceService    *balance.Service
+	statementService  *statement.Service
+	reconService      *reconciliation.Service
+	limitService      *limits.Service
+	deadLetterService *deadletter.Service
 
-	accrualClient *accrual.Client
-	accrualWorker *accrualworker.Worker
+	accrualClient  *accrual.Client
+	accrualWorker  *accrualworker.Worker
+	holdExpirer    *balance.HoldExpirer
+	pointsExpirer  *balance.PointsExpirer
+	reconJob       *reconciliation.Job
+	tierRecomputer *balance.TierRecomputer
+
+	idempotencyPurger *idempotency.Purger
 
 	server *http.Server
 }
@@ -85,7 +111,9 @@ func (b *Builder) WithLogger() *Builder {
 
 // WithDatabase устанавливает подключение к базе данных.
 func (b *Builder) WithDatabase() *Builder {
-	db, err := postgres.NewDB(b.ctx, b.config.DatabaseURI)
+	// Срок действия баллов нужен миграции, переносящей накопленные баллы в партии.
+	ctx := migrations.WithPointsTTL(b.ctx, b.config.PointsTTLMonths)
+	db, err := postgres.NewDB(ctx, b.config.DatabaseURI)
 	if err != nil {
 		panic(fmt.Errorf("%w: %w", ErrConnectDB, err))
 	}
@@ -128,17 +156,84 @@ func (b *Builder) WithRepositories() *Builder {
 		panic(fmt.Errorf("%w: %w", ErrCreateRetryRepo, err))
 	}
 
+	b.ledgerRepo, err = retryadapter.NewLedgerRepositoryAdapter(
+		postgres.NewLedgerRepository(b.db.Pool), b.retryStrategy)
+	if err != nil {
+		panic(fmt.Errorf("%w: %w", ErrCreateRetryRepo, err))
+	}
+
+	b.historyRepo, err = retryadapter.NewHistoryRepositoryAdapter(
+		postgres.NewHistoryRepository(b.db.Pool), b.retryStrategy)
+	if err != nil {
+		panic(fmt.Errorf("%w: %w", ErrCreateRetryRepo, err))
+	}
+
+	b.reconRepo, err = retryadapter.NewReconciliationRepositoryAdapter(
+		postgres.NewReconciliationRepository(b.db.Pool), b.retryStrategy)
+	if err != nil {
+		panic(fmt.Errorf("%w: %w", ErrCreateRetryRepo, err))
+	}
+
+	b.tierRepo, err = retryadapter.NewTierRepositoryAdapter(
+		postgres.NewTierRepository(b.db.Pool), b.retryStrategy)
+	if err != nil {
+		panic(fmt.Errorf("%w: %w", ErrCreateRetryRepo, err))
+	}
+
+	b.limitRepo, err = retryadapter.NewWithdrawalLimitRepositoryAdapter(
+		postgres.NewWithdrawalLimitRepository(b.db.Pool), b.retryStrategy)
+	if err != nil {
+		panic(fmt.Errorf("%w: %w", ErrCreateRetryRepo, err))
+	}
+
+	b.idempotencyRepo, err = retryadapter.NewIdempotencyRepositoryAdapter(
+		postgres.NewIdempotencyRepository(b.db.Pool), b.retryStrategy)
+	if err != nil {
+		panic(fmt.Errorf("%w: %w", ErrCreateRetryRepo, err))
+	}
+
+	b.uow, err = retryadapter.NewUnitOfWorkAdapter(
+		postgres.NewUnitOfWork(b.db.Pool), b.retryStrategy)
+	if err != nil {
+		panic(fmt.Errorf("%w: %w", ErrCreateRetryRepo, err))
+	}
+
 	return b
 }
 
 // WithServices создаёт бизнес-сервисы.
 func (b *Builder) WithServices() *Builder {
+	validator, err := ordernumber.NewValidator(b.config.OrderNumberAlgorithms...)
+	if err != nil {
+		panic(fmt.Errorf("%w: %w", ErrCreateOrderNumberValidator, err))
+	}
+
 	b.authService = auth.NewService(b.userRepo, b.balanceRepo, b.jwtManager)
-	b.orderService = order.NewService(b.orderRepo)
-	b.balanceService = balance.NewService(b.balanceRepo, b.withdrawalRepo)
+	b.orderService = order.NewService(b.orderRepo,
+		order.WithWithdrawals(b.withdrawalRepo),
+		order.WithOrderNumberValidator(validator))
+	b.balanceService = balance.NewService(b.balanceRepo, b.withdrawalRepo, b.userRepo,
+		balance.WithOrderNumberValidator(validator),
+		balance.WithHoldTTL(b.config.HoldTTL),
+		balance.WithExpiringWindow(b.config.PointsExpiringWindow),
+		balance.WithTransferLimits(b.config.TransferMaxAmount, b.config.TransferDailyAmount),
+		balance.WithWithdrawalLimits(b.withdrawalLimits()))
+	b.statementService = statement.NewService(b.ledgerRepo, b.historyRepo)
+	b.reconService = reconciliation.NewService(b.reconRepo, b.logger)
+	b.limitService = limits.NewService(b.limitRepo, b.userRepo, b.withdrawalLimits())
+	b.deadLetterService = deadletter.NewService(b.orderRepo)
 	return b
 }
 
+// withdrawalLimits собирает лимиты списаний по умолчанию из конфигурации.
+func (b *Builder) withdrawalLimits() domain.WithdrawalLimits {
+	return domain.WithdrawalLimits{
+		PerTransaction: b.config.WithdrawMaxAmount,
+		Daily:          b.config.WithdrawDailyAmount,
+		Monthly:        b.config.WithdrawMonthlyAmount,
+	}
+}
+
 // WithAccrualWorker создаёт клиент для accrual системы и воркер.
 func (b *Builder) WithAccrualWorker() *Builder {
 	httpClient := resty.New().
@@ -146,32 +241,96 @@ func (b *Builder) WithAccrualWorker() *Builder {
 		SetRetryCount(0)
 
 	b.accrualClient = accrual.NewClient(b.config.AccrualSystemAddress,
-		accrual.WithHTTPClient(httpClient))
+		accrual.WithHTTPClient(httpClient),
+		accrual.WithRateLimit(b.config.AccrualRequestsPerMinute, b.config.AccrualRateBurst))
 
 	b.accrualWorker = accrualworker.NewWorker(
 		b.orderRepo,
-		b.balanceRepo,
+		b.uow,
 		b.accrualClient,
 		b.logger,
+		accrualworker.WithPointsTTL(b.config.PointsTTLMonths),
+		accrualworker.WithTiers(b.tierRepo, b.tierPolicy()),
+		accrualworker.WithConcurrency(b.config.AccrualConcurrency),
+		accrualworker.WithBatchSize(b.config.AccrualBatchSize),
+		accrualworker.WithLeaseTTL(b.config.AccrualLeaseTTL),
+		accrualworker.WithGiveUp(b.config.AccrualMaxAttempts, b.config.AccrualMaxAge),
 	)
 
 	return b
 }
 
+// WithHoldExpirer создаёт воркер, завершающий блокировки баллов с истёкшим сроком.
+func (b *Builder) WithHoldExpirer() *Builder {
+	b.holdExpirer = balance.NewHoldExpirer(b.balanceRepo, b.logger, b.config.HoldExpireInterval)
+	return b
+}
+
+// WithPointsExpirer создаёт воркер, списывающий сгоревшие партии баллов.
+func (b *Builder) WithPointsExpirer() *Builder {
+	b.pointsExpirer = balance.NewPointsExpirer(b.balanceRepo, b.logger, b.config.PointsExpireInterval)
+	return b
+}
+
+// WithTierRecomputer создаёт воркер пересчёта уровней лояльности.
+func (b *Builder) WithTierRecomputer() *Builder {
+	b.tierRecomputer = balance.NewTierRecomputer(b.tierRepo, b.tierPolicy(), b.logger,
+		b.config.TierRecomputeInterval)
+	return b
+}
+
+// tierPolicy собирает правила уровней лояльности из конфигурации.
+func (b *Builder) tierPolicy() domain.TierPolicy {
+	return domain.TierPolicy{
+		{Tier: domain.TierBronze, Threshold: decimal.Zero, Multiplier: decimal.NewFromInt(1)},
+		{Tier: domain.TierSilver, Threshold: b.config.TierSilverThreshold, Multiplier: b.config.TierSilverMultiplier},
+		{Tier: domain.TierGold, Threshold: b.config.TierGoldThreshold, Multiplier: b.config.TierGoldMultiplier},
+	}
+}
+
+// WithReconciliationJob создаёт воркер периодической сверки балансов.
+func (b *Builder) WithReconciliationJob() *Builder {
+	b.reconJob = reconciliation.NewJob(b.reconService, b.logger,
+		b.config.ReconcileInterval, b.config.ReconcileAutoRepair)
+	return b
+}
+
+// WithIdempotencyPurger создаёт воркер очистки устаревших ключей идемпотентности.
+func (b *Builder) WithIdempotencyPurger() *Builder {
+	b.idempotencyPurger = idempotency.NewPurger(b.idempotencyRepo, b.logger,
+		b.config.IdempotencyPurgeInterval, b.config.IdempotencyKeyTTL)
+	return b
+}
+
 // WithHTTPServer создаёт HTTP сервер с роутером.
 func (b *Builder) WithHTTPServer() *Builder {
 	authHandler := handlers.NewAuthHandler(b.authService)
 	orderHandler := handlers.NewOrderHandler(b.orderService)
 	balanceHandler := handlers.NewBalanceHandler(b.balanceService)
 	withdrawalHandler := handlers.NewWithdrawalHandler(b.balanceService)
+	statementHandler := handlers.NewStatementHandler(b.statementService)
+	reconciliationHandler := handlers.NewReconciliationHandler(b.reconService)
+	withdrawalLimitHandler := handlers.NewWithdrawalLimitHandler(b.limitService)
+	orderRecheckHandler := handlers.NewOrderRecheckHandler(b.accrualWorker)
+	deadLetterHandler := handlers.NewDeadLetterHandler(b.deadLetterService)
 
 	router := httpapi.NewRouter(&httpapi.RouterConfig{
 		AuthHandler:       authHandler,
 		OrderHandler:      orderHandler,
 		BalanceHandler:    balanceHandler,
 		WithdrawalHandler: withdrawalHandler,
+		StatementHandler:  statementHandler,
+		IdempotencyRepo:   b.idempotencyRepo,
 		JWTManager:        b.jwtManager,
 		Logger:            b.logger,
+
+		IdempotencyLockTTL: b.config.IdempotencyLockTTL,
+
+		ReconciliationHandler:  reconciliationHandler,
+		WithdrawalLimitHandler: withdrawalLimitHandler,
+		OrderRecheckHandler:    orderRecheckHandler,
+		DeadLetterHandler:      deadLetterHandler,
+		AdminToken:             b.config.AdminToken,
 	})
 
 	b.server = &http.Server{
@@ -185,10 +344,16 @@ func (b *Builder) WithHTTPServer() *Builder {
 // Build собирает и возвращает готовый экземпляр приложения.
 func (b *Builder) Build() (*App, error) {
 	return &App{
-		logger:        b.logger,
-		config:        b.config,
-		server:        b.server,
-		db:            b.db,
-		accrualWorker: b.accrualWorker,
+		logger:         b.logger,
+		config:         b.config,
+		server:         b.server,
+		db:             b.db,
+		accrualWorker:  b.accrualWorker,
+		holdExpirer:    b.holdExpirer,
+		pointsExpirer:  b.pointsExpirer,
+		reconJob:       b.reconJob,
+		tierRecomputer: b.tierRecomputer,
+
+		idempotencyPurger: b.idempotencyPurger,
 	}, nil
 }
@@ -11,6 +11,9 @@ var ErrConnectDB = fmt.Errorf("ошибка подключения к БД")
 // ErrCreateRetryRepo возвращается при ошибке создания репозитория с retry.
 var ErrCreateRetryRepo = fmt.Errorf("ошибка создания репозитория с retry")
 
+// ErrCreateOrderNumberValidator возвращается при ошибке настройки проверки номеров заказов.
+var ErrCreateOrderNumberValidator = fmt.Errorf("ошибка настройки проверки номеров заказов")
+
 const (
 	msgConfigLoaded       = "конфигурация загружена"
 	msgServerStarting     = "запуск HTTP сервера"
@@ -18,4 +21,5 @@ const (
 	msgShuttingDown       = "завершение работы приложения"
 	msgServerStopError    = "ошибка остановки HTTP сервера"
 	msgDBConnectionClosed = "соединение с БД закрыто"
+	msgAccrualStopTimeout = "воркер начислений не завершился до закрытия БД"
 )
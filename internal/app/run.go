@@ -8,7 +8,16 @@ import (
 
 // Run запускает приложение и ожидает сигнала завершения.
 func (a *App) Run(ctx context.Context) error {
-	go a.accrualWorker.Run(ctx)
+	accrualDone := make(chan struct{})
+	go func() {
+		defer close(accrualDone)
+		a.accrualWorker.Run(ctx)
+	}()
+	go a.holdExpirer.Run(ctx)
+	go a.pointsExpirer.Run(ctx)
+	go a.reconJob.Run(ctx)
+	go a.tierRecomputer.Run(ctx)
+	go a.idempotencyPurger.Run(ctx)
 
 	go func() {
 		a.logger.Info().
@@ -35,6 +44,14 @@ func (a *App) Run(ctx context.Context) error {
 			Msg(msgServerStopError)
 	}
 
+	// Запросы воркера начислений отменяются вместе с ctx; соединение с БД
+	// закрывается только после завершения уже начатых обработок.
+	select {
+	case <-accrualDone:
+	case <-shutdownCtx.Done():
+		a.logger.Warn().Msg(msgAccrualStopTimeout)
+	}
+
 	a.db.Close()
 	a.logger.Info().Msg(msgDBConnectionClosed)
 
@@ -3,8 +3,10 @@ package config
 import (
 	"flag"
 	"fmt"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
+	"github.com/shopspring/decimal"
 )
 
 // ErrDatabaseURIRequired возвращается когда не задан обязательный параметр DATABASE_URI.
@@ -19,6 +21,45 @@ type AppConfig struct {
 	DatabaseURI          string `envconfig:"DATABASE_URI"`
 	AccrualSystemAddress string `envconfig:"ACCRUAL_SYSTEM_ADDRESS"`
 	JWTSecret            string `envconfig:"JWT_SECRET" default:"gophermart-secret-key"`
+
+	AccrualConcurrency       int `envconfig:"ACCRUAL_CONCURRENCY" default:"4"`
+	AccrualBatchSize         int `envconfig:"ACCRUAL_BATCH_SIZE" default:"100"`
+	AccrualRequestsPerMinute int `envconfig:"ACCRUAL_REQUESTS_PER_MINUTE" default:"0"`
+	AccrualRateBurst         int `envconfig:"ACCRUAL_RATE_BURST" default:"1"`
+
+	AccrualLeaseTTL    time.Duration `envconfig:"ACCRUAL_LEASE_TTL" default:"1m"`
+	AccrualMaxAttempts int           `envconfig:"ACCRUAL_MAX_ATTEMPTS" default:"0"`
+	AccrualMaxAge      time.Duration `envconfig:"ACCRUAL_MAX_AGE" default:"0"`
+
+	IdempotencyLockTTL       time.Duration `envconfig:"IDEMPOTENCY_LOCK_TTL" default:"1m"`
+	IdempotencyKeyTTL        time.Duration `envconfig:"IDEMPOTENCY_KEY_TTL" default:"24h"`
+	IdempotencyPurgeInterval time.Duration `envconfig:"IDEMPOTENCY_PURGE_INTERVAL" default:"1h"`
+
+	HoldTTL            time.Duration `envconfig:"HOLD_TTL" default:"15m"`
+	HoldExpireInterval time.Duration `envconfig:"HOLD_EXPIRE_INTERVAL" default:"1m"`
+
+	PointsTTLMonths      int           `envconfig:"POINTS_TTL_MONTHS" default:"0"`
+	PointsExpiringWindow time.Duration `envconfig:"POINTS_EXPIRING_WINDOW" default:"720h"`
+	PointsExpireInterval time.Duration `envconfig:"POINTS_EXPIRE_INTERVAL" default:"1h"`
+
+	TransferMaxAmount   decimal.Decimal `envconfig:"TRANSFER_MAX_AMOUNT" default:"10000"`
+	TransferDailyAmount decimal.Decimal `envconfig:"TRANSFER_DAILY_AMOUNT" default:"50000"`
+
+	WithdrawMaxAmount     decimal.Decimal `envconfig:"WITHDRAW_MAX_AMOUNT" default:"0"`
+	WithdrawDailyAmount   decimal.Decimal `envconfig:"WITHDRAW_DAILY_AMOUNT" default:"0"`
+	WithdrawMonthlyAmount decimal.Decimal `envconfig:"WITHDRAW_MONTHLY_AMOUNT" default:"0"`
+
+	TierSilverThreshold   decimal.Decimal `envconfig:"TIER_SILVER_THRESHOLD" default:"1000"`
+	TierSilverMultiplier  decimal.Decimal `envconfig:"TIER_SILVER_MULTIPLIER" default:"1.1"`
+	TierGoldThreshold     decimal.Decimal `envconfig:"TIER_GOLD_THRESHOLD" default:"5000"`
+	TierGoldMultiplier    decimal.Decimal `envconfig:"TIER_GOLD_MULTIPLIER" default:"1.25"`
+	TierRecomputeInterval time.Duration   `envconfig:"TIER_RECOMPUTE_INTERVAL" default:"1h"`
+
+	OrderNumberAlgorithms []string `envconfig:"ORDER_NUMBER_ALGORITHMS" default:"luhn"`
+
+	AdminToken          string        `envconfig:"ADMIN_TOKEN"`
+	ReconcileInterval   time.Duration `envconfig:"RECONCILE_INTERVAL" default:"1h"`
+	ReconcileAutoRepair bool          `envconfig:"RECONCILE_AUTO_REPAIR" default:"false"`
 }
 
 // LoadConfig загружает конфигурацию из переменных окружения и флагов командной строки.
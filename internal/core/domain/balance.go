@@ -3,8 +3,16 @@ package domain
 import "github.com/shopspring/decimal"
 
 // Balance представляет баланс пользователя в системе лояльности.
+//
+// Current содержит доступный остаток: баллы, заблокированные под оплату,
+// учитываются отдельно в OnHold и в Current не входят. ExpiringSoon
+// показывает, сколько баллов сгорит в ближайшее время. Tier содержит
+// текущий уровень пользователя в программе лояльности.
 type Balance struct {
-	UserID    int64
-	Current   decimal.Decimal
-	Withdrawn decimal.Decimal
+	UserID       int64
+	Current      decimal.Decimal
+	Withdrawn    decimal.Decimal
+	OnHold       decimal.Decimal
+	ExpiringSoon decimal.Decimal
+	Tier         Tier
 }
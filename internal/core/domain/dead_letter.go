@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// DeadLetterOrder представляет заказ, снятый с опроса системы начислений
+// после исчерпания попыток или срока ожидания. Заказ сохраняет свой статус
+// и возвращается в опрос только по запросу администратора.
+type DeadLetterOrder struct {
+	Order          *Order
+	LastError      string
+	DeadLetteredAt time.Time
+}
+
+// DeadLetterPage представляет страницу списка снятых с опроса заказов.
+// Next равен nil, если следующей страницы нет.
+type DeadLetterPage struct {
+	Orders []*DeadLetterOrder
+	Next   *PageCursor
+}
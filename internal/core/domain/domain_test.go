@@ -32,6 +32,11 @@ func TestOrderStatus_IsFinal(t *testing.T) {
 			status:   OrderStatusProcessed,
 			expected: true,
 		},
+		{
+			name:     "CANCELED является конечным",
+			status:   OrderStatusCanceled,
+			expected: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -40,3 +45,10 @@ func TestOrderStatus_IsFinal(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderStatus_IsCancelable(t *testing.T) {
+	assert.True(t, OrderStatusNew.IsCancelable())
+	for _, status := range []OrderStatus{OrderStatusProcessing, OrderStatusInvalid, OrderStatusProcessed, OrderStatusCanceled} {
+		assert.False(t, status.IsCancelable(), status)
+	}
+}
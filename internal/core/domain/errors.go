@@ -14,6 +14,19 @@ var (
 	ErrOrderNotFound = fmt.Errorf("заказ не найден")
 	// ErrOrderAlreadyExists возвращается при попытке создать дублирующий заказ.
 	ErrOrderAlreadyExists = fmt.Errorf("заказ уже существует")
+	// ErrOrderAlreadyFinal возвращается при попытке изменить статус заказа в конечном статусе.
+	ErrOrderAlreadyFinal = fmt.Errorf("заказ уже находится в конечном статусе")
+	// ErrOrderNotCancelable возвращается при попытке отменить заказ, обработка которого уже началась.
+	ErrOrderNotCancelable = fmt.Errorf("заказ нельзя отменить после начала обработки")
+	// ErrAccrualUnavailable возвращается, когда система начислений не ответила на запрос.
+	ErrAccrualUnavailable = fmt.Errorf("система начислений недоступна")
+	// ErrAccrualRateLimited возвращается, когда запрос к системе начислений
+	// превысил бы лимит частоты и не может быть выполнен сразу.
+	ErrAccrualRateLimited = fmt.Errorf("превышен лимит запросов к системе начислений")
+	// ErrAccrualAlreadyApplied возвращается при повторном начислении баллов за один заказ.
+	ErrAccrualAlreadyApplied = fmt.Errorf("начисление по заказу уже выполнено")
+	// ErrInvalidOrderBatch возвращается при пустом или слишком большом пакете заказов.
+	ErrInvalidOrderBatch = fmt.Errorf("пакет заказов пуст или превышает допустимый размер")
 	// ErrOrderBelongsToOther возвращается когда заказ принадлежит другому пользователю.
 	ErrOrderBelongsToOther = fmt.Errorf("заказ принадлежит другому пользователю")
 	// ErrInvalidOrderNumber возвращается при невалидном номере заказа.
@@ -22,4 +35,28 @@ var (
 	ErrInsufficientBalance = fmt.Errorf("недостаточно средств на счёте")
 	// ErrWithdrawalNotFound возвращается когда операция списания не найдена.
 	ErrWithdrawalNotFound = fmt.Errorf("списание не найдено")
+	// ErrRefundExceedsWithdrawal возвращается когда сумма возврата превышает невозвращённую часть списания.
+	ErrRefundExceedsWithdrawal = fmt.Errorf("сумма возврата превышает остаток списания")
+	// ErrInvalidRefundAmount возвращается при неположительной сумме возврата.
+	ErrInvalidRefundAmount = fmt.Errorf("сумма возврата должна быть положительной")
+	// ErrHoldNotFound возвращается когда блокировка баллов не найдена.
+	ErrHoldNotFound = fmt.Errorf("блокировка баллов не найдена")
+	// ErrHoldNotActive возвращается при попытке завершить уже завершённую или истёкшую блокировку.
+	ErrHoldNotActive = fmt.Errorf("блокировка баллов уже завершена или истекла")
+	// ErrTransferToSelf возвращается при попытке перевести баллы самому себе.
+	ErrTransferToSelf = fmt.Errorf("нельзя перевести баллы самому себе")
+	// ErrTransferLimitExceeded возвращается при превышении лимита переводов.
+	ErrTransferLimitExceeded = fmt.Errorf("превышен лимит переводов баллов")
+	// ErrInvalidListQuery возвращается при некорректных параметрах постраничной выборки.
+	ErrInvalidListQuery = fmt.Errorf("неверные параметры выборки")
+	// ErrWithdrawalLimitExceeded возвращается при превышении лимита списаний.
+	ErrWithdrawalLimitExceeded = fmt.Errorf("превышен лимит списаний баллов")
+	// ErrInvalidWithdrawalLimits возвращается при отрицательных значениях лимитов списаний.
+	ErrInvalidWithdrawalLimits = fmt.Errorf("лимиты списаний не могут быть отрицательными")
+	// ErrInvalidStatementQuery возвращается при некорректных параметрах выписки.
+	ErrInvalidStatementQuery = fmt.Errorf("неверные параметры выписки")
+	// ErrOrderLocked возвращается, если заказ арендован другим обработчиком.
+	ErrOrderLocked = fmt.Errorf("заказ уже обрабатывается, повторите позже")
+	// ErrAccrualNotFinal возвращается, если система начислений ещё не завершила расчёт по заказу.
+	ErrAccrualNotFinal = fmt.Errorf("система начислений ещё не завершила расчёт по заказу")
 )
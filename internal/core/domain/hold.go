@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// HoldStatus определяет статус блокировки баллов.
+type HoldStatus string
+
+const (
+	HoldStatusActive   HoldStatus = "ACTIVE"
+	HoldStatusCaptured HoldStatus = "CAPTURED"
+	HoldStatusReleased HoldStatus = "RELEASED"
+	HoldStatusExpired  HoldStatus = "EXPIRED"
+)
+
+// Hold представляет блокировку баллов под оплату заказа.
+//
+// Активная блокировка уменьшает доступный остаток, но не попадает в журнал
+// проводок. При подтверждении она превращается в списание, при отмене или
+// истечении срока баллы снова становятся доступны.
+type Hold struct {
+	ID          int64
+	UserID      int64
+	OrderNumber string
+	Amount      decimal.Decimal
+	Status      HoldStatus
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord представляет запрос, выполненный с ключом идемпотентности,
+// и сохранённый ответ на него.
+type IdempotencyRecord struct {
+	UserID       int64
+	Key          string
+	RequestHash  string
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+	CreatedAt    time.Time
+}
+
+// IsCompleted возвращает true, если ответ на запрос уже сохранён.
+// Пока StatusCode не задан, запрос считается выполняющимся.
+func (r *IdempotencyRecord) IsCompleted() bool {
+	return r.StatusCode != 0
+}
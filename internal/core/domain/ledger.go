@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// LedgerEntryKind определяет тип проводки в журнале баллов.
+type LedgerEntryKind string
+
+const (
+	LedgerEntryAccrual     LedgerEntryKind = "ACCRUAL"
+	LedgerEntryWithdrawal  LedgerEntryKind = "WITHDRAWAL"
+	LedgerEntryAdjustment  LedgerEntryKind = "ADJUSTMENT"
+	LedgerEntryRefund      LedgerEntryKind = "REFUND"
+	LedgerEntryExpiration  LedgerEntryKind = "EXPIRATION"
+	LedgerEntryTransferIn  LedgerEntryKind = "TRANSFER_IN"
+	LedgerEntryTransferOut LedgerEntryKind = "TRANSFER_OUT"
+	// LedgerEntryReconciliation — корректировка, записанная при исправлении
+	// расхождения журнала с заказами и списаниями.
+	LedgerEntryReconciliation LedgerEntryKind = "RECONCILIATION"
+	// LedgerEntryBonus — надбавка к начислению за уровень лояльности.
+	LedgerEntryBonus LedgerEntryKind = "BONUS"
+	// LedgerEntryAccrualCorrection — доначисление или отзыв баллов за заказ,
+	// начисление по которому изменилось после перепроверки.
+	LedgerEntryAccrualCorrection LedgerEntryKind = "ACCRUAL_CORRECTION"
+	// LedgerEntryBonusCorrection — пересчёт надбавки за уровень лояльности
+	// после изменения начисления за заказ.
+	LedgerEntryBonusCorrection LedgerEntryKind = "BONUS_CORRECTION"
+)
+
+// LedgerEntry представляет проводку в журнале движения баллов пользователя.
+//
+// Журнал ведётся только на добавление: положительная сумма увеличивает баланс,
+// отрицательная уменьшает. Баланс пользователя равен сумме всех его проводок.
+type LedgerEntry struct {
+	ID          int64
+	UserID      int64
+	Kind        LedgerEntryKind
+	Amount      decimal.Decimal
+	OrderNumber string
+	Description string
+	CreatedAt   time.Time
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PageCursor указывает на последнюю запись полученной страницы списка.
+// Записи упорядочены по времени и идентификатору, поэтому позиция
+// однозначна даже при совпадении времени у нескольких записей.
+type PageCursor struct {
+	Time time.Time
+	ID   int64
+}
+
+// String кодирует курсор в непрозрачную для клиента строку.
+func (c PageCursor) String() string {
+	raw := strconv.FormatInt(c.Time.UnixMicro(), 10) + ":" + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParsePageCursor разбирает курсор, полученный методом String.
+func ParsePageCursor(s string) (*PageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidListQuery, err)
+	}
+
+	micros, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, ErrInvalidListQuery
+	}
+
+	t, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidListQuery, err)
+	}
+
+	cursor := PageCursor{Time: time.UnixMicro(t)}
+	cursor.ID, err = strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidListQuery, err)
+	}
+
+	return &cursor, nil
+}
+
+// MaxListLimit задаёт наибольший допустимый размер страницы списка.
+const MaxListLimit = 500
+
+// ListQuery описывает параметры постраничной выборки заказов и списаний.
+//
+// From и To ограничивают период: From включительно, To не включительно;
+// nil снимает ограничение. Cursor продолжает выборку после записи предыдущей
+// страницы. Нулевой Limit возвращает все записи без разбиения на страницы.
+// По умолчанию записи упорядочены от новых к старым, Ascending меняет порядок.
+type ListQuery struct {
+	From      *time.Time
+	To        *time.Time
+	Cursor    *PageCursor
+	Limit     int
+	Ascending bool
+}
+
+// IsValid проверяет размер страницы и границы периода.
+func (q ListQuery) IsValid() bool {
+	if q.Limit < 0 || q.Limit > MaxListLimit {
+		return false
+	}
+	return q.From == nil || q.To == nil || q.From.Before(*q.To)
+}
+
+// OrderListQuery описывает параметры выборки заказов.
+// Statuses ограничивает выборку заказами в перечисленных статусах.
+type OrderListQuery struct {
+	ListQuery
+	Statuses []OrderStatus
+}
+
+// IsValid проверяет параметры выборки заказов.
+func (q OrderListQuery) IsValid() bool {
+	for _, status := range q.Statuses {
+		if !status.IsValid() {
+			return false
+		}
+	}
+	return q.ListQuery.IsValid()
+}
+
+// OrderPage представляет страницу списка заказов.
+// Next равен nil, если следующей страницы нет.
+type OrderPage struct {
+	Orders []*Order
+	Next   *PageCursor
+}
+
+// WithdrawalPage представляет страницу списка списаний.
+// Next равен nil, если следующей страницы нет.
+type WithdrawalPage struct {
+	Withdrawals []*Withdrawal
+	Next        *PageCursor
+}
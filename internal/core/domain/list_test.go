@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageCursor_RoundTrip(t *testing.T) {
+	cursor := PageCursor{Time: time.Date(2024, 1, 15, 10, 30, 0, 123456000, time.UTC), ID: 42}
+
+	parsed, err := ParsePageCursor(cursor.String())
+
+	require.NoError(t, err)
+	assert.True(t, cursor.Time.Equal(parsed.Time))
+	assert.Equal(t, cursor.ID, parsed.ID)
+}
+
+func TestParsePageCursor_Invalid(t *testing.T) {
+	for _, raw := range []string{"not base64!", "MTIz", "YWJjOjE", "MTIzOmFiYw"} {
+		_, err := ParsePageCursor(raw)
+		assert.ErrorIs(t, err, ErrInvalidListQuery, raw)
+	}
+}
+
+func TestOrderListQuery_IsValid(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name     string
+		query    OrderListQuery
+		expected bool
+	}{
+		{"пустой запрос", OrderListQuery{}, true},
+		{"корректный период и статусы", OrderListQuery{
+			ListQuery: ListQuery{From: &from, To: &to, Limit: 10},
+			Statuses:  []OrderStatus{OrderStatusNew, OrderStatusProcessed},
+		}, true},
+		{"отрицательный лимит", OrderListQuery{ListQuery: ListQuery{Limit: -1}}, false},
+		{"слишком большой лимит", OrderListQuery{ListQuery: ListQuery{Limit: MaxListLimit + 1}}, false},
+		{"перевёрнутый период", OrderListQuery{ListQuery: ListQuery{From: &to, To: &from}}, false},
+		{"неизвестный статус", OrderListQuery{Statuses: []OrderStatus{"DONE"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.query.IsValid())
+		})
+	}
+}
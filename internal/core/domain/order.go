@@ -14,9 +14,12 @@ const (
 	OrderStatusProcessing OrderStatus = "PROCESSING"
 	OrderStatusInvalid    OrderStatus = "INVALID"
 	OrderStatusProcessed  OrderStatus = "PROCESSED"
+	OrderStatusCanceled   OrderStatus = "CANCELED"
 )
 
 // Order представляет заказ пользователя в системе лояльности.
+// Attempts заполняется только при выборке необработанных и снятых с опроса
+// заказов и равен числу уже выполненных проверок заказа в системе начислений.
 type Order struct {
 	ID         int64
 	UserID     int64
@@ -24,9 +27,48 @@ type Order struct {
 	Status     OrderStatus
 	Accrual    *decimal.Decimal
 	UploadedAt time.Time
+	Attempts   int
 }
 
 // IsFinal возвращает true, если статус заказа является конечным.
 func (s OrderStatus) IsFinal() bool {
-	return s == OrderStatusInvalid || s == OrderStatusProcessed
+	return s == OrderStatusInvalid || s == OrderStatusProcessed || s == OrderStatusCanceled
+}
+
+// IsCancelable возвращает true, если заказ в этом статусе может быть отменён пользователем.
+func (s OrderStatus) IsCancelable() bool {
+	return s == OrderStatusNew
+}
+
+// IsValid возвращает true для известного статуса заказа.
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderStatusNew, OrderStatusProcessing, OrderStatusInvalid, OrderStatusProcessed, OrderStatusCanceled:
+		return true
+	}
+	return false
+}
+
+// OrderStatusChange представляет запись истории статусов заказа.
+type OrderStatusChange struct {
+	Status    OrderStatus
+	Accrual   *decimal.Decimal
+	ChangedAt time.Time
+}
+
+// OrderDetails представляет заказ вместе с историей его статусов
+// в хронологическом порядке и списаниями, оформленными на его номер.
+type OrderDetails struct {
+	Order       *Order
+	History     []*OrderStatusChange
+	Withdrawals []*Withdrawal
+}
+
+// OrderRecheck представляет результат перепроверки заказа в системе начислений.
+// Correction содержит проводку, изменившую начисление, или nil, если
+// начисление по заказу не изменилось.
+type OrderRecheck struct {
+	Previous   *Order
+	Order      *Order
+	Correction *LedgerEntry
 }
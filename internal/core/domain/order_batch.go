@@ -0,0 +1,23 @@
+package domain
+
+// OrderSubmitResult определяет результат загрузки номера заказа в пакете.
+type OrderSubmitResult string
+
+const (
+	// OrderSubmitAccepted означает, что заказ принят в обработку.
+	OrderSubmitAccepted OrderSubmitResult = "ACCEPTED"
+	// OrderSubmitAlreadyUploaded означает, что заказ уже загружен этим пользователем.
+	OrderSubmitAlreadyUploaded OrderSubmitResult = "ALREADY_UPLOADED"
+	// OrderSubmitBelongsToOther означает, что заказ загружен другим пользователем.
+	OrderSubmitBelongsToOther OrderSubmitResult = "BELONGS_TO_OTHER"
+	// OrderSubmitInvalid означает, что номер заказа не прошёл проверку.
+	OrderSubmitInvalid OrderSubmitResult = "INVALID"
+)
+
+// OrderSubmission представляет результат загрузки одного номера из пакета.
+// Для номеров, не прошедших проверку, Reason содержит причину отказа.
+type OrderSubmission struct {
+	Number string
+	Result OrderSubmitResult
+	Reason string
+}
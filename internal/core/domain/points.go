@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Accrual описывает начисление баллов пользователю за обработанный заказ.
+//
+// ExpiresAt задаёт момент сгорания начисленных баллов; nil означает,
+// что баллы бессрочные. Bonus содержит надбавку за уровень лояльности,
+// она записывается отдельной проводкой с тем же сроком действия.
+type Accrual struct {
+	UserID      int64
+	OrderNumber string
+	Amount      decimal.Decimal
+	Bonus       decimal.Decimal
+	ExpiresAt   *time.Time
+}
+
+// PointLot представляет партию баллов с собственным сроком действия.
+//
+// Каждое поступление баллов образует партию. Списания расходуют партии
+// в порядке FIFO: сначала те, что сгорают раньше. Неизрасходованный
+// остаток партии сгорает по истечении ExpiresAt.
+type PointLot struct {
+	ID        int64
+	UserID    int64
+	Amount    decimal.Decimal
+	Remaining decimal.Decimal
+	ExpiresAt *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BalanceMismatch описывает расхождение баланса пользователя с исходными данными.
+//
+// Ожидаемые значения рассчитываются по обработанным заказам, списаниям
+// и возвратам, фактические — по журналу проводок. Блокировки баллов
+// в сверке не участвуют. Adjustment заполняется, если расхождение
+// остатка исправлено корректирующей проводкой.
+type BalanceMismatch struct {
+	UserID            int64
+	ExpectedCurrent   decimal.Decimal
+	ActualCurrent     decimal.Decimal
+	ExpectedWithdrawn decimal.Decimal
+	ActualWithdrawn   decimal.Decimal
+	Adjustment        *LedgerEntry
+}
+
+// CurrentDiff возвращает сумму, на которую нужно скорректировать остаток,
+// чтобы он совпал с ожидаемым.
+func (m *BalanceMismatch) CurrentDiff() decimal.Decimal {
+	return m.ExpectedCurrent.Sub(m.ActualCurrent)
+}
+
+// ReconciliationReport представляет результат сверки балансов.
+type ReconciliationReport struct {
+	CheckedAt  time.Time
+	Mismatches []*BalanceMismatch
+}
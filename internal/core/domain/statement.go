@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// StatementQuery описывает параметры выборки выписки по счёту.
+//
+// From и To ограничивают период по времени проводки: From включительно,
+// To не включительно; nil снимает ограничение. Cursor содержит идентификатор
+// последней полученной проводки, выборка продолжается после неё.
+type StatementQuery struct {
+	From   *time.Time
+	To     *time.Time
+	Cursor int64
+	Limit  int
+}
+
+// StatementEntry представляет строку выписки: проводку и остаток баллов после неё.
+type StatementEntry struct {
+	LedgerEntry
+	Balance decimal.Decimal
+}
+
+// Statement представляет страницу выписки по счёту.
+//
+// NextCursor равен нулю, если следующей страницы нет.
+type Statement struct {
+	Entries    []*StatementEntry
+	NextCursor int64
+}
+
+// HistoryRecord представляет запись истории по счёту для выгрузки: заказ
+// или списание. Заполнено ровно одно из полей.
+type HistoryRecord struct {
+	Order      *Order
+	Withdrawal *Withdrawal
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Tier определяет уровень участника программы лояльности.
+type Tier string
+
+const (
+	TierBronze Tier = "BRONZE"
+	TierSilver Tier = "SILVER"
+	TierGold   Tier = "GOLD"
+)
+
+// DefaultTier назначается пользователю, для которого уровень ещё не рассчитан.
+const DefaultTier = TierBronze
+
+// UserTier представляет рассчитанный уровень пользователя.
+//
+// LifetimeAccrued содержит сумму всех начислений за заказы с учётом
+// корректировок после перепроверки, но без бонусов уровня; по ней
+// и определяется уровень.
+type UserTier struct {
+	UserID          int64
+	Tier            Tier
+	LifetimeAccrued decimal.Decimal
+	UpdatedAt       time.Time
+}
+
+// TierRule задаёт порог суммы начислений, с которого действует уровень,
+// и множитель начислений для него.
+type TierRule struct {
+	Tier       Tier
+	Threshold  decimal.Decimal
+	Multiplier decimal.Decimal
+}
+
+// TierPolicy содержит правила уровней в порядке возрастания порога.
+type TierPolicy []TierRule
+
+// Rule возвращает правило уровня tier. Для неизвестного уровня возвращается
+// правило с множителем 1.
+func (p TierPolicy) Rule(tier Tier) TierRule {
+	for _, rule := range p {
+		if rule.Tier == tier {
+			return rule
+		}
+	}
+	return TierRule{Tier: tier, Multiplier: decimal.NewFromInt(1)}
+}
+
+// Bonus возвращает бонус к начислению amount для уровня tier,
+// округлённый до копеек вниз.
+func (p TierPolicy) Bonus(tier Tier, amount decimal.Decimal) decimal.Decimal {
+	multiplier := p.Rule(tier).Multiplier
+	if multiplier.LessThanOrEqual(decimal.NewFromInt(1)) {
+		return decimal.Zero
+	}
+	return amount.Mul(multiplier.Sub(decimal.NewFromInt(1))).RoundFloor(2)
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTierPolicy_Bonus(t *testing.T) {
+	policy := TierPolicy{
+		{Tier: TierBronze, Threshold: decimal.Zero, Multiplier: decimal.NewFromInt(1)},
+		{Tier: TierSilver, Threshold: decimal.NewFromInt(1000), Multiplier: decimal.RequireFromString("1.1")},
+		{Tier: TierGold, Threshold: decimal.NewFromInt(5000), Multiplier: decimal.RequireFromString("1.25")},
+	}
+
+	tests := []struct {
+		name     string
+		tier     Tier
+		amount   string
+		expected string
+	}{
+		{
+			name:     "BRONZE без надбавки",
+			tier:     TierBronze,
+			amount:   "100",
+			expected: "0",
+		},
+		{
+			name:     "SILVER добавляет 10%",
+			tier:     TierSilver,
+			amount:   "100",
+			expected: "10",
+		},
+		{
+			name:     "GOLD округляет вниз до копеек",
+			tier:     TierGold,
+			amount:   "10.05",
+			expected: "2.51",
+		},
+		{
+			name:     "неизвестный уровень без надбавки",
+			tier:     Tier("PLATINUM"),
+			amount:   "100",
+			expected: "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bonus := policy.Bonus(tt.tier, decimal.RequireFromString(tt.amount))
+			assert.True(t, decimal.RequireFromString(tt.expected).Equal(bonus), bonus.String())
+		})
+	}
+}
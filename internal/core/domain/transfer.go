@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Transfer представляет перевод баллов от одного пользователя другому.
+type Transfer struct {
+	ID         int64
+	FromUserID int64
+	ToUserID   int64
+	Amount     decimal.Decimal
+	CreatedAt  time.Time
+}
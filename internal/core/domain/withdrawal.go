@@ -12,5 +12,20 @@ type Withdrawal struct {
 	UserID      int64
 	OrderNumber string
 	Sum         decimal.Decimal
+	Refunded    decimal.Decimal
 	ProcessedAt time.Time
 }
+
+// Refundable возвращает сумму списания, которую ещё можно вернуть.
+func (w *Withdrawal) Refundable() decimal.Decimal {
+	return w.Sum.Sub(w.Refunded)
+}
+
+// Refund представляет возврат баллов по ранее выполненному списанию.
+type Refund struct {
+	ID           int64
+	WithdrawalID int64
+	UserID       int64
+	Sum          decimal.Decimal
+	ProcessedAt  time.Time
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// WithdrawalLimits задаёт лимиты списаний пользователя: на одно списание,
+// за последние сутки и за последний месяц. Нулевое значение снимает
+// соответствующий лимит.
+type WithdrawalLimits struct {
+	PerTransaction decimal.Decimal
+	Daily          decimal.Decimal
+	Monthly        decimal.Decimal
+}
+
+// IsValid проверяет, что лимиты не отрицательны.
+func (l WithdrawalLimits) IsValid() bool {
+	return !l.PerTransaction.IsNegative() && !l.Daily.IsNegative() && !l.Monthly.IsNegative()
+}
+
+// Check проверяет, что списание amount укладывается в лимиты с учётом уже
+// списанного за сутки и за месяц.
+func (l WithdrawalLimits) Check(amount, withdrawnDay, withdrawnMonth decimal.Decimal) error {
+	if exceeds(l.PerTransaction, amount) ||
+		exceeds(l.Daily, withdrawnDay.Add(amount)) ||
+		exceeds(l.Monthly, withdrawnMonth.Add(amount)) {
+		return ErrWithdrawalLimitExceeded
+	}
+	return nil
+}
+
+func exceeds(limit, amount decimal.Decimal) bool {
+	return limit.IsPositive() && amount.GreaterThan(limit)
+}
+
+// WithdrawalLimitsOverride представляет индивидуальные лимиты списаний,
+// назначенные пользователю администратором. Незаданное поле означает,
+// что действует лимит по умолчанию.
+type WithdrawalLimitsOverride struct {
+	UserID         int64
+	PerTransaction *decimal.Decimal
+	Daily          *decimal.Decimal
+	Monthly        *decimal.Decimal
+	UpdatedAt      time.Time
+}
+
+// Apply возвращает лимиты defaults с учётом индивидуальных значений.
+// Для nil возвращаются defaults без изменений.
+func (o *WithdrawalLimitsOverride) Apply(defaults WithdrawalLimits) WithdrawalLimits {
+	if o == nil {
+		return defaults
+	}
+
+	limits := defaults
+	if o.PerTransaction != nil {
+		limits.PerTransaction = *o.PerTransaction
+	}
+	if o.Daily != nil {
+		limits.Daily = *o.Daily
+	}
+	if o.Monthly != nil {
+		limits.Monthly = *o.Monthly
+	}
+	return limits
+}
+
+// UserWithdrawalLimits описывает лимиты списаний, действующие для пользователя.
+type UserWithdrawalLimits struct {
+	UserID     int64
+	Limits     WithdrawalLimits
+	Overridden bool
+}
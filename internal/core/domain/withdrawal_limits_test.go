@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithdrawalLimits_Check(t *testing.T) {
+	limits := WithdrawalLimits{
+		PerTransaction: decimal.NewFromInt(500),
+		Daily:          decimal.NewFromInt(1000),
+		Monthly:        decimal.NewFromInt(3000),
+	}
+
+	tests := []struct {
+		name           string
+		limits         WithdrawalLimits
+		amount         int64
+		withdrawnDay   int64
+		withdrawnMonth int64
+		wantErr        error
+	}{
+		{"в пределах лимитов", limits, 500, 500, 2500, nil},
+		{"превышен лимит на списание", limits, 501, 0, 0, ErrWithdrawalLimitExceeded},
+		{"превышен суточный лимит", limits, 100, 950, 950, ErrWithdrawalLimitExceeded},
+		{"превышен месячный лимит", limits, 100, 0, 2950, ErrWithdrawalLimitExceeded},
+		{"нулевые лимиты не ограничивают", WithdrawalLimits{}, 100000, 100000, 100000, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limits.Check(
+				decimal.NewFromInt(tt.amount),
+				decimal.NewFromInt(tt.withdrawnDay),
+				decimal.NewFromInt(tt.withdrawnMonth),
+			)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWithdrawalLimitsOverride_Apply(t *testing.T) {
+	defaults := WithdrawalLimits{
+		PerTransaction: decimal.NewFromInt(500),
+		Daily:          decimal.NewFromInt(1000),
+		Monthly:        decimal.NewFromInt(3000),
+	}
+
+	t.Run("nil возвращает лимиты по умолчанию", func(t *testing.T) {
+		var override *WithdrawalLimitsOverride
+		assert.Equal(t, defaults, override.Apply(defaults))
+	})
+
+	t.Run("заданные поля заменяют лимиты по умолчанию", func(t *testing.T) {
+		daily := decimal.NewFromInt(5000)
+		unlimited := decimal.Zero
+		override := &WithdrawalLimitsOverride{Daily: &daily, Monthly: &unlimited}
+
+		limits := override.Apply(defaults)
+
+		assert.True(t, defaults.PerTransaction.Equal(limits.PerTransaction))
+		assert.True(t, daily.Equal(limits.Daily))
+		assert.True(t, limits.Monthly.IsZero())
+	})
+}
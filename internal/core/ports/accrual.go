@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/shopspring/decimal"
@@ -17,6 +18,14 @@ type AccrualResponse struct {
 }
 
 // AccrualClient определяет контракт для взаимодействия с системой начислений.
+//
+// Все запросы проходят через общий ограничитель частоты. GetOrderAccrual
+// дожидается разрешения на запрос, а TryGetOrderAccrual при исчерпанном
+// лимите сразу возвращает ошибку с временем, через которое стоит повторить
+// запрос. PausedFor возвращает оставшееся время паузы после ответа
+// 429 Too Many Requests.
 type AccrualClient interface {
 	GetOrderAccrual(ctx context.Context, orderNumber string) (*AccrualResponse, error)
+	TryGetOrderAccrual(ctx context.Context, orderNumber string) (*AccrualResponse, error)
+	PausedFor() time.Duration
 }
@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	ports "github.com/arvaliullin/gophermart/internal/core/ports"
 	gomock "go.uber.org/mock/gomock"
@@ -55,3 +56,32 @@ func (mr *MockAccrualClientMockRecorder) GetOrderAccrual(ctx, orderNumber any) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderAccrual", reflect.TypeOf((*MockAccrualClient)(nil).GetOrderAccrual), ctx, orderNumber)
 }
+
+// PausedFor mocks base method.
+func (m *MockAccrualClient) PausedFor() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PausedFor")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// PausedFor indicates an expected call of PausedFor.
+func (mr *MockAccrualClientMockRecorder) PausedFor() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PausedFor", reflect.TypeOf((*MockAccrualClient)(nil).PausedFor))
+}
+
+// TryGetOrderAccrual mocks base method.
+func (m *MockAccrualClient) TryGetOrderAccrual(ctx context.Context, orderNumber string) (*ports.AccrualResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryGetOrderAccrual", ctx, orderNumber)
+	ret0, _ := ret[0].(*ports.AccrualResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TryGetOrderAccrual indicates an expected call of TryGetOrderAccrual.
+func (mr *MockAccrualClientMockRecorder) TryGetOrderAccrual(ctx, orderNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryGetOrderAccrual", reflect.TypeOf((*MockAccrualClient)(nil).TryGetOrderAccrual), ctx, orderNumber)
+}
@@ -12,8 +12,10 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/arvaliullin/gophermart/internal/core/domain"
+	ports "github.com/arvaliullin/gophermart/internal/core/ports"
 	decimal "github.com/shopspring/decimal"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -111,6 +113,35 @@ func (m *MockOrderRepository) EXPECT() *MockOrderRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Cancel mocks base method.
+func (m *MockOrderRepository) Cancel(ctx context.Context, userID int64, number string) (*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, userID, number)
+	ret0, _ := ret[0].(*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockOrderRepositoryMockRecorder) Cancel(ctx, userID, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockOrderRepository)(nil).Cancel), ctx, userID, number)
+}
+
+// ClaimLease mocks base method.
+func (m *MockOrderRepository) ClaimLease(ctx context.Context, orderID int64, lease time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimLease", ctx, orderID, lease)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClaimLease indicates an expected call of ClaimLease.
+func (mr *MockOrderRepositoryMockRecorder) ClaimLease(ctx, orderID, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimLease", reflect.TypeOf((*MockOrderRepository)(nil).ClaimLease), ctx, orderID, lease)
+}
+
 // Create mocks base method.
 func (m *MockOrderRepository) Create(ctx context.Context, userID int64, number string) (*domain.Order, error) {
 	m.ctrl.T.Helper()
@@ -126,6 +157,35 @@ func (mr *MockOrderRepositoryMockRecorder) Create(ctx, userID, number any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrderRepository)(nil).Create), ctx, userID, number)
 }
 
+// CreateBatch mocks base method.
+func (m *MockOrderRepository) CreateBatch(ctx context.Context, userID int64, numbers []string) ([]*domain.OrderSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, userID, numbers)
+	ret0, _ := ret[0].([]*domain.OrderSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockOrderRepositoryMockRecorder) CreateBatch(ctx, userID, numbers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockOrderRepository)(nil).CreateBatch), ctx, userID, numbers)
+}
+
+// DeadLetter mocks base method.
+func (m *MockOrderRepository) DeadLetter(ctx context.Context, orderID int64, attempts int, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetter", ctx, orderID, attempts, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeadLetter indicates an expected call of DeadLetter.
+func (mr *MockOrderRepositoryMockRecorder) DeadLetter(ctx, orderID, attempts, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetter", reflect.TypeOf((*MockOrderRepository)(nil).DeadLetter), ctx, orderID, attempts, lastError)
+}
+
 // GetByNumber mocks base method.
 func (m *MockOrderRepository) GetByNumber(ctx context.Context, number string) (*domain.Order, error) {
 	m.ctrl.T.Helper()
@@ -157,32 +217,135 @@ func (mr *MockOrderRepositoryMockRecorder) GetByUserID(ctx, userID any) *gomock.
 }
 
 // GetPendingOrders mocks base method.
-func (m *MockOrderRepository) GetPendingOrders(ctx context.Context) ([]*domain.Order, error) {
+func (m *MockOrderRepository) GetPendingOrders(ctx context.Context, limit int, lease time.Duration) ([]*domain.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPendingOrders", ctx)
+	ret := m.ctrl.Call(m, "GetPendingOrders", ctx, limit, lease)
 	ret0, _ := ret[0].([]*domain.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPendingOrders indicates an expected call of GetPendingOrders.
-func (mr *MockOrderRepositoryMockRecorder) GetPendingOrders(ctx any) *gomock.Call {
+func (mr *MockOrderRepositoryMockRecorder) GetPendingOrders(ctx, limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingOrders", reflect.TypeOf((*MockOrderRepository)(nil).GetPendingOrders), ctx, limit, lease)
+}
+
+// GetStatusHistory mocks base method.
+func (m *MockOrderRepository) GetStatusHistory(ctx context.Context, orderID int64) ([]*domain.OrderStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusHistory", ctx, orderID)
+	ret0, _ := ret[0].([]*domain.OrderStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusHistory indicates an expected call of GetStatusHistory.
+func (mr *MockOrderRepositoryMockRecorder) GetStatusHistory(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusHistory", reflect.TypeOf((*MockOrderRepository)(nil).GetStatusHistory), ctx, orderID)
+}
+
+// List mocks base method.
+func (m *MockOrderRepository) List(ctx context.Context, userID int64, query domain.OrderListQuery) ([]*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID, query)
+	ret0, _ := ret[0].([]*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockOrderRepositoryMockRecorder) List(ctx, userID, query any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingOrders", reflect.TypeOf((*MockOrderRepository)(nil).GetPendingOrders), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOrderRepository)(nil).List), ctx, userID, query)
+}
+
+// ListDeadLetters mocks base method.
+func (m *MockOrderRepository) ListDeadLetters(ctx context.Context, query domain.ListQuery) ([]*domain.DeadLetterOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", ctx, query)
+	ret0, _ := ret[0].([]*domain.DeadLetterOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockOrderRepositoryMockRecorder) ListDeadLetters(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockOrderRepository)(nil).ListDeadLetters), ctx, query)
+}
+
+// ReleaseLease mocks base method.
+func (m *MockOrderRepository) ReleaseLease(ctx context.Context, orderID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseLease", ctx, orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseLease indicates an expected call of ReleaseLease.
+func (mr *MockOrderRepositoryMockRecorder) ReleaseLease(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseLease", reflect.TypeOf((*MockOrderRepository)(nil).ReleaseLease), ctx, orderID)
+}
+
+// Reopen mocks base method.
+func (m *MockOrderRepository) Reopen(ctx context.Context, orderID int64) (*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reopen", ctx, orderID)
+	ret0, _ := ret[0].(*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reopen indicates an expected call of Reopen.
+func (mr *MockOrderRepositoryMockRecorder) Reopen(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reopen", reflect.TypeOf((*MockOrderRepository)(nil).Reopen), ctx, orderID)
+}
+
+// Requeue mocks base method.
+func (m *MockOrderRepository) Requeue(ctx context.Context, number string) (*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Requeue", ctx, number)
+	ret0, _ := ret[0].(*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Requeue indicates an expected call of Requeue.
+func (mr *MockOrderRepositoryMockRecorder) Requeue(ctx, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Requeue", reflect.TypeOf((*MockOrderRepository)(nil).Requeue), ctx, number)
+}
+
+// Reschedule mocks base method.
+func (m *MockOrderRepository) Reschedule(ctx context.Context, orderID int64, attempts int, delay time.Duration, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reschedule", ctx, orderID, attempts, delay, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reschedule indicates an expected call of Reschedule.
+func (mr *MockOrderRepositoryMockRecorder) Reschedule(ctx, orderID, attempts, delay, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reschedule", reflect.TypeOf((*MockOrderRepository)(nil).Reschedule), ctx, orderID, attempts, delay, lastError)
 }
 
 // UpdateStatus mocks base method.
-func (m *MockOrderRepository) UpdateStatus(ctx context.Context, number string, status domain.OrderStatus, accrual *decimal.Decimal) error {
+func (m *MockOrderRepository) UpdateStatus(ctx context.Context, orderID int64, status domain.OrderStatus, accrual *decimal.Decimal) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateStatus", ctx, number, status, accrual)
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, orderID, status, accrual)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateStatus indicates an expected call of UpdateStatus.
-func (mr *MockOrderRepositoryMockRecorder) UpdateStatus(ctx, number, status, accrual any) *gomock.Call {
+func (mr *MockOrderRepositoryMockRecorder) UpdateStatus(ctx, orderID, status, accrual any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockOrderRepository)(nil).UpdateStatus), ctx, number, status, accrual)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockOrderRepository)(nil).UpdateStatus), ctx, orderID, status, accrual)
 }
 
 // MockBalanceRepository is a mock of BalanceRepository interface.
@@ -210,17 +373,32 @@ func (m *MockBalanceRepository) EXPECT() *MockBalanceRepositoryMockRecorder {
 }
 
 // AddAccrual mocks base method.
-func (m *MockBalanceRepository) AddAccrual(ctx context.Context, userID int64, amount decimal.Decimal) error {
+func (m *MockBalanceRepository) AddAccrual(ctx context.Context, accrual *domain.Accrual) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddAccrual", ctx, userID, amount)
+	ret := m.ctrl.Call(m, "AddAccrual", ctx, accrual)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddAccrual indicates an expected call of AddAccrual.
-func (mr *MockBalanceRepositoryMockRecorder) AddAccrual(ctx, userID, amount any) *gomock.Call {
+func (mr *MockBalanceRepositoryMockRecorder) AddAccrual(ctx, accrual any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccrual", reflect.TypeOf((*MockBalanceRepository)(nil).AddAccrual), ctx, accrual)
+}
+
+// CaptureHold mocks base method.
+func (m *MockBalanceRepository) CaptureHold(ctx context.Context, holdID int64, limits domain.WithdrawalLimits) (*domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", ctx, holdID, limits)
+	ret0, _ := ret[0].(*domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockBalanceRepositoryMockRecorder) CaptureHold(ctx, holdID, limits any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccrual", reflect.TypeOf((*MockBalanceRepository)(nil).AddAccrual), ctx, userID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockBalanceRepository)(nil).CaptureHold), ctx, holdID, limits)
 }
 
 // CreateForUser mocks base method.
@@ -237,6 +415,36 @@ func (mr *MockBalanceRepositoryMockRecorder) CreateForUser(ctx, userID any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateForUser", reflect.TypeOf((*MockBalanceRepository)(nil).CreateForUser), ctx, userID)
 }
 
+// ExpireHolds mocks base method.
+func (m *MockBalanceRepository) ExpireHolds(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireHolds", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireHolds indicates an expected call of ExpireHolds.
+func (mr *MockBalanceRepositoryMockRecorder) ExpireHolds(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireHolds", reflect.TypeOf((*MockBalanceRepository)(nil).ExpireHolds), ctx)
+}
+
+// ExpirePoints mocks base method.
+func (m *MockBalanceRepository) ExpirePoints(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePoints", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePoints indicates an expected call of ExpirePoints.
+func (mr *MockBalanceRepositoryMockRecorder) ExpirePoints(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePoints", reflect.TypeOf((*MockBalanceRepository)(nil).ExpirePoints), ctx)
+}
+
 // GetByUserID mocks base method.
 func (m *MockBalanceRepository) GetByUserID(ctx context.Context, userID int64) (*domain.Balance, error) {
 	m.ctrl.T.Helper()
@@ -252,18 +460,108 @@ func (mr *MockBalanceRepositoryMockRecorder) GetByUserID(ctx, userID any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockBalanceRepository)(nil).GetByUserID), ctx, userID)
 }
 
+// GetExpiring mocks base method.
+func (m *MockBalanceRepository) GetExpiring(ctx context.Context, userID int64, before time.Time) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiring", ctx, userID, before)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiring indicates an expected call of GetExpiring.
+func (mr *MockBalanceRepositoryMockRecorder) GetExpiring(ctx, userID, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiring", reflect.TypeOf((*MockBalanceRepository)(nil).GetExpiring), ctx, userID, before)
+}
+
+// Hold mocks base method.
+func (m *MockBalanceRepository) Hold(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal, expiresAt time.Time, limits domain.WithdrawalLimits) (*domain.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Hold", ctx, userID, orderNumber, amount, expiresAt, limits)
+	ret0, _ := ret[0].(*domain.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Hold indicates an expected call of Hold.
+func (mr *MockBalanceRepositoryMockRecorder) Hold(ctx, userID, orderNumber, amount, expiresAt, limits any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Hold", reflect.TypeOf((*MockBalanceRepository)(nil).Hold), ctx, userID, orderNumber, amount, expiresAt, limits)
+}
+
+// Refund mocks base method.
+func (m *MockBalanceRepository) Refund(ctx context.Context, withdrawalID int64, amount *decimal.Decimal) (*domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, withdrawalID, amount)
+	ret0, _ := ret[0].(*domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockBalanceRepositoryMockRecorder) Refund(ctx, withdrawalID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockBalanceRepository)(nil).Refund), ctx, withdrawalID, amount)
+}
+
+// ReleaseHold mocks base method.
+func (m *MockBalanceRepository) ReleaseHold(ctx context.Context, holdID int64) (*domain.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHold", ctx, holdID)
+	ret0, _ := ret[0].(*domain.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseHold indicates an expected call of ReleaseHold.
+func (mr *MockBalanceRepositoryMockRecorder) ReleaseHold(ctx, holdID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHold", reflect.TypeOf((*MockBalanceRepository)(nil).ReleaseHold), ctx, holdID)
+}
+
+// SettleAccrual mocks base method.
+func (m *MockBalanceRepository) SettleAccrual(ctx context.Context, accrual *domain.Accrual, description string) (*domain.LedgerEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettleAccrual", ctx, accrual, description)
+	ret0, _ := ret[0].(*domain.LedgerEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SettleAccrual indicates an expected call of SettleAccrual.
+func (mr *MockBalanceRepositoryMockRecorder) SettleAccrual(ctx, accrual, description any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleAccrual", reflect.TypeOf((*MockBalanceRepository)(nil).SettleAccrual), ctx, accrual, description)
+}
+
+// Transfer mocks base method.
+func (m *MockBalanceRepository) Transfer(ctx context.Context, fromUserID, toUserID int64, amount, dailyLimit decimal.Decimal) (*domain.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", ctx, fromUserID, toUserID, amount, dailyLimit)
+	ret0, _ := ret[0].(*domain.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockBalanceRepositoryMockRecorder) Transfer(ctx, fromUserID, toUserID, amount, dailyLimit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockBalanceRepository)(nil).Transfer), ctx, fromUserID, toUserID, amount, dailyLimit)
+}
+
 // Withdraw mocks base method.
-func (m *MockBalanceRepository) Withdraw(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal) error {
+func (m *MockBalanceRepository) Withdraw(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal, limits domain.WithdrawalLimits) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Withdraw", ctx, userID, orderNumber, amount)
+	ret := m.ctrl.Call(m, "Withdraw", ctx, userID, orderNumber, amount, limits)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Withdraw indicates an expected call of Withdraw.
-func (mr *MockBalanceRepositoryMockRecorder) Withdraw(ctx, userID, orderNumber, amount any) *gomock.Call {
+func (mr *MockBalanceRepositoryMockRecorder) Withdraw(ctx, userID, orderNumber, amount, limits any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockBalanceRepository)(nil).Withdraw), ctx, userID, orderNumber, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockBalanceRepository)(nil).Withdraw), ctx, userID, orderNumber, amount, limits)
 }
 
 // MockWithdrawalRepository is a mock of WithdrawalRepository interface.
@@ -290,6 +588,21 @@ func (m *MockWithdrawalRepository) EXPECT() *MockWithdrawalRepositoryMockRecorde
 	return m.recorder
 }
 
+// GetByOrderNumber mocks base method.
+func (m *MockWithdrawalRepository) GetByOrderNumber(ctx context.Context, userID int64, orderNumber string) ([]*domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOrderNumber", ctx, userID, orderNumber)
+	ret0, _ := ret[0].([]*domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOrderNumber indicates an expected call of GetByOrderNumber.
+func (mr *MockWithdrawalRepositoryMockRecorder) GetByOrderNumber(ctx, userID, orderNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOrderNumber", reflect.TypeOf((*MockWithdrawalRepository)(nil).GetByOrderNumber), ctx, userID, orderNumber)
+}
+
 // GetByUserID mocks base method.
 func (m *MockWithdrawalRepository) GetByUserID(ctx context.Context, userID int64) ([]*domain.Withdrawal, error) {
 	m.ctrl.T.Helper()
@@ -304,3 +617,500 @@ func (mr *MockWithdrawalRepositoryMockRecorder) GetByUserID(ctx, userID any) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockWithdrawalRepository)(nil).GetByUserID), ctx, userID)
 }
+
+// List mocks base method.
+func (m *MockWithdrawalRepository) List(ctx context.Context, userID int64, query domain.ListQuery) ([]*domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID, query)
+	ret0, _ := ret[0].([]*domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWithdrawalRepositoryMockRecorder) List(ctx, userID, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWithdrawalRepository)(nil).List), ctx, userID, query)
+}
+
+// MockHistoryRepository is a mock of HistoryRepository interface.
+type MockHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockHistoryRepositoryMockRecorder is the mock recorder for MockHistoryRepository.
+type MockHistoryRepositoryMockRecorder struct {
+	mock *MockHistoryRepository
+}
+
+// NewMockHistoryRepository creates a new mock instance.
+func NewMockHistoryRepository(ctrl *gomock.Controller) *MockHistoryRepository {
+	mock := &MockHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryRepository) EXPECT() *MockHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// StreamByUserID mocks base method.
+func (m *MockHistoryRepository) StreamByUserID(ctx context.Context, userID int64, from, to *time.Time, fn func(*domain.HistoryRecord) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamByUserID", ctx, userID, from, to, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamByUserID indicates an expected call of StreamByUserID.
+func (mr *MockHistoryRepositoryMockRecorder) StreamByUserID(ctx, userID, from, to, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamByUserID", reflect.TypeOf((*MockHistoryRepository)(nil).StreamByUserID), ctx, userID, from, to, fn)
+}
+
+// MockLedgerRepository is a mock of LedgerRepository interface.
+type MockLedgerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockLedgerRepositoryMockRecorder is the mock recorder for MockLedgerRepository.
+type MockLedgerRepositoryMockRecorder struct {
+	mock *MockLedgerRepository
+}
+
+// NewMockLedgerRepository creates a new mock instance.
+func NewMockLedgerRepository(ctrl *gomock.Controller) *MockLedgerRepository {
+	mock := &MockLedgerRepository{ctrl: ctrl}
+	mock.recorder = &MockLedgerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLedgerRepository) EXPECT() *MockLedgerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByUserID mocks base method.
+func (m *MockLedgerRepository) GetByUserID(ctx context.Context, userID int64) ([]*domain.LedgerEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*domain.LedgerEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockLedgerRepositoryMockRecorder) GetByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockLedgerRepository)(nil).GetByUserID), ctx, userID)
+}
+
+// GetStatement mocks base method.
+func (m *MockLedgerRepository) GetStatement(ctx context.Context, userID int64, query domain.StatementQuery) ([]*domain.StatementEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatement", ctx, userID, query)
+	ret0, _ := ret[0].([]*domain.StatementEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatement indicates an expected call of GetStatement.
+func (mr *MockLedgerRepositoryMockRecorder) GetStatement(ctx, userID, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatement", reflect.TypeOf((*MockLedgerRepository)(nil).GetStatement), ctx, userID, query)
+}
+
+// MockReconciliationRepository is a mock of ReconciliationRepository interface.
+type MockReconciliationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReconciliationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockReconciliationRepositoryMockRecorder is the mock recorder for MockReconciliationRepository.
+type MockReconciliationRepositoryMockRecorder struct {
+	mock *MockReconciliationRepository
+}
+
+// NewMockReconciliationRepository creates a new mock instance.
+func NewMockReconciliationRepository(ctrl *gomock.Controller) *MockReconciliationRepository {
+	mock := &MockReconciliationRepository{ctrl: ctrl}
+	mock.recorder = &MockReconciliationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReconciliationRepository) EXPECT() *MockReconciliationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindMismatches mocks base method.
+func (m *MockReconciliationRepository) FindMismatches(ctx context.Context) ([]*domain.BalanceMismatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMismatches", ctx)
+	ret0, _ := ret[0].([]*domain.BalanceMismatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMismatches indicates an expected call of FindMismatches.
+func (mr *MockReconciliationRepositoryMockRecorder) FindMismatches(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMismatches", reflect.TypeOf((*MockReconciliationRepository)(nil).FindMismatches), ctx)
+}
+
+// Repair mocks base method.
+func (m *MockReconciliationRepository) Repair(ctx context.Context, userID int64) (*domain.LedgerEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Repair", ctx, userID)
+	ret0, _ := ret[0].(*domain.LedgerEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Repair indicates an expected call of Repair.
+func (mr *MockReconciliationRepositoryMockRecorder) Repair(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Repair", reflect.TypeOf((*MockReconciliationRepository)(nil).Repair), ctx, userID)
+}
+
+// MockTierRepository is a mock of TierRepository interface.
+type MockTierRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTierRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTierRepositoryMockRecorder is the mock recorder for MockTierRepository.
+type MockTierRepositoryMockRecorder struct {
+	mock *MockTierRepository
+}
+
+// NewMockTierRepository creates a new mock instance.
+func NewMockTierRepository(ctrl *gomock.Controller) *MockTierRepository {
+	mock := &MockTierRepository{ctrl: ctrl}
+	mock.recorder = &MockTierRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTierRepository) EXPECT() *MockTierRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByUserID mocks base method.
+func (m *MockTierRepository) GetByUserID(ctx context.Context, userID int64) (*domain.UserTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID)
+	ret0, _ := ret[0].(*domain.UserTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockTierRepositoryMockRecorder) GetByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockTierRepository)(nil).GetByUserID), ctx, userID)
+}
+
+// Recompute mocks base method.
+func (m *MockTierRepository) Recompute(ctx context.Context, policy domain.TierPolicy) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recompute", ctx, policy)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recompute indicates an expected call of Recompute.
+func (mr *MockTierRepositoryMockRecorder) Recompute(ctx, policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recompute", reflect.TypeOf((*MockTierRepository)(nil).Recompute), ctx, policy)
+}
+
+// MockWithdrawalLimitRepository is a mock of WithdrawalLimitRepository interface.
+type MockWithdrawalLimitRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWithdrawalLimitRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockWithdrawalLimitRepositoryMockRecorder is the mock recorder for MockWithdrawalLimitRepository.
+type MockWithdrawalLimitRepositoryMockRecorder struct {
+	mock *MockWithdrawalLimitRepository
+}
+
+// NewMockWithdrawalLimitRepository creates a new mock instance.
+func NewMockWithdrawalLimitRepository(ctrl *gomock.Controller) *MockWithdrawalLimitRepository {
+	mock := &MockWithdrawalLimitRepository{ctrl: ctrl}
+	mock.recorder = &MockWithdrawalLimitRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWithdrawalLimitRepository) EXPECT() *MockWithdrawalLimitRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockWithdrawalLimitRepository) Delete(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWithdrawalLimitRepositoryMockRecorder) Delete(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWithdrawalLimitRepository)(nil).Delete), ctx, userID)
+}
+
+// GetByUserID mocks base method.
+func (m *MockWithdrawalLimitRepository) GetByUserID(ctx context.Context, userID int64) (*domain.WithdrawalLimitsOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID)
+	ret0, _ := ret[0].(*domain.WithdrawalLimitsOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockWithdrawalLimitRepositoryMockRecorder) GetByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockWithdrawalLimitRepository)(nil).GetByUserID), ctx, userID)
+}
+
+// Set mocks base method.
+func (m *MockWithdrawalLimitRepository) Set(ctx context.Context, override *domain.WithdrawalLimitsOverride) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, override)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockWithdrawalLimitRepositoryMockRecorder) Set(ctx, override any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockWithdrawalLimitRepository)(nil).Set), ctx, override)
+}
+
+// MockIdempotencyRepository is a mock of IdempotencyRepository interface.
+type MockIdempotencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyRepositoryMockRecorder is the mock recorder for MockIdempotencyRepository.
+type MockIdempotencyRepositoryMockRecorder struct {
+	mock *MockIdempotencyRepository
+}
+
+// NewMockIdempotencyRepository creates a new mock instance.
+func NewMockIdempotencyRepository(ctrl *gomock.Controller) *MockIdempotencyRepository {
+	mock := &MockIdempotencyRepository{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyRepository) EXPECT() *MockIdempotencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Complete mocks base method.
+func (m *MockIdempotencyRepository) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyRepositoryMockRecorder) Complete(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotencyRepository)(nil).Complete), ctx, record)
+}
+
+// Extend mocks base method.
+func (m *MockIdempotencyRepository) Extend(ctx context.Context, userID int64, key string, lockTTL time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Extend", ctx, userID, key, lockTTL)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Extend indicates an expected call of Extend.
+func (mr *MockIdempotencyRepositoryMockRecorder) Extend(ctx, userID, key, lockTTL any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Extend", reflect.TypeOf((*MockIdempotencyRepository)(nil).Extend), ctx, userID, key, lockTTL)
+}
+
+// PurgeExpired mocks base method.
+func (m *MockIdempotencyRepository) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockIdempotencyRepositoryMockRecorder) PurgeExpired(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockIdempotencyRepository)(nil).PurgeExpired), ctx, before)
+}
+
+// Release mocks base method.
+func (m *MockIdempotencyRepository) Release(ctx context.Context, userID int64, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, userID, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIdempotencyRepositoryMockRecorder) Release(ctx, userID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIdempotencyRepository)(nil).Release), ctx, userID, key)
+}
+
+// Reserve mocks base method.
+func (m *MockIdempotencyRepository) Reserve(ctx context.Context, userID int64, key, requestHash string, lockTTL time.Duration) (*domain.IdempotencyRecord, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, userID, key, requestHash, lockTTL)
+	ret0, _ := ret[0].(*domain.IdempotencyRecord)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockIdempotencyRepositoryMockRecorder) Reserve(ctx, userID, key, requestHash, lockTTL any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockIdempotencyRepository)(nil).Reserve), ctx, userID, key, requestHash, lockTTL)
+}
+
+// MockTxRepositories is a mock of TxRepositories interface.
+type MockTxRepositories struct {
+	ctrl     *gomock.Controller
+	recorder *MockTxRepositoriesMockRecorder
+	isgomock struct{}
+}
+
+// MockTxRepositoriesMockRecorder is the mock recorder for MockTxRepositories.
+type MockTxRepositoriesMockRecorder struct {
+	mock *MockTxRepositories
+}
+
+// NewMockTxRepositories creates a new mock instance.
+func NewMockTxRepositories(ctrl *gomock.Controller) *MockTxRepositories {
+	mock := &MockTxRepositories{ctrl: ctrl}
+	mock.recorder = &MockTxRepositoriesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTxRepositories) EXPECT() *MockTxRepositoriesMockRecorder {
+	return m.recorder
+}
+
+// Balances mocks base method.
+func (m *MockTxRepositories) Balances() ports.BalanceRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Balances")
+	ret0, _ := ret[0].(ports.BalanceRepository)
+	return ret0
+}
+
+// Balances indicates an expected call of Balances.
+func (mr *MockTxRepositoriesMockRecorder) Balances() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Balances", reflect.TypeOf((*MockTxRepositories)(nil).Balances))
+}
+
+// Ledger mocks base method.
+func (m *MockTxRepositories) Ledger() ports.LedgerRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ledger")
+	ret0, _ := ret[0].(ports.LedgerRepository)
+	return ret0
+}
+
+// Ledger indicates an expected call of Ledger.
+func (mr *MockTxRepositoriesMockRecorder) Ledger() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ledger", reflect.TypeOf((*MockTxRepositories)(nil).Ledger))
+}
+
+// Orders mocks base method.
+func (m *MockTxRepositories) Orders() ports.OrderRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Orders")
+	ret0, _ := ret[0].(ports.OrderRepository)
+	return ret0
+}
+
+// Orders indicates an expected call of Orders.
+func (mr *MockTxRepositoriesMockRecorder) Orders() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Orders", reflect.TypeOf((*MockTxRepositories)(nil).Orders))
+}
+
+// Reconciliation mocks base method.
+func (m *MockTxRepositories) Reconciliation() ports.ReconciliationRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconciliation")
+	ret0, _ := ret[0].(ports.ReconciliationRepository)
+	return ret0
+}
+
+// Reconciliation indicates an expected call of Reconciliation.
+func (mr *MockTxRepositoriesMockRecorder) Reconciliation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconciliation", reflect.TypeOf((*MockTxRepositories)(nil).Reconciliation))
+}
+
+// MockUnitOfWork is a mock of UnitOfWork interface.
+type MockUnitOfWork struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkMockRecorder
+	isgomock struct{}
+}
+
+// MockUnitOfWorkMockRecorder is the mock recorder for MockUnitOfWork.
+type MockUnitOfWorkMockRecorder struct {
+	mock *MockUnitOfWork
+}
+
+// NewMockUnitOfWork creates a new mock instance.
+func NewMockUnitOfWork(ctrl *gomock.Controller) *MockUnitOfWork {
+	mock := &MockUnitOfWork{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWork) EXPECT() *MockUnitOfWorkMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockUnitOfWork) Do(ctx context.Context, fn func(context.Context, ports.TxRepositories) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Do", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockUnitOfWorkMockRecorder) Do(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockUnitOfWork)(nil).Do), ctx, fn)
+}
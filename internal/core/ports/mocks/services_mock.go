@@ -12,8 +12,10 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/arvaliullin/gophermart/internal/core/domain"
+	ports "github.com/arvaliullin/gophermart/internal/core/ports"
 	decimal "github.com/shopspring/decimal"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -96,6 +98,36 @@ func (m *MockOrderService) EXPECT() *MockOrderServiceMockRecorder {
 	return m.recorder
 }
 
+// CancelOrder mocks base method.
+func (m *MockOrderService) CancelOrder(ctx context.Context, userID int64, number string) (*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrder", ctx, userID, number)
+	ret0, _ := ret[0].(*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOrder indicates an expected call of CancelOrder.
+func (mr *MockOrderServiceMockRecorder) CancelOrder(ctx, userID, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockOrderService)(nil).CancelOrder), ctx, userID, number)
+}
+
+// GetOrder mocks base method.
+func (m *MockOrderService) GetOrder(ctx context.Context, userID int64, number string) (*domain.OrderDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", ctx, userID, number)
+	ret0, _ := ret[0].(*domain.OrderDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockOrderServiceMockRecorder) GetOrder(ctx, userID, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrderService)(nil).GetOrder), ctx, userID, number)
+}
+
 // GetUserOrders mocks base method.
 func (m *MockOrderService) GetUserOrders(ctx context.Context, userID int64) ([]*domain.Order, error) {
 	m.ctrl.T.Helper()
@@ -111,6 +143,21 @@ func (mr *MockOrderServiceMockRecorder) GetUserOrders(ctx, userID any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOrders", reflect.TypeOf((*MockOrderService)(nil).GetUserOrders), ctx, userID)
 }
 
+// ListOrders mocks base method.
+func (m *MockOrderService) ListOrders(ctx context.Context, userID int64, query domain.OrderListQuery) (*domain.OrderPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", ctx, userID, query)
+	ret0, _ := ret[0].(*domain.OrderPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockOrderServiceMockRecorder) ListOrders(ctx, userID, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockOrderService)(nil).ListOrders), ctx, userID, query)
+}
+
 // SubmitOrder mocks base method.
 func (m *MockOrderService) SubmitOrder(ctx context.Context, userID int64, number string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -126,6 +173,21 @@ func (mr *MockOrderServiceMockRecorder) SubmitOrder(ctx, userID, number any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitOrder", reflect.TypeOf((*MockOrderService)(nil).SubmitOrder), ctx, userID, number)
 }
 
+// SubmitOrders mocks base method.
+func (m *MockOrderService) SubmitOrders(ctx context.Context, userID int64, numbers []string) ([]*domain.OrderSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitOrders", ctx, userID, numbers)
+	ret0, _ := ret[0].([]*domain.OrderSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitOrders indicates an expected call of SubmitOrders.
+func (mr *MockOrderServiceMockRecorder) SubmitOrders(ctx, userID, numbers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitOrders", reflect.TypeOf((*MockOrderService)(nil).SubmitOrders), ctx, userID, numbers)
+}
+
 // MockBalanceService is a mock of BalanceService interface.
 type MockBalanceService struct {
 	ctrl     *gomock.Controller
@@ -150,6 +212,21 @@ func (m *MockBalanceService) EXPECT() *MockBalanceServiceMockRecorder {
 	return m.recorder
 }
 
+// CaptureHold mocks base method.
+func (m *MockBalanceService) CaptureHold(ctx context.Context, holdID int64) (*domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", ctx, holdID)
+	ret0, _ := ret[0].(*domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockBalanceServiceMockRecorder) CaptureHold(ctx, holdID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockBalanceService)(nil).CaptureHold), ctx, holdID)
+}
+
 // GetBalance mocks base method.
 func (m *MockBalanceService) GetBalance(ctx context.Context, userID int64) (*domain.Balance, error) {
 	m.ctrl.T.Helper()
@@ -180,6 +257,81 @@ func (mr *MockBalanceServiceMockRecorder) GetWithdrawals(ctx, userID any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithdrawals", reflect.TypeOf((*MockBalanceService)(nil).GetWithdrawals), ctx, userID)
 }
 
+// Hold mocks base method.
+func (m *MockBalanceService) Hold(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal) (*domain.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Hold", ctx, userID, orderNumber, amount)
+	ret0, _ := ret[0].(*domain.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Hold indicates an expected call of Hold.
+func (mr *MockBalanceServiceMockRecorder) Hold(ctx, userID, orderNumber, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Hold", reflect.TypeOf((*MockBalanceService)(nil).Hold), ctx, userID, orderNumber, amount)
+}
+
+// ListWithdrawals mocks base method.
+func (m *MockBalanceService) ListWithdrawals(ctx context.Context, userID int64, query domain.ListQuery) (*domain.WithdrawalPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWithdrawals", ctx, userID, query)
+	ret0, _ := ret[0].(*domain.WithdrawalPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWithdrawals indicates an expected call of ListWithdrawals.
+func (mr *MockBalanceServiceMockRecorder) ListWithdrawals(ctx, userID, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithdrawals", reflect.TypeOf((*MockBalanceService)(nil).ListWithdrawals), ctx, userID, query)
+}
+
+// Refund mocks base method.
+func (m *MockBalanceService) Refund(ctx context.Context, withdrawalID int64, amount *decimal.Decimal) (*domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, withdrawalID, amount)
+	ret0, _ := ret[0].(*domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockBalanceServiceMockRecorder) Refund(ctx, withdrawalID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockBalanceService)(nil).Refund), ctx, withdrawalID, amount)
+}
+
+// ReleaseHold mocks base method.
+func (m *MockBalanceService) ReleaseHold(ctx context.Context, holdID int64) (*domain.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHold", ctx, holdID)
+	ret0, _ := ret[0].(*domain.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseHold indicates an expected call of ReleaseHold.
+func (mr *MockBalanceServiceMockRecorder) ReleaseHold(ctx, holdID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHold", reflect.TypeOf((*MockBalanceService)(nil).ReleaseHold), ctx, holdID)
+}
+
+// Transfer mocks base method.
+func (m *MockBalanceService) Transfer(ctx context.Context, fromUserID int64, toLogin string, amount decimal.Decimal) (*domain.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", ctx, fromUserID, toLogin, amount)
+	ret0, _ := ret[0].(*domain.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockBalanceServiceMockRecorder) Transfer(ctx, fromUserID, toLogin, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockBalanceService)(nil).Transfer), ctx, fromUserID, toLogin, amount)
+}
+
 // Withdraw mocks base method.
 func (m *MockBalanceService) Withdraw(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal) error {
 	m.ctrl.T.Helper()
@@ -193,3 +345,362 @@ func (mr *MockBalanceServiceMockRecorder) Withdraw(ctx, userID, orderNumber, amo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockBalanceService)(nil).Withdraw), ctx, userID, orderNumber, amount)
 }
+
+// MockStatementService is a mock of StatementService interface.
+type MockStatementService struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatementServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockStatementServiceMockRecorder is the mock recorder for MockStatementService.
+type MockStatementServiceMockRecorder struct {
+	mock *MockStatementService
+}
+
+// NewMockStatementService creates a new mock instance.
+func NewMockStatementService(ctrl *gomock.Controller) *MockStatementService {
+	mock := &MockStatementService{ctrl: ctrl}
+	mock.recorder = &MockStatementServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatementService) EXPECT() *MockStatementServiceMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockStatementService) Export(ctx context.Context, userID int64, from, to *time.Time, sink ports.ExportSink) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, userID, from, to, sink)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockStatementServiceMockRecorder) Export(ctx, userID, from, to, sink any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockStatementService)(nil).Export), ctx, userID, from, to, sink)
+}
+
+// GetStatement mocks base method.
+func (m *MockStatementService) GetStatement(ctx context.Context, userID int64, query domain.StatementQuery) (*domain.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatement", ctx, userID, query)
+	ret0, _ := ret[0].(*domain.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatement indicates an expected call of GetStatement.
+func (mr *MockStatementServiceMockRecorder) GetStatement(ctx, userID, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatement", reflect.TypeOf((*MockStatementService)(nil).GetStatement), ctx, userID, query)
+}
+
+// MockReconciliationService is a mock of ReconciliationService interface.
+type MockReconciliationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReconciliationServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockReconciliationServiceMockRecorder is the mock recorder for MockReconciliationService.
+type MockReconciliationServiceMockRecorder struct {
+	mock *MockReconciliationService
+}
+
+// NewMockReconciliationService creates a new mock instance.
+func NewMockReconciliationService(ctrl *gomock.Controller) *MockReconciliationService {
+	mock := &MockReconciliationService{ctrl: ctrl}
+	mock.recorder = &MockReconciliationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReconciliationService) EXPECT() *MockReconciliationServiceMockRecorder {
+	return m.recorder
+}
+
+// Repair mocks base method.
+func (m *MockReconciliationService) Repair(ctx context.Context) (*domain.ReconciliationReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Repair", ctx)
+	ret0, _ := ret[0].(*domain.ReconciliationReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Repair indicates an expected call of Repair.
+func (mr *MockReconciliationServiceMockRecorder) Repair(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Repair", reflect.TypeOf((*MockReconciliationService)(nil).Repair), ctx)
+}
+
+// Report mocks base method.
+func (m *MockReconciliationService) Report(ctx context.Context) (*domain.ReconciliationReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Report", ctx)
+	ret0, _ := ret[0].(*domain.ReconciliationReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockReconciliationServiceMockRecorder) Report(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockReconciliationService)(nil).Report), ctx)
+}
+
+// MockWithdrawalLimitService is a mock of WithdrawalLimitService interface.
+type MockWithdrawalLimitService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWithdrawalLimitServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockWithdrawalLimitServiceMockRecorder is the mock recorder for MockWithdrawalLimitService.
+type MockWithdrawalLimitServiceMockRecorder struct {
+	mock *MockWithdrawalLimitService
+}
+
+// NewMockWithdrawalLimitService creates a new mock instance.
+func NewMockWithdrawalLimitService(ctrl *gomock.Controller) *MockWithdrawalLimitService {
+	mock := &MockWithdrawalLimitService{ctrl: ctrl}
+	mock.recorder = &MockWithdrawalLimitServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWithdrawalLimitService) EXPECT() *MockWithdrawalLimitServiceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockWithdrawalLimitService) Get(ctx context.Context, userID int64) (*domain.UserWithdrawalLimits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID)
+	ret0, _ := ret[0].(*domain.UserWithdrawalLimits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWithdrawalLimitServiceMockRecorder) Get(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWithdrawalLimitService)(nil).Get), ctx, userID)
+}
+
+// Reset mocks base method.
+func (m *MockWithdrawalLimitService) Reset(ctx context.Context, userID int64) (*domain.UserWithdrawalLimits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, userID)
+	ret0, _ := ret[0].(*domain.UserWithdrawalLimits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockWithdrawalLimitServiceMockRecorder) Reset(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockWithdrawalLimitService)(nil).Reset), ctx, userID)
+}
+
+// Set mocks base method.
+func (m *MockWithdrawalLimitService) Set(ctx context.Context, override *domain.WithdrawalLimitsOverride) (*domain.UserWithdrawalLimits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, override)
+	ret0, _ := ret[0].(*domain.UserWithdrawalLimits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockWithdrawalLimitServiceMockRecorder) Set(ctx, override any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockWithdrawalLimitService)(nil).Set), ctx, override)
+}
+
+// MockOrderRecheckService is a mock of OrderRecheckService interface.
+type MockOrderRecheckService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderRecheckServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockOrderRecheckServiceMockRecorder is the mock recorder for MockOrderRecheckService.
+type MockOrderRecheckServiceMockRecorder struct {
+	mock *MockOrderRecheckService
+}
+
+// NewMockOrderRecheckService creates a new mock instance.
+func NewMockOrderRecheckService(ctrl *gomock.Controller) *MockOrderRecheckService {
+	mock := &MockOrderRecheckService{ctrl: ctrl}
+	mock.recorder = &MockOrderRecheckServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderRecheckService) EXPECT() *MockOrderRecheckServiceMockRecorder {
+	return m.recorder
+}
+
+// Recheck mocks base method.
+func (m *MockOrderRecheckService) Recheck(ctx context.Context, number string) (*domain.OrderRecheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recheck", ctx, number)
+	ret0, _ := ret[0].(*domain.OrderRecheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recheck indicates an expected call of Recheck.
+func (mr *MockOrderRecheckServiceMockRecorder) Recheck(ctx, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recheck", reflect.TypeOf((*MockOrderRecheckService)(nil).Recheck), ctx, number)
+}
+
+// MockDeadLetterService is a mock of DeadLetterService interface.
+type MockDeadLetterService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockDeadLetterServiceMockRecorder is the mock recorder for MockDeadLetterService.
+type MockDeadLetterServiceMockRecorder struct {
+	mock *MockDeadLetterService
+}
+
+// NewMockDeadLetterService creates a new mock instance.
+func NewMockDeadLetterService(ctrl *gomock.Controller) *MockDeadLetterService {
+	mock := &MockDeadLetterService{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterService) EXPECT() *MockDeadLetterServiceMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockDeadLetterService) List(ctx context.Context, query domain.ListQuery) (*domain.DeadLetterPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, query)
+	ret0, _ := ret[0].(*domain.DeadLetterPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockDeadLetterServiceMockRecorder) List(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDeadLetterService)(nil).List), ctx, query)
+}
+
+// Requeue mocks base method.
+func (m *MockDeadLetterService) Requeue(ctx context.Context, number string) (*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Requeue", ctx, number)
+	ret0, _ := ret[0].(*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Requeue indicates an expected call of Requeue.
+func (mr *MockDeadLetterServiceMockRecorder) Requeue(ctx, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Requeue", reflect.TypeOf((*MockDeadLetterService)(nil).Requeue), ctx, number)
+}
+
+// MockOrderNumberValidator is a mock of OrderNumberValidator interface.
+type MockOrderNumberValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderNumberValidatorMockRecorder
+	isgomock struct{}
+}
+
+// MockOrderNumberValidatorMockRecorder is the mock recorder for MockOrderNumberValidator.
+type MockOrderNumberValidatorMockRecorder struct {
+	mock *MockOrderNumberValidator
+}
+
+// NewMockOrderNumberValidator creates a new mock instance.
+func NewMockOrderNumberValidator(ctrl *gomock.Controller) *MockOrderNumberValidator {
+	mock := &MockOrderNumberValidator{ctrl: ctrl}
+	mock.recorder = &MockOrderNumberValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderNumberValidator) EXPECT() *MockOrderNumberValidatorMockRecorder {
+	return m.recorder
+}
+
+// Validate mocks base method.
+func (m *MockOrderNumberValidator) Validate(number string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", number)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockOrderNumberValidatorMockRecorder) Validate(number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockOrderNumberValidator)(nil).Validate), number)
+}
+
+// MockExportSink is a mock of ExportSink interface.
+type MockExportSink struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportSinkMockRecorder
+	isgomock struct{}
+}
+
+// MockExportSinkMockRecorder is the mock recorder for MockExportSink.
+type MockExportSinkMockRecorder struct {
+	mock *MockExportSink
+}
+
+// NewMockExportSink creates a new mock instance.
+func NewMockExportSink(ctrl *gomock.Controller) *MockExportSink {
+	mock := &MockExportSink{ctrl: ctrl}
+	mock.recorder = &MockExportSinkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportSink) EXPECT() *MockExportSinkMockRecorder {
+	return m.recorder
+}
+
+// WriteOrder mocks base method.
+func (m *MockExportSink) WriteOrder(order *domain.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteOrder", order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteOrder indicates an expected call of WriteOrder.
+func (mr *MockExportSinkMockRecorder) WriteOrder(order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteOrder", reflect.TypeOf((*MockExportSink)(nil).WriteOrder), order)
+}
+
+// WriteWithdrawal mocks base method.
+func (m *MockExportSink) WriteWithdrawal(withdrawal *domain.Withdrawal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteWithdrawal", withdrawal)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteWithdrawal indicates an expected call of WriteWithdrawal.
+func (mr *MockExportSinkMockRecorder) WriteWithdrawal(withdrawal any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteWithdrawal", reflect.TypeOf((*MockExportSink)(nil).WriteWithdrawal), withdrawal)
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/shopspring/decimal"
@@ -17,23 +18,121 @@ type UserRepository interface {
 }
 
 // OrderRepository определяет контракт для работы с заказами.
+//
+// CreateBatch создаёт заказы пользователя за один запрос к хранилищу и
+// возвращает результат по каждому уникальному номеру.
+//
+// UpdateStatus и Reopen находят заказ по идентификатору: после отмены номер
+// может принадлежать нескольким заказам. UpdateStatus записывает каждый
+// фактический переход в историю статусов, которую возвращает GetStatusHistory.
 type OrderRepository interface {
 	Create(ctx context.Context, userID int64, number string) (*domain.Order, error)
+	CreateBatch(ctx context.Context, userID int64, numbers []string) ([]*domain.OrderSubmission, error)
 	GetByNumber(ctx context.Context, number string) (*domain.Order, error)
 	GetByUserID(ctx context.Context, userID int64) ([]*domain.Order, error)
-	GetPendingOrders(ctx context.Context) ([]*domain.Order, error)
-	UpdateStatus(ctx context.Context, number string, status domain.OrderStatus, accrual *decimal.Decimal) error
+	List(ctx context.Context, userID int64, query domain.OrderListQuery) ([]*domain.Order, error)
+	GetPendingOrders(ctx context.Context, limit int, lease time.Duration) ([]*domain.Order, error)
+	ClaimLease(ctx context.Context, orderID int64, lease time.Duration) error
+	ReleaseLease(ctx context.Context, orderID int64) error
+	Reschedule(ctx context.Context, orderID int64, attempts int, delay time.Duration, lastError string) error
+	DeadLetter(ctx context.Context, orderID int64, attempts int, lastError string) error
+	ListDeadLetters(ctx context.Context, query domain.ListQuery) ([]*domain.DeadLetterOrder, error)
+	Requeue(ctx context.Context, number string) (*domain.Order, error)
+	UpdateStatus(ctx context.Context, orderID int64, status domain.OrderStatus, accrual *decimal.Decimal) error
+	GetStatusHistory(ctx context.Context, orderID int64) ([]*domain.OrderStatusChange, error)
+	Cancel(ctx context.Context, userID int64, number string) (*domain.Order, error)
+	Reopen(ctx context.Context, orderID int64) (*domain.Order, error)
 }
 
 // BalanceRepository определяет контракт для работы с балансом.
 type BalanceRepository interface {
 	GetByUserID(ctx context.Context, userID int64) (*domain.Balance, error)
 	CreateForUser(ctx context.Context, userID int64) error
-	AddAccrual(ctx context.Context, userID int64, amount decimal.Decimal) error
-	Withdraw(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal) error
+	AddAccrual(ctx context.Context, accrual *domain.Accrual) error
+	SettleAccrual(ctx context.Context, accrual *domain.Accrual, description string) (*domain.LedgerEntry, error)
+	Withdraw(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal, limits domain.WithdrawalLimits) error
+	Refund(ctx context.Context, withdrawalID int64, amount *decimal.Decimal) (*domain.Refund, error)
+	Hold(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal, expiresAt time.Time, limits domain.WithdrawalLimits) (*domain.Hold, error)
+	CaptureHold(ctx context.Context, holdID int64, limits domain.WithdrawalLimits) (*domain.Withdrawal, error)
+	ReleaseHold(ctx context.Context, holdID int64) (*domain.Hold, error)
+	ExpireHolds(ctx context.Context) (int64, error)
+	GetExpiring(ctx context.Context, userID int64, before time.Time) (decimal.Decimal, error)
+	ExpirePoints(ctx context.Context) (int64, error)
+	Transfer(ctx context.Context, fromUserID, toUserID int64, amount, dailyLimit decimal.Decimal) (*domain.Transfer, error)
 }
 
 // WithdrawalRepository определяет контракт для работы со списаниями.
 type WithdrawalRepository interface {
 	GetByUserID(ctx context.Context, userID int64) ([]*domain.Withdrawal, error)
+	GetByOrderNumber(ctx context.Context, userID int64, orderNumber string) ([]*domain.Withdrawal, error)
+	List(ctx context.Context, userID int64, query domain.ListQuery) ([]*domain.Withdrawal, error)
+}
+
+// HistoryRepository определяет контракт выгрузки истории по счёту.
+//
+// StreamByUserID передаёт заказы и списания пользователя в fn одной
+// хронологической лентой по мере чтения из хранилища, не загружая выборку
+// в память целиком. Все записи соответствуют одному моменту времени.
+// Ошибка fn прерывает чтение.
+type HistoryRepository interface {
+	StreamByUserID(ctx context.Context, userID int64, from, to *time.Time, fn func(*domain.HistoryRecord) error) error
+}
+
+// LedgerRepository определяет контракт для работы с журналом проводок по баллам.
+type LedgerRepository interface {
+	GetByUserID(ctx context.Context, userID int64) ([]*domain.LedgerEntry, error)
+	GetStatement(ctx context.Context, userID int64, query domain.StatementQuery) ([]*domain.StatementEntry, error)
+}
+
+// ReconciliationRepository определяет контракт сверки журнала проводок
+// с заказами и списаниями.
+//
+// Repair пересчитывает расхождение пользователя под блокировкой счёта
+// и записывает корректирующую проводку; nil означает, что исправлять нечего.
+type ReconciliationRepository interface {
+	FindMismatches(ctx context.Context) ([]*domain.BalanceMismatch, error)
+	Repair(ctx context.Context, userID int64) (*domain.LedgerEntry, error)
+}
+
+// TierRepository определяет контракт для работы с уровнями лояльности пользователей.
+type TierRepository interface {
+	GetByUserID(ctx context.Context, userID int64) (*domain.UserTier, error)
+	Recompute(ctx context.Context, policy domain.TierPolicy) (int64, error)
+}
+
+// WithdrawalLimitRepository определяет контракт для работы с индивидуальными
+// лимитами списаний. GetByUserID возвращает nil, если лимиты не назначены.
+type WithdrawalLimitRepository interface {
+	GetByUserID(ctx context.Context, userID int64) (*domain.WithdrawalLimitsOverride, error)
+	Set(ctx context.Context, override *domain.WithdrawalLimitsOverride) error
+	Delete(ctx context.Context, userID int64) error
+}
+
+// IdempotencyRepository определяет контракт для хранения запросов с ключом идемпотентности.
+//
+// Reserve атомарно занимает ключ за пользователем на lockTTL. Если ключ уже
+// занят, возвращается существующая запись и false. Extend продлевает
+// блокировку незавершённого ключа, пока запрос ещё выполняется.
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, userID int64, key, requestHash string, lockTTL time.Duration) (*domain.IdempotencyRecord, bool, error)
+	Extend(ctx context.Context, userID int64, key string, lockTTL time.Duration) error
+	Complete(ctx context.Context, record *domain.IdempotencyRecord) error
+	Release(ctx context.Context, userID int64, key string) error
+	PurgeExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// TxRepositories предоставляет репозитории, работающие в рамках одной транзакции.
+type TxRepositories interface {
+	Orders() OrderRepository
+	Balances() BalanceRepository
+	Ledger() LedgerRepository
+	Reconciliation() ReconciliationRepository
+}
+
+// UnitOfWork определяет контракт для атомарного выполнения операций над несколькими репозиториями.
+//
+// Все изменения, сделанные через TxRepositories внутри fn, фиксируются вместе,
+// если fn вернула nil, и откатываются целиком в противном случае.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context, tx TxRepositories) error) error
 }
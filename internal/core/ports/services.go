@@ -4,6 +4,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/shopspring/decimal"
@@ -18,7 +19,11 @@ type AuthService interface {
 // OrderService определяет контракт сервиса заказов.
 type OrderService interface {
 	SubmitOrder(ctx context.Context, userID int64, number string) (bool, error)
+	SubmitOrders(ctx context.Context, userID int64, numbers []string) ([]*domain.OrderSubmission, error)
 	GetUserOrders(ctx context.Context, userID int64) ([]*domain.Order, error)
+	ListOrders(ctx context.Context, userID int64, query domain.OrderListQuery) (*domain.OrderPage, error)
+	GetOrder(ctx context.Context, userID int64, number string) (*domain.OrderDetails, error)
+	CancelOrder(ctx context.Context, userID int64, number string) (*domain.Order, error)
 }
 
 // BalanceService определяет контракт сервиса баланса.
@@ -26,4 +31,55 @@ type BalanceService interface {
 	GetBalance(ctx context.Context, userID int64) (*domain.Balance, error)
 	Withdraw(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal) error
 	GetWithdrawals(ctx context.Context, userID int64) ([]*domain.Withdrawal, error)
+	ListWithdrawals(ctx context.Context, userID int64, query domain.ListQuery) (*domain.WithdrawalPage, error)
+	Refund(ctx context.Context, withdrawalID int64, amount *decimal.Decimal) (*domain.Refund, error)
+	Hold(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal) (*domain.Hold, error)
+	CaptureHold(ctx context.Context, holdID int64) (*domain.Withdrawal, error)
+	ReleaseHold(ctx context.Context, holdID int64) (*domain.Hold, error)
+	Transfer(ctx context.Context, fromUserID int64, toLogin string, amount decimal.Decimal) (*domain.Transfer, error)
+}
+
+// StatementService определяет контракт сервиса выписки по счёту.
+type StatementService interface {
+	GetStatement(ctx context.Context, userID int64, query domain.StatementQuery) (*domain.Statement, error)
+	Export(ctx context.Context, userID int64, from, to *time.Time, sink ExportSink) error
+}
+
+// ReconciliationService определяет контракт сервиса сверки балансов.
+type ReconciliationService interface {
+	Report(ctx context.Context) (*domain.ReconciliationReport, error)
+	Repair(ctx context.Context) (*domain.ReconciliationReport, error)
+}
+
+// WithdrawalLimitService определяет контракт сервиса администрирования
+// индивидуальных лимитов списаний.
+type WithdrawalLimitService interface {
+	Get(ctx context.Context, userID int64) (*domain.UserWithdrawalLimits, error)
+	Set(ctx context.Context, override *domain.WithdrawalLimitsOverride) (*domain.UserWithdrawalLimits, error)
+	Reset(ctx context.Context, userID int64) (*domain.UserWithdrawalLimits, error)
+}
+
+// OrderRecheckService определяет контракт административной перепроверки
+// заказа в системе начислений.
+type OrderRecheckService interface {
+	Recheck(ctx context.Context, number string) (*domain.OrderRecheck, error)
+}
+
+// DeadLetterService определяет контракт администрирования заказов,
+// снятых с опроса системы начислений.
+type DeadLetterService interface {
+	List(ctx context.Context, query domain.ListQuery) (*domain.DeadLetterPage, error)
+	Requeue(ctx context.Context, number string) (*domain.Order, error)
+}
+
+// OrderNumberValidator проверяет номер заказа перед загрузкой или списанием.
+// Ошибка оборачивает domain.ErrInvalidOrderNumber и описывает причину отказа.
+type OrderNumberValidator interface {
+	Validate(number string) error
+}
+
+// ExportSink принимает записи выгрузки истории пользователя по мере их чтения.
+type ExportSink interface {
+	WriteOrder(order *domain.Order) error
+	WriteWithdrawal(withdrawal *domain.Withdrawal) error
 }
@@ -0,0 +1,82 @@
+package accrual
+
+import "time"
+
+// checkOutcome описывает исход проверки заказа в системе начислений.
+type checkOutcome int
+
+const (
+	// outcomeDone означает, что заказ получил конечный статус.
+	outcomeDone checkOutcome = iota
+	// outcomeDeferred означает, что проверка не состоялась: превышен лимит
+	// запросов или воркер остановлен. Срок проверки не меняется.
+	outcomeDeferred
+	// outcomeNotRegistered означает ответ 204: заказ не зарегистрирован.
+	outcomeNotRegistered
+	// outcomeProcessing означает, что расчёт начисления не завершён.
+	outcomeProcessing
+	// outcomeFailed означает ошибку запроса или сохранения результата.
+	outcomeFailed
+)
+
+// Backoff задаёт экспоненциально растущую задержку между проверками заказа.
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Delay возвращает задержку после attempt-й проверки: Base·2^(attempt-1),
+// но не больше Max.
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := b.Base
+	for i := 1; i < attempt && delay < b.Max; i++ {
+		delay *= 2
+	}
+	return min(delay, b.Max)
+}
+
+// BackoffPolicy задаёт расписание повторных проверок заказа по исходу последней.
+// Jitter — доля задержки, на которую она случайно сокращается, чтобы
+// заказы, загруженные одновременно, не опрашивались одной волной.
+type BackoffPolicy struct {
+	NotRegistered Backoff
+	Processing    Backoff
+	Failed        Backoff
+	Jitter        float64
+}
+
+// DefaultBackoffPolicy задаёт расписание проверок по умолчанию.
+var DefaultBackoffPolicy = BackoffPolicy{
+	NotRegistered: Backoff{Base: 5 * time.Second, Max: 10 * time.Minute},
+	Processing:    Backoff{Base: 2 * time.Second, Max: time.Minute},
+	Failed:        Backoff{Base: 5 * time.Second, Max: 5 * time.Minute},
+	Jitter:        0.2,
+}
+
+// backoff возвращает расписание для исхода проверки. Для исходов,
+// после которых заказ не переназначается, ok равен false.
+func (p BackoffPolicy) backoff(outcome checkOutcome) (Backoff, bool) {
+	switch outcome {
+	case outcomeNotRegistered:
+		return p.NotRegistered, true
+	case outcomeProcessing:
+		return p.Processing, true
+	case outcomeFailed:
+		return p.Failed, true
+	default:
+		return Backoff{}, false
+	}
+}
+
+// delay возвращает задержку после attempt-й проверки с исходом outcome,
+// сокращённую на случайную долю random() от Jitter.
+func (p BackoffPolicy) delay(outcome checkOutcome, attempt int, random func() float64) (time.Duration, bool) {
+	backoff, ok := p.backoff(outcome)
+	if !ok {
+		return 0, false
+	}
+
+	delay := backoff.Delay(attempt)
+	jitter := time.Duration(float64(delay) * p.Jitter * random())
+	return delay - jitter, true
+}
@@ -0,0 +1,63 @@
+package accrual
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff_Delay(t *testing.T) {
+	backoff := Backoff{Base: time.Second, Max: 10 * time.Second}
+
+	tests := []struct {
+		name    string
+		attempt int
+		want    time.Duration
+	}{
+		{name: "first attempt", attempt: 1, want: time.Second},
+		{name: "doubles", attempt: 3, want: 4 * time.Second},
+		{name: "capped", attempt: 5, want: 10 * time.Second},
+		{name: "large attempt does not overflow", attempt: 1000, want: 10 * time.Second},
+		{name: "zero attempt", attempt: 0, want: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, backoff.Delay(tt.attempt))
+		})
+	}
+}
+
+func TestBackoffPolicy_Delay(t *testing.T) {
+	policy := BackoffPolicy{
+		NotRegistered: Backoff{Base: 10 * time.Second, Max: time.Minute},
+		Processing:    Backoff{Base: time.Second, Max: time.Minute},
+		Failed:        Backoff{Base: 5 * time.Second, Max: time.Minute},
+		Jitter:        0.5,
+	}
+
+	tests := []struct {
+		name    string
+		outcome checkOutcome
+		attempt int
+		random  float64
+		want    time.Duration
+		wantOK  bool
+	}{
+		{name: "not registered", outcome: outcomeNotRegistered, attempt: 2, want: 20 * time.Second, wantOK: true},
+		{name: "processing", outcome: outcomeProcessing, attempt: 2, want: 2 * time.Second, wantOK: true},
+		{name: "failed", outcome: outcomeFailed, attempt: 1, want: 5 * time.Second, wantOK: true},
+		{name: "jitter shortens delay", outcome: outcomeFailed, attempt: 1, random: 1, want: 2500 * time.Millisecond, wantOK: true},
+		{name: "done is not rescheduled", outcome: outcomeDone, attempt: 1},
+		{name: "deferred is not rescheduled", outcome: outcomeDeferred, attempt: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := policy.delay(tt.outcome, tt.attempt, func() float64 { return tt.random })
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
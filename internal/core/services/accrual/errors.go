@@ -5,6 +5,15 @@ import (
 	"time"
 )
 
+var (
+	// ErrOrderNotRegistered описывает проверку, на которую система начислений
+	// ответила, что заказ не зарегистрирован.
+	ErrOrderNotRegistered = fmt.Errorf("заказ не зарегистрирован в системе начислений")
+	// ErrAccrualPending описывает проверку, после которой расчёт начисления
+	// ещё не завершён.
+	ErrAccrualPending = fmt.Errorf("расчёт начисления не завершён")
+)
+
 // RetryAfterError возвращается при превышении лимита запросов.
 type RetryAfterError struct {
 	Duration time.Duration
@@ -2,6 +2,9 @@ package accrual
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
 	"sync"
 	"time"
 
@@ -13,6 +16,14 @@ import (
 
 const (
 	defaultPollInterval = 1 * time.Second
+	// DefaultConcurrency задаёт число одновременных запросов к системе начислений по умолчанию.
+	DefaultConcurrency = 4
+	// DefaultBatchSize задаёт число заказов, выбираемых за один цикл опроса по умолчанию.
+	DefaultBatchSize = 100
+	// DefaultLeaseTTL задаёт срок аренды заказа экземпляром сервиса по умолчанию.
+	DefaultLeaseTTL = time.Minute
+
+	releaseTimeout = 5 * time.Second
 
 	msgWorkerStopping      = "остановка воркера начислений"
 	msgGetOrdersError      = "ошибка получения заказов"
@@ -20,35 +31,129 @@ const (
 	msgAccrualRequestError = "ошибка запроса к системе начислений"
 	msgUpdateStatusError   = "ошибка обновления статуса заказа"
 	msgAccrualError        = "ошибка начисления баллов"
+	msgAccrualDuplicate    = "начисление по заказу уже выполнено ранее"
 	msgAccrualSuccess      = "баллы успешно начислены"
+	msgGetTierError        = "ошибка получения уровня лояльности"
+	msgOrderRechecked      = "заказ перепроверен"
+	msgReleaseLeaseError   = "ошибка снятия аренды заказа"
+	msgRescheduleError     = "ошибка назначения следующей проверки заказа"
+	msgDeadLetterError     = "ошибка снятия заказа с опроса"
+	msgOrderDeadLettered   = "заказ снят с опроса системы начислений"
 )
 
 // Worker опрашивает систему начислений и обновляет статусы заказов.
 type Worker struct {
 	orderRepo     ports.OrderRepository
-	balanceRepo   ports.BalanceRepository
+	uow           ports.UnitOfWork
 	accrualClient ports.AccrualClient
 	logger        zerolog.Logger
 	pollInterval  time.Duration
-	retryAfter    time.Duration
-	mu            sync.Mutex
+	concurrency   int
+	batchSize     int
+	leaseTTL      time.Duration
+	backoff       BackoffPolicy
+	maxAttempts   int
+	maxAge        time.Duration
+	random        func() float64
+	pointsTTL     int
+	tierRepo      ports.TierRepository
+	tierPolicy    domain.TierPolicy
+}
+
+// WorkerOption определяет функциональную опцию для настройки воркера.
+type WorkerOption func(*Worker)
+
+// WithPointsTTL задаёт срок действия начисленных баллов в месяцах.
+// Нулевое значение означает, что баллы не сгорают.
+func WithPointsTTL(months int) WorkerOption {
+	return func(w *Worker) {
+		if months > 0 {
+			w.pointsTTL = months
+		}
+	}
+}
+
+// WithTiers включает надбавку к начислениям по уровню лояльности пользователя.
+func WithTiers(tierRepo ports.TierRepository, policy domain.TierPolicy) WorkerOption {
+	return func(w *Worker) {
+		w.tierRepo = tierRepo
+		w.tierPolicy = policy
+	}
+}
+
+// WithConcurrency задаёт число заказов, обрабатываемых одновременно.
+func WithConcurrency(n int) WorkerOption {
+	return func(w *Worker) {
+		if n > 0 {
+			w.concurrency = n
+		}
+	}
+}
+
+// WithBatchSize задаёт число заказов, выбираемых за один цикл опроса.
+// Нулевое значение снимает ограничение.
+func WithBatchSize(n int) WorkerOption {
+	return func(w *Worker) {
+		if n >= 0 {
+			w.batchSize = n
+		}
+	}
+}
+
+// WithLeaseTTL задаёт срок аренды выбранных заказов. По его истечении
+// заказы, не обработанные упавшим экземпляром, достаются другим.
+// Срок должен превышать время обработки одного заказа.
+func WithLeaseTTL(ttl time.Duration) WorkerOption {
+	return func(w *Worker) {
+		if ttl > 0 {
+			w.leaseTTL = ttl
+		}
+	}
+}
+
+// WithBackoff задаёт расписание повторных проверок заказа.
+func WithBackoff(policy BackoffPolicy) WorkerOption {
+	return func(w *Worker) {
+		w.backoff = policy
+	}
+}
+
+// WithGiveUp задаёт, после скольких безрезультатных проверок или через какое
+// время после загрузки заказ снимается с опроса системы начислений.
+// Нулевое значение снимает соответствующее ограничение.
+func WithGiveUp(maxAttempts int, maxAge time.Duration) WorkerOption {
+	return func(w *Worker) {
+		w.maxAttempts = max(maxAttempts, 0)
+		w.maxAge = max(maxAge, 0)
+	}
 }
 
 // NewWorker создаёт новый воркер опроса системы начислений.
 func NewWorker(
 	orderRepo ports.OrderRepository,
-	balanceRepo ports.BalanceRepository,
+	uow ports.UnitOfWork,
 	accrualClient ports.AccrualClient,
 	logger zerolog.Logger,
+	opts ...WorkerOption,
 ) *Worker {
-	return &Worker{
+	w := &Worker{
 		orderRepo:     orderRepo,
-		balanceRepo:   balanceRepo,
+		uow:           uow,
 		accrualClient: accrualClient,
 		logger:        logger,
 		pollInterval:  defaultPollInterval,
-		retryAfter:    0,
+		concurrency:   DefaultConcurrency,
+		batchSize:     DefaultBatchSize,
+		leaseTTL:      DefaultLeaseTTL,
+		backoff:       DefaultBackoffPolicy,
+		random:        rand.Float64,
 	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
 }
 
 // Run запускает воркер опроса системы начислений.
@@ -67,52 +172,144 @@ func (w *Worker) Run(ctx context.Context) {
 	}
 }
 
+// processOrders выбирает пакет необработанных заказов и раздаёт их
+// ограниченному пулу обработчиков. Частоту запросов ограничивает клиент
+// системы начислений, а на время паузы после ответа 429 выдача заказов
+// прекращается. Возврат происходит только после завершения всех начатых
+// запросов, поэтому при отмене ctx воркер останавливается, не оставляя
+// обработку в фоне.
 func (w *Worker) processOrders(ctx context.Context) {
-	w.mu.Lock()
-	if w.retryAfter > 0 {
-		w.retryAfter -= w.pollInterval
-		w.mu.Unlock()
+	if w.accrualClient.PausedFor() > 0 {
 		return
 	}
-	w.mu.Unlock()
 
-	orders, err := w.orderRepo.GetPendingOrders(ctx)
+	orders, err := w.orderRepo.GetPendingOrders(ctx, w.batchSize, w.leaseTTL)
 	if err != nil {
 		w.logger.Error().Err(err).Msg(msgGetOrdersError)
 		return
 	}
 
+	jobs := make(chan *domain.Order)
+	var wg sync.WaitGroup
+	for range min(w.concurrency, len(orders)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for order := range jobs {
+				outcome, cause := w.processOrder(ctx, order)
+				w.schedule(ctx, order, outcome, cause)
+			}
+		}()
+	}
+
+	dispatched := 0
 	for _, order := range orders {
-		if ctx.Err() != nil {
+		// После ответа 429 оставшиеся заказы пакета ждут следующего цикла.
+		if w.accrualClient.PausedFor() > 0 || ctx.Err() != nil {
+			break
+		}
+		jobs <- order
+		dispatched++
+	}
+
+	close(jobs)
+	for _, order := range orders[dispatched:] {
+		w.releaseLease(ctx, order)
+	}
+	wg.Wait()
+}
+
+// releaseLease возвращает заказ в выборку необработанных. Аренда снимается
+// и при остановке воркера, чтобы заказ сразу подхватил другой экземпляр.
+func (w *Worker) releaseLease(ctx context.Context, order *domain.Order) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer cancel()
+
+	if err := w.orderRepo.ReleaseLease(ctx, order.ID); err != nil {
+		w.logger.Error().
+			Err(err).
+			Str("order", order.Number).
+			Msg(msgReleaseLeaseError)
+	}
+}
+
+// schedule назначает следующую проверку заказа по исходу текущей, а заказ,
+// исчерпавший попытки или срок ожидания, снимает с опроса. cause — причина,
+// по которой заказ остался необработанным. Заказ без следующей проверки
+// только освобождается от аренды.
+func (w *Worker) schedule(ctx context.Context, order *domain.Order, outcome checkOutcome, cause error) {
+	attempts := order.Attempts + 1
+	delay, ok := w.backoff.delay(outcome, attempts, w.random)
+	if !ok {
+		w.releaseLease(ctx, order)
+		return
+	}
+
+	var lastError string
+	if cause != nil {
+		lastError = cause.Error()
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer cancel()
+
+	if w.exhausted(order, attempts) {
+		if err := w.orderRepo.DeadLetter(ctx, order.ID, attempts, lastError); err != nil {
+			w.logger.Error().
+				Err(err).
+				Str("order", order.Number).
+				Msg(msgDeadLetterError)
 			return
 		}
+		w.logger.Warn().
+			Str("order", order.Number).
+			Int("attempts", attempts).
+			Str("last_error", lastError).
+			Msg(msgOrderDeadLettered)
+		return
+	}
 
-		w.processOrder(ctx, order)
+	if err := w.orderRepo.Reschedule(ctx, order.ID, attempts, delay, lastError); err != nil {
+		w.logger.Error().
+			Err(err).
+			Str("order", order.Number).
+			Msg(msgRescheduleError)
 	}
 }
 
-func (w *Worker) processOrder(ctx context.Context, order *domain.Order) {
+// exhausted сообщает, исчерпал ли заказ попытки или срок ожидания.
+func (w *Worker) exhausted(order *domain.Order, attempts int) bool {
+	if w.maxAttempts > 0 && attempts >= w.maxAttempts {
+		return true
+	}
+	return w.maxAge > 0 && time.Since(order.UploadedAt) >= w.maxAge
+}
+
+// processOrder запрашивает начисление по заказу, сохраняет результат
+// и возвращает исход проверки вместе с причиной, по которой заказ
+// остался необработанным.
+func (w *Worker) processOrder(ctx context.Context, order *domain.Order) (checkOutcome, error) {
 	resp, err := w.accrualClient.GetOrderAccrual(ctx, order.Number)
 	if err != nil {
 		if retryErr, ok := err.(*RetryAfterError); ok {
-			w.mu.Lock()
-			w.retryAfter = retryErr.Duration
-			w.mu.Unlock()
 			w.logger.Warn().
 				Dur("retry_after", retryErr.Duration).
 				Msg(msgRateLimitExceeded)
-			return
+			return outcomeDeferred, nil
+		}
+		if ctx.Err() != nil {
+			return outcomeDeferred, nil
 		}
 
 		w.logger.Error().
 			Err(err).
 			Str("order", order.Number).
 			Msg(msgAccrualRequestError)
-		return
+		return outcomeFailed, err
 	}
 
 	if resp == nil {
-		return
+		return outcomeNotRegistered, ErrOrderNotRegistered
 	}
 
 	var accrual *decimal.Decimal
@@ -120,29 +317,184 @@ func (w *Worker) processOrder(ctx context.Context, order *domain.Order) {
 		accrual = &resp.Accrual
 	}
 
-	err = w.orderRepo.UpdateStatus(ctx, order.Number, resp.Status, accrual)
-	if err != nil {
-		w.logger.Error().
-			Err(err).
-			Str("order", order.Number).
-			Msg(msgUpdateStatusError)
-		return
-	}
+	credit := resp.Status == domain.OrderStatusProcessed && resp.Accrual.IsPositive()
 
-	if resp.Status == domain.OrderStatusProcessed && resp.Accrual.IsPositive() {
-		err = w.balanceRepo.AddAccrual(ctx, order.UserID, resp.Accrual)
+	var bonus decimal.Decimal
+	if credit {
+		bonus, err = w.tierBonus(ctx, order.UserID, resp.Accrual)
 		if err != nil {
 			w.logger.Error().
 				Err(err).
 				Str("order", order.Number).
-				Str("accrual", resp.Accrual.String()).
-				Msg(msgAccrualError)
-			return
+				Msg(msgGetTierError)
+			return outcomeFailed, err
+		}
+	}
+
+	err = w.uow.Do(ctx, func(ctx context.Context, tx ports.TxRepositories) error {
+		if err := tx.Orders().UpdateStatus(ctx, order.ID, resp.Status, accrual); err != nil {
+			return fmt.Errorf("%s: %w", msgUpdateStatusError, err)
+		}
+
+		if credit {
+			if err := tx.Balances().AddAccrual(ctx, w.newAccrual(order, resp.Accrual, bonus)); err != nil {
+				return fmt.Errorf("%s: %w", msgAccrualError, err)
+			}
 		}
 
+		return nil
+	})
+
+	if errors.Is(err, domain.ErrOrderAlreadyFinal) || errors.Is(err, domain.ErrAccrualAlreadyApplied) {
+		w.logger.Warn().
+			Err(err).
+			Str("order", order.Number).
+			Msg(msgAccrualDuplicate)
+		return outcomeDone, nil
+	}
+
+	if err != nil {
+		w.logger.Error().
+			Err(err).
+			Str("order", order.Number).
+			Str("accrual", resp.Accrual.String()).
+			Msg(msgAccrualError)
+		return outcomeFailed, err
+	}
+
+	if credit {
 		w.logger.Info().
 			Str("order", order.Number).
 			Str("accrual", resp.Accrual.String()).
+			Str("bonus", bonus.String()).
 			Msg(msgAccrualSuccess)
 	}
+
+	if resp.Status.IsFinal() {
+		return outcomeDone, nil
+	}
+	return outcomeProcessing, ErrAccrualPending
+}
+
+// Recheck перепроверяет заказ в системе начислений по запросу администратора.
+//
+// На время перепроверки заказ арендуется, чтобы его не обработал параллельно
+// воркер или другая перепроверка; арендованный заказ возвращает
+// domain.ErrOrderLocked. Заказ, в том числе в конечном статусе, переводится
+// в PROCESSING и сразу получает конечный статус из ответа системы начислений.
+// Оба перехода фиксируются в одной транзакции, поэтому при недоступности
+// системы заказ не меняется. Сумма начислений за заказ доводится до
+// полученной, надбавка за уровень пересчитывается по текущему уровню
+// пользователя, а при отзыве начисления отзывается. Пока расчёт не завершён,
+// заказ и начисление не меняются, а возвращается domain.ErrAccrualNotFinal.
+//
+// Запрос к системе начислений проходит через общий с воркером лимит частоты,
+// но не ждёт его: при исчерпанном лимите сразу возвращается
+// domain.ErrAccrualRateLimited вместе с *RetryAfterError.
+func (w *Worker) Recheck(ctx context.Context, number string) (*domain.OrderRecheck, error) {
+	order, err := w.orderRepo.GetByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status == domain.OrderStatusCanceled {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	if err := w.orderRepo.ClaimLease(ctx, order.ID, w.leaseTTL); err != nil {
+		return nil, err
+	}
+	defer w.releaseLease(ctx, order)
+
+	resp, err := w.accrualClient.TryGetOrderAccrual(ctx, number)
+	if err != nil {
+		if _, ok := err.(*RetryAfterError); ok {
+			return nil, fmt.Errorf("%w: %w", domain.ErrAccrualRateLimited, err)
+		}
+		return nil, fmt.Errorf("%w: %w", domain.ErrAccrualUnavailable, err)
+	}
+	if resp == nil || !resp.Status.IsFinal() {
+		return nil, domain.ErrAccrualNotFinal
+	}
+
+	status := resp.Status
+	var accrual *decimal.Decimal
+	if resp.Accrual.IsPositive() {
+		accrual = &resp.Accrual
+	}
+
+	var amount, bonus decimal.Decimal
+	if status == domain.OrderStatusProcessed && accrual != nil {
+		amount = *accrual
+		bonus, err = w.tierBonus(ctx, order.UserID, amount)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", msgGetTierError, err)
+		}
+	}
+
+	result := &domain.OrderRecheck{}
+	err = w.uow.Do(ctx, func(ctx context.Context, tx ports.TxRepositories) error {
+		previous, err := tx.Orders().Reopen(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Orders().UpdateStatus(ctx, order.ID, status, accrual); err != nil {
+			return fmt.Errorf("%s: %w", msgUpdateStatusError, err)
+		}
+
+		description := fmt.Sprintf("перепроверка заказа %s: начисление %s", number, amount.StringFixed(2))
+		correction, err := tx.Balances().SettleAccrual(ctx, w.newAccrual(previous, amount, bonus), description)
+		if err != nil {
+			return fmt.Errorf("%s: %w", msgAccrualError, err)
+		}
+
+		current := *previous
+		current.Status = status
+		current.Accrual = accrual
+		result.Previous, result.Order, result.Correction = previous, &current, correction
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	event := w.logger.Info().
+		Str("order", number).
+		Str("previous_status", string(result.Previous.Status)).
+		Str("status", string(status))
+	if result.Correction != nil {
+		event = event.Str("correction", result.Correction.Amount.String())
+	}
+	event.Msg(msgOrderRechecked)
+
+	return result, nil
+}
+
+// tierBonus возвращает надбавку к начислению по текущему уровню пользователя.
+func (w *Worker) tierBonus(ctx context.Context, userID int64, amount decimal.Decimal) (decimal.Decimal, error) {
+	if w.tierRepo == nil {
+		return decimal.Zero, nil
+	}
+
+	tier, err := w.tierRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return w.tierPolicy.Bonus(tier.Tier, amount), nil
+}
+
+func (w *Worker) newAccrual(order *domain.Order, amount, bonus decimal.Decimal) *domain.Accrual {
+	accrual := &domain.Accrual{
+		UserID:      order.UserID,
+		OrderNumber: order.Number,
+		Amount:      amount,
+		Bonus:       bonus,
+	}
+
+	if w.pointsTTL > 0 {
+		expiresAt := time.Now().AddDate(0, w.pointsTTL, 0)
+		accrual.ExpiresAt = &expiresAt
+	}
+
+	return accrual
 }
@@ -2,7 +2,10 @@ package accrual_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -13,19 +16,27 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
+// newAccrualClient создаёт мок клиента системы начислений без паузы после 429.
+func newAccrualClient(ctrl *gomock.Controller) *mocks.MockAccrualClient {
+	client := mocks.NewMockAccrualClient(ctrl)
+	client.EXPECT().PausedFor().Return(time.Duration(0)).AnyTimes()
+	return client
+}
+
 func TestWorker_ProcessOrder_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	orderRepo := mocks.NewMockOrderRepository(ctrl)
-	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
-	accrualClient := mocks.NewMockAccrualClient(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	accrualClient := newAccrualClient(ctrl)
 	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
 
-	worker := accrual.NewWorker(orderRepo, balanceRepo, accrualClient, logger)
+	worker := accrual.NewWorker(orderRepo, uow, accrualClient, logger)
 
 	pendingOrders := []*domain.Order{
 		{
@@ -36,8 +47,10 @@ func TestWorker_ProcessOrder_Success(t *testing.T) {
 		},
 	}
 
+	orderRepo.EXPECT().ReleaseLease(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	orderRepo.EXPECT().Reschedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	orderRepo.EXPECT().
-		GetPendingOrders(gomock.Any()).
+		GetPendingOrders(gomock.Any(), accrual.DefaultBatchSize, accrual.DefaultLeaseTTL).
 		Return(pendingOrders, nil).
 		AnyTimes()
 
@@ -50,16 +63,171 @@ func TestWorker_ProcessOrder_Success(t *testing.T) {
 		}, nil).
 		AnyTimes()
 
+	txOrders := mocks.NewMockOrderRepository(ctrl)
+	txBalances := mocks.NewMockBalanceRepository(ctrl)
+	expectTx(ctrl, uow, txOrders, txBalances)
+
+	txOrders.EXPECT().
+		UpdateStatus(gomock.Any(), int64(1), domain.OrderStatusProcessed, gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	txBalances.EXPECT().
+		AddAccrual(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, accrual *domain.Accrual) error {
+			assert.Equal(t, int64(1), accrual.UserID)
+			assert.Equal(t, "12345678903", accrual.OrderNumber)
+			assert.True(t, decimal.NewFromFloat(500.0).Equal(accrual.Amount))
+			assert.Nil(t, accrual.ExpiresAt)
+			return nil
+		}).
+		AnyTimes()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+
+	go worker.Run(ctx)
+	<-ctx.Done()
+}
+
+func TestWorker_ProcessOrder_PointsTTL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	accrualClient := newAccrualClient(ctrl)
+	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
+
+	worker := accrual.NewWorker(orderRepo, uow, accrualClient, logger, accrual.WithPointsTTL(6))
+
+	orderRepo.EXPECT().ReleaseLease(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	orderRepo.EXPECT().Reschedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	orderRepo.EXPECT().
-		UpdateStatus(gomock.Any(), "12345678903", domain.OrderStatusProcessed, gomock.Any()).
+		GetPendingOrders(gomock.Any(), accrual.DefaultBatchSize, accrual.DefaultLeaseTTL).
+		Return([]*domain.Order{{ID: 1, UserID: 1, Number: "12345678903", Status: domain.OrderStatusNew}}, nil).
+		AnyTimes()
+
+	accrualClient.EXPECT().
+		GetOrderAccrual(gomock.Any(), "12345678903").
+		Return(&ports.AccrualResponse{
+			Order:   "12345678903",
+			Status:  domain.OrderStatusProcessed,
+			Accrual: decimal.NewFromFloat(100.0),
+		}, nil).
+		AnyTimes()
+
+	txOrders := mocks.NewMockOrderRepository(ctrl)
+	txBalances := mocks.NewMockBalanceRepository(ctrl)
+	expectTx(ctrl, uow, txOrders, txBalances)
+
+	txOrders.EXPECT().
+		UpdateStatus(gomock.Any(), int64(1), domain.OrderStatusProcessed, gomock.Any()).
 		Return(nil).
 		AnyTimes()
 
-	balanceRepo.EXPECT().
-		AddAccrual(gomock.Any(), int64(1), gomock.Any()).
+	wantExpiresAt := time.Now().AddDate(0, 6, 0)
+	txBalances.EXPECT().
+		AddAccrual(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, accrual *domain.Accrual) error {
+			if assert.NotNil(t, accrual.ExpiresAt) {
+				assert.WithinDuration(t, wantExpiresAt, *accrual.ExpiresAt, time.Minute)
+			}
+			return nil
+		}).
+		MinTimes(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+
+	go worker.Run(ctx)
+	<-ctx.Done()
+}
+
+func TestWorker_ProcessOrder_AccrualAlreadyApplied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	accrualClient := newAccrualClient(ctrl)
+	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
+
+	worker := accrual.NewWorker(orderRepo, uow, accrualClient, logger)
+
+	orderRepo.EXPECT().ReleaseLease(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	orderRepo.EXPECT().Reschedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	orderRepo.EXPECT().
+		GetPendingOrders(gomock.Any(), accrual.DefaultBatchSize, accrual.DefaultLeaseTTL).
+		Return([]*domain.Order{{ID: 1, UserID: 1, Number: "12345678903", Status: domain.OrderStatusProcessing}}, nil).
+		AnyTimes()
+
+	accrualClient.EXPECT().
+		GetOrderAccrual(gomock.Any(), "12345678903").
+		Return(&ports.AccrualResponse{
+			Order:   "12345678903",
+			Status:  domain.OrderStatusProcessed,
+			Accrual: decimal.NewFromFloat(500.0),
+		}, nil).
+		AnyTimes()
+
+	txOrders := mocks.NewMockOrderRepository(ctrl)
+	txBalances := mocks.NewMockBalanceRepository(ctrl)
+	expectTx(ctrl, uow, txOrders, txBalances)
+
+	txOrders.EXPECT().
+		UpdateStatus(gomock.Any(), int64(1), domain.OrderStatusProcessed, gomock.Any()).
 		Return(nil).
 		AnyTimes()
 
+	txBalances.EXPECT().
+		AddAccrual(gomock.Any(), gomock.Any()).
+		Return(domain.ErrAccrualAlreadyApplied).
+		AnyTimes()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+
+	go worker.Run(ctx)
+	<-ctx.Done()
+}
+
+func TestWorker_ProcessOrder_AlreadyFinal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	accrualClient := newAccrualClient(ctrl)
+	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
+
+	worker := accrual.NewWorker(orderRepo, uow, accrualClient, logger)
+
+	orderRepo.EXPECT().ReleaseLease(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	orderRepo.EXPECT().Reschedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	orderRepo.EXPECT().
+		GetPendingOrders(gomock.Any(), accrual.DefaultBatchSize, accrual.DefaultLeaseTTL).
+		Return([]*domain.Order{{ID: 1, UserID: 1, Number: "12345678903", Status: domain.OrderStatusProcessing}}, nil).
+		AnyTimes()
+
+	accrualClient.EXPECT().
+		GetOrderAccrual(gomock.Any(), "12345678903").
+		Return(&ports.AccrualResponse{
+			Order:   "12345678903",
+			Status:  domain.OrderStatusProcessed,
+			Accrual: decimal.NewFromFloat(500.0),
+		}, nil).
+		AnyTimes()
+
+	txOrders := mocks.NewMockOrderRepository(ctrl)
+	expectTx(ctrl, uow, txOrders, mocks.NewMockBalanceRepository(ctrl))
+
+	// Начисление не должно выполняться, если статус заказа уже конечный.
+	txOrders.EXPECT().
+		UpdateStatus(gomock.Any(), int64(1), domain.OrderStatusProcessed, gomock.Any()).
+		Return(domain.ErrOrderAlreadyFinal).
+		AnyTimes()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
 	defer cancel()
 
@@ -72,11 +240,11 @@ func TestWorker_ProcessOrder_NoContent(t *testing.T) {
 	defer ctrl.Finish()
 
 	orderRepo := mocks.NewMockOrderRepository(ctrl)
-	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
-	accrualClient := mocks.NewMockAccrualClient(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	accrualClient := newAccrualClient(ctrl)
 	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
 
-	worker := accrual.NewWorker(orderRepo, balanceRepo, accrualClient, logger)
+	worker := accrual.NewWorker(orderRepo, uow, accrualClient, logger)
 
 	pendingOrders := []*domain.Order{
 		{
@@ -87,8 +255,10 @@ func TestWorker_ProcessOrder_NoContent(t *testing.T) {
 		},
 	}
 
+	orderRepo.EXPECT().ReleaseLease(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	orderRepo.EXPECT().Reschedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	orderRepo.EXPECT().
-		GetPendingOrders(gomock.Any()).
+		GetPendingOrders(gomock.Any(), accrual.DefaultBatchSize, accrual.DefaultLeaseTTL).
 		Return(pendingOrders, nil).
 		AnyTimes()
 
@@ -109,11 +279,11 @@ func TestWorker_ProcessOrder_Processing(t *testing.T) {
 	defer ctrl.Finish()
 
 	orderRepo := mocks.NewMockOrderRepository(ctrl)
-	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
-	accrualClient := mocks.NewMockAccrualClient(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	accrualClient := newAccrualClient(ctrl)
 	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
 
-	worker := accrual.NewWorker(orderRepo, balanceRepo, accrualClient, logger)
+	worker := accrual.NewWorker(orderRepo, uow, accrualClient, logger)
 
 	pendingOrders := []*domain.Order{
 		{
@@ -124,8 +294,10 @@ func TestWorker_ProcessOrder_Processing(t *testing.T) {
 		},
 	}
 
+	orderRepo.EXPECT().ReleaseLease(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	orderRepo.EXPECT().Reschedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	orderRepo.EXPECT().
-		GetPendingOrders(gomock.Any()).
+		GetPendingOrders(gomock.Any(), accrual.DefaultBatchSize, accrual.DefaultLeaseTTL).
 		Return(pendingOrders, nil).
 		AnyTimes()
 
@@ -137,8 +309,11 @@ func TestWorker_ProcessOrder_Processing(t *testing.T) {
 		}, nil).
 		AnyTimes()
 
-	orderRepo.EXPECT().
-		UpdateStatus(gomock.Any(), "12345678903", domain.OrderStatusProcessing, gomock.Any()).
+	txOrders := mocks.NewMockOrderRepository(ctrl)
+	expectTx(ctrl, uow, txOrders, mocks.NewMockBalanceRepository(ctrl))
+
+	txOrders.EXPECT().
+		UpdateStatus(gomock.Any(), int64(1), domain.OrderStatusProcessing, gomock.Any()).
 		Return(nil).
 		AnyTimes()
 
@@ -154,11 +329,11 @@ func TestWorker_ProcessOrder_RateLimited(t *testing.T) {
 	defer ctrl.Finish()
 
 	orderRepo := mocks.NewMockOrderRepository(ctrl)
-	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
 	accrualClient := mocks.NewMockAccrualClient(ctrl)
 	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
 
-	worker := accrual.NewWorker(orderRepo, balanceRepo, accrualClient, logger)
+	worker := accrual.NewWorker(orderRepo, uow, accrualClient, logger)
 
 	pendingOrders := []*domain.Order{
 		{
@@ -169,15 +344,31 @@ func TestWorker_ProcessOrder_RateLimited(t *testing.T) {
 		},
 	}
 
+	orderRepo.EXPECT().ReleaseLease(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	orderRepo.EXPECT().Reschedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	orderRepo.EXPECT().
-		GetPendingOrders(gomock.Any()).
+		GetPendingOrders(gomock.Any(), accrual.DefaultBatchSize, accrual.DefaultLeaseTTL).
 		Return(pendingOrders, nil).
 		AnyTimes()
 
+	// Клиент приостанавливает запросы после ответа 429, и до конца паузы
+	// воркер не выбирает заказы.
+	var paused atomic.Bool
+	accrualClient.EXPECT().PausedFor().
+		DoAndReturn(func() time.Duration {
+			if paused.Load() {
+				return 60 * time.Second
+			}
+			return 0
+		}).
+		AnyTimes()
 	accrualClient.EXPECT().
 		GetOrderAccrual(gomock.Any(), "12345678903").
-		Return(nil, &accrual.RetryAfterError{Duration: 60 * time.Second}).
-		AnyTimes()
+		DoAndReturn(func(context.Context, string) (*ports.AccrualResponse, error) {
+			paused.Store(true)
+			return nil, &accrual.RetryAfterError{Duration: 60 * time.Second}
+		}).
+		Times(1)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
 	defer cancel()
@@ -191,14 +382,16 @@ func TestWorker_NoPendingOrders(t *testing.T) {
 	defer ctrl.Finish()
 
 	orderRepo := mocks.NewMockOrderRepository(ctrl)
-	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
-	accrualClient := mocks.NewMockAccrualClient(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	accrualClient := newAccrualClient(ctrl)
 	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
 
-	worker := accrual.NewWorker(orderRepo, balanceRepo, accrualClient, logger)
+	worker := accrual.NewWorker(orderRepo, uow, accrualClient, logger)
 
+	orderRepo.EXPECT().ReleaseLease(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	orderRepo.EXPECT().Reschedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	orderRepo.EXPECT().
-		GetPendingOrders(gomock.Any()).
+		GetPendingOrders(gomock.Any(), accrual.DefaultBatchSize, accrual.DefaultLeaseTTL).
 		Return([]*domain.Order{}, nil).
 		AnyTimes()
 
@@ -209,7 +402,587 @@ func TestWorker_NoPendingOrders(t *testing.T) {
 	<-ctx.Done()
 }
 
+func TestWorker_ProcessOrders_Concurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	accrualClient := newAccrualClient(ctrl)
+
+	worker := accrual.NewWorker(orderRepo, mocks.NewMockUnitOfWork(ctrl), accrualClient, zerolog.Nop(),
+		accrual.WithConcurrency(3), accrual.WithBatchSize(6))
+
+	orders := make([]*domain.Order, 6)
+	for i := range orders {
+		orders[i] = &domain.Order{ID: int64(i + 1), UserID: 1, Number: fmt.Sprintf("order-%d", i), Status: domain.OrderStatusNew}
+	}
+
+	orderRepo.EXPECT().ReleaseLease(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	orderRepo.EXPECT().Reschedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	orderRepo.EXPECT().
+		GetPendingOrders(gomock.Any(), 6, accrual.DefaultLeaseTTL).
+		Return(orders, nil).
+		MinTimes(1)
+
+	var inFlight, maxInFlight, calls atomic.Int32
+	accrualClient.EXPECT().
+		GetOrderAccrual(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, string) (*ports.AccrualResponse, error) {
+			calls.Add(1)
+			current := inFlight.Add(1)
+			for {
+				peak := maxInFlight.Load()
+				if current <= peak || maxInFlight.CompareAndSwap(peak, current) {
+					break
+				}
+			}
+			time.Sleep(50 * time.Millisecond)
+			inFlight.Add(-1)
+			return nil, nil
+		}).
+		AnyTimes()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+
+	worker.Run(ctx)
+
+	assert.Equal(t, int32(6), calls.Load())
+	assert.Equal(t, int32(3), maxInFlight.Load())
+}
+
+func TestWorker_ProcessOrders_RateLimitedStopsBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	accrualClient := mocks.NewMockAccrualClient(ctrl)
+
+	worker := accrual.NewWorker(orderRepo, mocks.NewMockUnitOfWork(ctrl), accrualClient, zerolog.Nop(),
+		accrual.WithConcurrency(1))
+
+	orderRepo.EXPECT().ReleaseLease(gomock.Any(), int64(1)).Return(nil).Times(1)
+	// Недоставшиеся обработчикам заказы сразу возвращаются в выборку.
+	orderRepo.EXPECT().ReleaseLease(gomock.Any(), int64(2)).Return(nil).Times(1)
+	orderRepo.EXPECT().ReleaseLease(gomock.Any(), int64(3)).Return(nil).Times(1)
+	orderRepo.EXPECT().
+		GetPendingOrders(gomock.Any(), accrual.DefaultBatchSize, accrual.DefaultLeaseTTL).
+		Return([]*domain.Order{
+			{ID: 1, UserID: 1, Number: "12345678903", Status: domain.OrderStatusNew},
+			{ID: 2, UserID: 1, Number: "79927398713", Status: domain.OrderStatusNew},
+			{ID: 3, UserID: 1, Number: "2377225624", Status: domain.OrderStatusNew},
+		}, nil).
+		Times(1)
+
+	var paused atomic.Bool
+	accrualClient.EXPECT().PausedFor().
+		DoAndReturn(func() time.Duration {
+			if paused.Load() {
+				return time.Minute
+			}
+			return 0
+		}).
+		AnyTimes()
+	accrualClient.EXPECT().
+		GetOrderAccrual(gomock.Any(), "12345678903").
+		DoAndReturn(func(context.Context, string) (*ports.AccrualResponse, error) {
+			paused.Store(true)
+			return nil, &accrual.RetryAfterError{Duration: time.Minute}
+		}).
+		Times(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+	defer cancel()
+
+	worker.Run(ctx)
+}
+
+func TestWorker_Run_WaitsForInFlightRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	accrualClient := newAccrualClient(ctrl)
+
+	worker := accrual.NewWorker(orderRepo, mocks.NewMockUnitOfWork(ctrl), accrualClient, zerolog.Nop())
+
+	orderRepo.EXPECT().ReleaseLease(gomock.Any(), int64(1)).
+		DoAndReturn(func(ctx context.Context, _ int64) error {
+			// Аренда снимается и после отмены контекста воркера.
+			assert.NoError(t, ctx.Err())
+			return nil
+		}).
+		Times(1)
+	orderRepo.EXPECT().
+		GetPendingOrders(gomock.Any(), accrual.DefaultBatchSize, accrual.DefaultLeaseTTL).
+		Return([]*domain.Order{{ID: 1, UserID: 1, Number: "12345678903", Status: domain.OrderStatusNew}}, nil).
+		Times(1)
+
+	started := make(chan struct{})
+	var finished atomic.Bool
+	accrualClient.EXPECT().
+		GetOrderAccrual(gomock.Any(), "12345678903").
+		DoAndReturn(func(ctx context.Context, _ string) (*ports.AccrualResponse, error) {
+			close(started)
+			<-ctx.Done()
+			time.Sleep(20 * time.Millisecond)
+			finished.Store(true)
+			return nil, ctx.Err()
+		}).
+		Times(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		worker.Run(ctx)
+		close(done)
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case <-done:
+		assert.True(t, finished.Load())
+	case <-time.After(time.Second):
+		t.Fatal("воркер не остановился после отмены контекста")
+	}
+}
+
+func TestWorker_ProcessOrders_Reschedule(t *testing.T) {
+	policy := accrual.BackoffPolicy{
+		NotRegistered: accrual.Backoff{Base: 10 * time.Second, Max: time.Hour},
+		Processing:    accrual.Backoff{Base: time.Second, Max: time.Minute},
+		Failed:        accrual.Backoff{Base: 5 * time.Second, Max: time.Hour},
+	}
+
+	tests := []struct {
+		name      string
+		resp      *ports.AccrualResponse
+		err       error
+		wantDelay time.Duration
+		wantError string
+		wantFinal bool
+	}{
+		{
+			name:      "not registered",
+			wantDelay: 80 * time.Second,
+			wantError: accrual.ErrOrderNotRegistered.Error(),
+		},
+		{
+			name:      "still processing",
+			resp:      &ports.AccrualResponse{Order: "12345678903", Status: domain.OrderStatusProcessing},
+			wantDelay: 8 * time.Second,
+			wantError: accrual.ErrAccrualPending.Error(),
+		},
+		{
+			name:      "transport error",
+			err:       errors.New("connection refused"),
+			wantDelay: 40 * time.Second,
+			wantError: "connection refused",
+		},
+		{
+			name:      "final status",
+			resp:      &ports.AccrualResponse{Order: "12345678903", Status: domain.OrderStatusInvalid},
+			wantFinal: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			orderRepo := mocks.NewMockOrderRepository(ctrl)
+			uow := mocks.NewMockUnitOfWork(ctrl)
+			accrualClient := newAccrualClient(ctrl)
+
+			worker := accrual.NewWorker(orderRepo, uow, accrualClient, zerolog.Nop(), accrual.WithBackoff(policy))
+
+			orderRepo.EXPECT().
+				GetPendingOrders(gomock.Any(), accrual.DefaultBatchSize, accrual.DefaultLeaseTTL).
+				Return([]*domain.Order{{ID: 1, UserID: 1, Number: "12345678903", Status: domain.OrderStatusNew, Attempts: 3}}, nil).
+				Times(1)
+
+			accrualClient.EXPECT().
+				GetOrderAccrual(gomock.Any(), "12345678903").
+				Return(tt.resp, tt.err).
+				Times(1)
+
+			if tt.resp != nil {
+				txOrders := mocks.NewMockOrderRepository(ctrl)
+				expectTx(ctrl, uow, txOrders, mocks.NewMockBalanceRepository(ctrl))
+				txOrders.EXPECT().
+					UpdateStatus(gomock.Any(), int64(1), tt.resp.Status, gomock.Any()).
+					Return(nil)
+			}
+
+			if tt.wantFinal {
+				orderRepo.EXPECT().ReleaseLease(gomock.Any(), int64(1)).Return(nil).Times(1)
+			} else {
+				orderRepo.EXPECT().Reschedule(gomock.Any(), int64(1), 4, tt.wantDelay, tt.wantError).Return(nil).Times(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+			defer cancel()
+
+			worker.Run(ctx)
+		})
+	}
+}
+
+func TestWorker_ProcessOrders_GiveUp(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []accrual.WorkerOption
+		attempts       int
+		uploadedAt     time.Time
+		resp           *ports.AccrualResponse
+		wantDeadLetter bool
+	}{
+		{
+			name:           "attempts exhausted",
+			opts:           []accrual.WorkerOption{accrual.WithGiveUp(5, 0)},
+			attempts:       4,
+			uploadedAt:     time.Now(),
+			wantDeadLetter: true,
+		},
+		{
+			name:       "attempts left",
+			opts:       []accrual.WorkerOption{accrual.WithGiveUp(5, 0)},
+			attempts:   3,
+			uploadedAt: time.Now(),
+		},
+		{
+			name:           "order too old",
+			opts:           []accrual.WorkerOption{accrual.WithGiveUp(0, 24*time.Hour)},
+			uploadedAt:     time.Now().Add(-25 * time.Hour),
+			wantDeadLetter: true,
+		},
+		{
+			name:       "no limits",
+			attempts:   1000,
+			uploadedAt: time.Now().Add(-365 * 24 * time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			orderRepo := mocks.NewMockOrderRepository(ctrl)
+			accrualClient := newAccrualClient(ctrl)
+
+			worker := accrual.NewWorker(orderRepo, mocks.NewMockUnitOfWork(ctrl), accrualClient, zerolog.Nop(), tt.opts...)
+
+			orderRepo.EXPECT().
+				GetPendingOrders(gomock.Any(), accrual.DefaultBatchSize, accrual.DefaultLeaseTTL).
+				Return([]*domain.Order{{
+					ID: 1, UserID: 1, Number: "12345678903", Status: domain.OrderStatusNew,
+					UploadedAt: tt.uploadedAt, Attempts: tt.attempts,
+				}}, nil).
+				Times(1)
+
+			accrualClient.EXPECT().
+				GetOrderAccrual(gomock.Any(), "12345678903").
+				Return(nil, nil).
+				Times(1)
+
+			if tt.wantDeadLetter {
+				orderRepo.EXPECT().
+					DeadLetter(gomock.Any(), int64(1), tt.attempts+1, accrual.ErrOrderNotRegistered.Error()).
+					Return(nil).
+					Times(1)
+			} else {
+				orderRepo.EXPECT().
+					Reschedule(gomock.Any(), int64(1), tt.attempts+1, gomock.Any(), accrual.ErrOrderNotRegistered.Error()).
+					Return(nil).
+					Times(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+			defer cancel()
+
+			worker.Run(ctx)
+		})
+	}
+}
+
+func expectTx(
+	ctrl *gomock.Controller,
+	uow *mocks.MockUnitOfWork,
+	orders *mocks.MockOrderRepository,
+	balances *mocks.MockBalanceRepository,
+) {
+	tx := mocks.NewMockTxRepositories(ctrl)
+	tx.EXPECT().Orders().Return(orders).AnyTimes()
+	tx.EXPECT().Balances().Return(balances).AnyTimes()
+
+	uow.EXPECT().
+		Do(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context, ports.TxRepositories) error) error {
+			return fn(ctx, tx)
+		}).
+		AnyTimes()
+}
+
 func TestRetryAfterError_Error(t *testing.T) {
 	err := &accrual.RetryAfterError{Duration: 60 * time.Second}
 	assert.Equal(t, "превышен лимит запросов, повторить через 1m0s", err.Error())
 }
+
+func TestWorker_ProcessOrder_TierBonus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	accrualClient := newAccrualClient(ctrl)
+	tierRepo := mocks.NewMockTierRepository(ctrl)
+	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
+
+	policy := domain.TierPolicy{
+		{Tier: domain.TierGold, Threshold: decimal.NewFromInt(5000), Multiplier: decimal.RequireFromString("1.25")},
+	}
+	worker := accrual.NewWorker(orderRepo, uow, accrualClient, logger, accrual.WithTiers(tierRepo, policy))
+
+	orderRepo.EXPECT().ReleaseLease(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	orderRepo.EXPECT().Reschedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	orderRepo.EXPECT().
+		GetPendingOrders(gomock.Any(), accrual.DefaultBatchSize, accrual.DefaultLeaseTTL).
+		Return([]*domain.Order{{ID: 1, UserID: 1, Number: "12345678903", Status: domain.OrderStatusNew}}, nil).
+		AnyTimes()
+
+	accrualClient.EXPECT().
+		GetOrderAccrual(gomock.Any(), "12345678903").
+		Return(&ports.AccrualResponse{
+			Order:   "12345678903",
+			Status:  domain.OrderStatusProcessed,
+			Accrual: decimal.NewFromFloat(100.0),
+		}, nil).
+		AnyTimes()
+
+	tierRepo.EXPECT().
+		GetByUserID(gomock.Any(), int64(1)).
+		Return(&domain.UserTier{UserID: 1, Tier: domain.TierGold}, nil).
+		AnyTimes()
+
+	txOrders := mocks.NewMockOrderRepository(ctrl)
+	txBalances := mocks.NewMockBalanceRepository(ctrl)
+	expectTx(ctrl, uow, txOrders, txBalances)
+
+	txOrders.EXPECT().
+		UpdateStatus(gomock.Any(), int64(1), domain.OrderStatusProcessed, gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	txBalances.EXPECT().
+		AddAccrual(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, accrual *domain.Accrual) error {
+			assert.True(t, decimal.NewFromFloat(100.0).Equal(accrual.Amount))
+			assert.True(t, decimal.NewFromFloat(25.0).Equal(accrual.Bonus))
+			return nil
+		}).
+		MinTimes(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+
+	go worker.Run(ctx)
+	<-ctx.Done()
+}
+
+func TestWorker_Recheck_InvalidBecomesProcessed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	accrualClient := mocks.NewMockAccrualClient(ctrl)
+	tierRepo := mocks.NewMockTierRepository(ctrl)
+	policy := domain.TierPolicy{
+		{Tier: domain.TierBronze, Threshold: decimal.Zero, Multiplier: decimal.NewFromInt(1)},
+		{Tier: domain.TierGold, Threshold: decimal.NewFromInt(1000), Multiplier: decimal.NewFromFloat(1.5)},
+	}
+
+	worker := accrual.NewWorker(orderRepo, uow, accrualClient, zerolog.Nop(), accrual.WithTiers(tierRepo, policy))
+
+	invalid := &domain.Order{ID: 1, UserID: 7, Number: "12345678903", Status: domain.OrderStatusInvalid}
+	orderRepo.EXPECT().GetByNumber(gomock.Any(), "12345678903").Return(invalid, nil)
+	orderRepo.EXPECT().ClaimLease(gomock.Any(), int64(1), accrual.DefaultLeaseTTL).Return(nil)
+	orderRepo.EXPECT().ReleaseLease(gomock.Any(), int64(1)).Return(nil)
+	accrualClient.EXPECT().
+		TryGetOrderAccrual(gomock.Any(), "12345678903").
+		Return(&ports.AccrualResponse{Order: "12345678903", Status: domain.OrderStatusProcessed, Accrual: decimal.NewFromInt(200)}, nil)
+	tierRepo.EXPECT().GetByUserID(gomock.Any(), int64(7)).Return(&domain.UserTier{UserID: 7, Tier: domain.TierGold}, nil)
+
+	txOrders := mocks.NewMockOrderRepository(ctrl)
+	txBalances := mocks.NewMockBalanceRepository(ctrl)
+	expectTx(ctrl, uow, txOrders, txBalances)
+
+	gomock.InOrder(
+		txOrders.EXPECT().Reopen(gomock.Any(), int64(1)).Return(invalid, nil),
+		txOrders.EXPECT().
+			UpdateStatus(gomock.Any(), int64(1), domain.OrderStatusProcessed, gomock.Any()).
+			Return(nil),
+	)
+	correction := &domain.LedgerEntry{ID: 10, UserID: 7, Kind: domain.LedgerEntryAccrual, Amount: decimal.NewFromInt(200)}
+	txBalances.EXPECT().
+		SettleAccrual(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, a *domain.Accrual, description string) (*domain.LedgerEntry, error) {
+			assert.Equal(t, int64(7), a.UserID)
+			assert.True(t, decimal.NewFromInt(200).Equal(a.Amount))
+			assert.True(t, decimal.NewFromInt(100).Equal(a.Bonus))
+			assert.Contains(t, description, "12345678903")
+			return correction, nil
+		})
+
+	result, err := worker.Recheck(context.Background(), "12345678903")
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusInvalid, result.Previous.Status)
+	assert.Equal(t, domain.OrderStatusProcessed, result.Order.Status)
+	require.NotNil(t, result.Order.Accrual)
+	assert.True(t, decimal.NewFromInt(200).Equal(*result.Order.Accrual))
+	assert.Equal(t, correction, result.Correction)
+}
+
+func TestWorker_Recheck_Clawback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	accrualClient := mocks.NewMockAccrualClient(ctrl)
+
+	worker := accrual.NewWorker(orderRepo, uow, accrualClient, zerolog.Nop())
+
+	credited := decimal.NewFromInt(300)
+	processed := &domain.Order{ID: 1, UserID: 7, Number: "12345678903", Status: domain.OrderStatusProcessed, Accrual: &credited}
+	orderRepo.EXPECT().GetByNumber(gomock.Any(), "12345678903").Return(processed, nil)
+	orderRepo.EXPECT().ClaimLease(gomock.Any(), int64(1), accrual.DefaultLeaseTTL).Return(nil)
+	orderRepo.EXPECT().ReleaseLease(gomock.Any(), int64(1)).Return(nil)
+	accrualClient.EXPECT().
+		TryGetOrderAccrual(gomock.Any(), "12345678903").
+		Return(&ports.AccrualResponse{Order: "12345678903", Status: domain.OrderStatusInvalid}, nil)
+
+	txOrders := mocks.NewMockOrderRepository(ctrl)
+	txBalances := mocks.NewMockBalanceRepository(ctrl)
+	expectTx(ctrl, uow, txOrders, txBalances)
+
+	txOrders.EXPECT().Reopen(gomock.Any(), int64(1)).Return(processed, nil)
+	txOrders.EXPECT().UpdateStatus(gomock.Any(), int64(1), domain.OrderStatusInvalid, nil).Return(nil)
+	txBalances.EXPECT().
+		SettleAccrual(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, a *domain.Accrual, _ string) (*domain.LedgerEntry, error) {
+			assert.True(t, a.Amount.IsZero())
+			assert.True(t, a.Bonus.IsZero())
+			return &domain.LedgerEntry{Kind: domain.LedgerEntryAccrualCorrection, Amount: credited.Neg()}, nil
+		})
+
+	result, err := worker.Recheck(context.Background(), "12345678903")
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusInvalid, result.Order.Status)
+	assert.Nil(t, result.Order.Accrual)
+	assert.True(t, credited.Neg().Equal(result.Correction.Amount))
+}
+
+func TestWorker_Recheck_Errors(t *testing.T) {
+	order := &domain.Order{ID: 1, Number: "12345678903", Status: domain.OrderStatusInvalid}
+
+	tests := []struct {
+		name     string
+		order    *domain.Order
+		getErr   error
+		claimErr error
+		client   func(*mocks.MockAccrualClient)
+		wantErr  error
+	}{
+		{
+			name:    "заказ не найден",
+			getErr:  domain.ErrOrderNotFound,
+			client:  func(*mocks.MockAccrualClient) {},
+			wantErr: domain.ErrOrderNotFound,
+		},
+		{
+			name:    "отменённый заказ",
+			order:   &domain.Order{ID: 1, Number: "12345678903", Status: domain.OrderStatusCanceled},
+			client:  func(*mocks.MockAccrualClient) {},
+			wantErr: domain.ErrOrderNotFound,
+		},
+		{
+			name:     "заказ арендован другим обработчиком",
+			order:    order,
+			claimErr: domain.ErrOrderLocked,
+			client:   func(*mocks.MockAccrualClient) {},
+			wantErr:  domain.ErrOrderLocked,
+		},
+		{
+			name:  "исчерпан лимит запросов",
+			order: order,
+			client: func(c *mocks.MockAccrualClient) {
+				c.EXPECT().
+					TryGetOrderAccrual(gomock.Any(), "12345678903").
+					Return(nil, &accrual.RetryAfterError{Duration: time.Minute})
+			},
+			wantErr: domain.ErrAccrualRateLimited,
+		},
+		{
+			name:  "система начислений недоступна",
+			order: order,
+			client: func(c *mocks.MockAccrualClient) {
+				c.EXPECT().
+					TryGetOrderAccrual(gomock.Any(), "12345678903").
+					Return(nil, errors.New("connection refused"))
+			},
+			wantErr: domain.ErrAccrualUnavailable,
+		},
+		{
+			name:  "заказ не зарегистрирован",
+			order: order,
+			client: func(c *mocks.MockAccrualClient) {
+				c.EXPECT().
+					TryGetOrderAccrual(gomock.Any(), "12345678903").
+					Return(nil, nil)
+			},
+			wantErr: domain.ErrAccrualNotFinal,
+		},
+		{
+			name:  "расчёт не завершён",
+			order: order,
+			client: func(c *mocks.MockAccrualClient) {
+				c.EXPECT().
+					TryGetOrderAccrual(gomock.Any(), "12345678903").
+					Return(&ports.AccrualResponse{Order: "12345678903", Status: domain.OrderStatusProcessing}, nil)
+			},
+			wantErr: domain.ErrAccrualNotFinal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			orderRepo := mocks.NewMockOrderRepository(ctrl)
+			accrualClient := mocks.NewMockAccrualClient(ctrl)
+			// Заказ и начисление не меняются: транзакция не открывается.
+			worker := accrual.NewWorker(orderRepo, mocks.NewMockUnitOfWork(ctrl), accrualClient, zerolog.Nop())
+
+			orderRepo.EXPECT().GetByNumber(gomock.Any(), "12345678903").Return(tt.order, tt.getErr)
+			if tt.order != nil && tt.order.Status != domain.OrderStatusCanceled {
+				orderRepo.EXPECT().ClaimLease(gomock.Any(), int64(1), accrual.DefaultLeaseTTL).Return(tt.claimErr)
+				if tt.claimErr == nil {
+					orderRepo.EXPECT().ReleaseLease(gomock.Any(), int64(1)).Return(nil)
+				}
+			}
+			tt.client(accrualClient)
+
+			_, err := worker.Recheck(context.Background(), "12345678903")
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package balance
+
+import (
+	"context"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultHoldExpireInterval задаёт период проверки истёкших блокировок по умолчанию.
+	DefaultHoldExpireInterval = 1 * time.Minute
+	// DefaultPointsExpireInterval задаёт период проверки сгоревших партий баллов по умолчанию.
+	DefaultPointsExpireInterval = 1 * time.Hour
+
+	msgHoldExpirerStopping   = "остановка воркера истечения блокировок"
+	msgExpireHoldsError      = "ошибка завершения истёкших блокировок"
+	msgHoldsExpired          = "истёкшие блокировки завершены"
+	msgPointsExpirerStopping = "остановка воркера сгорания баллов"
+	msgExpirePointsError     = "ошибка сгорания баллов"
+	msgPointsExpired         = "сгоревшие партии баллов списаны"
+)
+
+// HoldExpirer периодически завершает блокировки баллов с истёкшим сроком,
+// чтобы они не оставались в статусе ACTIVE навсегда.
+type HoldExpirer struct {
+	balanceRepo ports.BalanceRepository
+	logger      zerolog.Logger
+	interval    time.Duration
+}
+
+// NewHoldExpirer создаёт новый воркер истечения блокировок.
+func NewHoldExpirer(balanceRepo ports.BalanceRepository, logger zerolog.Logger, interval time.Duration) *HoldExpirer {
+	if interval <= 0 {
+		interval = DefaultHoldExpireInterval
+	}
+
+	return &HoldExpirer{
+		balanceRepo: balanceRepo,
+		logger:      logger,
+		interval:    interval,
+	}
+}
+
+// Run запускает воркер истечения блокировок.
+func (e *HoldExpirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.logger.Info().Msg(msgHoldExpirerStopping)
+			return
+		case <-ticker.C:
+			e.expireHolds(ctx)
+		}
+	}
+}
+
+func (e *HoldExpirer) expireHolds(ctx context.Context) {
+	expired, err := e.balanceRepo.ExpireHolds(ctx)
+	if err != nil {
+		e.logger.Error().Err(err).Msg(msgExpireHoldsError)
+		return
+	}
+
+	if expired > 0 {
+		e.logger.Info().Int64("count", expired).Msg(msgHoldsExpired)
+	}
+}
+
+// PointsExpirer периодически списывает остатки партий баллов с истёкшим сроком действия.
+type PointsExpirer struct {
+	balanceRepo ports.BalanceRepository
+	logger      zerolog.Logger
+	interval    time.Duration
+}
+
+// NewPointsExpirer создаёт новый воркер сгорания баллов.
+func NewPointsExpirer(balanceRepo ports.BalanceRepository, logger zerolog.Logger, interval time.Duration) *PointsExpirer {
+	if interval <= 0 {
+		interval = DefaultPointsExpireInterval
+	}
+
+	return &PointsExpirer{
+		balanceRepo: balanceRepo,
+		logger:      logger,
+		interval:    interval,
+	}
+}
+
+// Run запускает воркер сгорания баллов.
+func (e *PointsExpirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.logger.Info().Msg(msgPointsExpirerStopping)
+			return
+		case <-ticker.C:
+			e.expirePoints(ctx)
+		}
+	}
+}
+
+func (e *PointsExpirer) expirePoints(ctx context.Context) {
+	expired, err := e.balanceRepo.ExpirePoints(ctx)
+	if err != nil {
+		e.logger.Error().Err(err).Msg(msgExpirePointsError)
+		return
+	}
+
+	if expired > 0 {
+		e.logger.Info().Int64("count", expired).Msg(msgPointsExpired)
+	}
+}
@@ -0,0 +1,97 @@
+package balance_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
+	"github.com/arvaliullin/gophermart/internal/core/services/balance"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHoldExpirer_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
+
+	expirer := balance.NewHoldExpirer(balanceRepo, logger, 10*time.Millisecond)
+
+	balanceRepo.EXPECT().
+		ExpireHolds(gomock.Any()).
+		Return(int64(2), nil).
+		MinTimes(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	expirer.Run(ctx)
+}
+
+func TestHoldExpirer_Run_RepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
+
+	expirer := balance.NewHoldExpirer(balanceRepo, logger, 10*time.Millisecond)
+
+	balanceRepo.EXPECT().
+		ExpireHolds(gomock.Any()).
+		Return(int64(0), errors.New("database error")).
+		MinTimes(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	expirer.Run(ctx)
+}
+
+func TestPointsExpirer_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
+
+	expirer := balance.NewPointsExpirer(balanceRepo, logger, 10*time.Millisecond)
+
+	balanceRepo.EXPECT().
+		ExpirePoints(gomock.Any()).
+		Return(int64(1), nil).
+		MinTimes(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	expirer.Run(ctx)
+}
+
+func TestTierRecomputer_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tierRepo := mocks.NewMockTierRepository(ctrl)
+	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
+	policy := domain.TierPolicy{{Tier: domain.TierSilver, Threshold: decimal.NewFromInt(1000)}}
+
+	recomputer := balance.NewTierRecomputer(tierRepo, policy, logger, 10*time.Millisecond)
+
+	tierRepo.EXPECT().
+		Recompute(gomock.Any(), policy).
+		Return(int64(1), nil).
+		MinTimes(2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	recomputer.Run(ctx)
+}
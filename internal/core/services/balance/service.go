@@ -2,42 +2,219 @@ package balance
 
 import (
 	"context"
+	"time"
 
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/arvaliullin/gophermart/internal/core/ports"
-	"github.com/arvaliullin/gophermart/internal/pkg/luhn"
+	"github.com/arvaliullin/gophermart/internal/core/services/ordernumber"
 	"github.com/shopspring/decimal"
 )
 
+const (
+	// DefaultHoldTTL задаёт срок действия блокировки баллов по умолчанию.
+	DefaultHoldTTL = 15 * time.Minute
+	// DefaultExpiringWindow задаёт горизонт, на котором баллы считаются скоро сгорающими.
+	DefaultExpiringWindow = 30 * 24 * time.Hour
+)
+
 // Service реализует бизнес-логику управления балансом.
 type Service struct {
 	balanceRepo    ports.BalanceRepository
 	withdrawalRepo ports.WithdrawalRepository
+	userRepo       ports.UserRepository
+	holdTTL        time.Duration
+	expiringWindow time.Duration
+
+	maxTransfer   decimal.Decimal
+	dailyTransfer decimal.Decimal
+
+	withdrawalLimits domain.WithdrawalLimits
+
+	validator ports.OrderNumberValidator
+}
+
+// Option определяет функциональную опцию для настройки сервиса баланса.
+type Option func(*Service)
+
+// WithHoldTTL устанавливает срок действия блокировки баллов.
+func WithHoldTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		if ttl > 0 {
+			s.holdTTL = ttl
+		}
+	}
+}
+
+// WithExpiringWindow устанавливает горизонт, на котором баллы считаются скоро сгорающими.
+func WithExpiringWindow(window time.Duration) Option {
+	return func(s *Service) {
+		if window > 0 {
+			s.expiringWindow = window
+		}
+	}
+}
+
+// WithTransferLimits устанавливает лимиты переводов баллов: на один перевод
+// и суммарно за сутки. Нулевое значение снимает соответствующий лимит.
+func WithTransferLimits(perTransfer, daily decimal.Decimal) Option {
+	return func(s *Service) {
+		s.maxTransfer = perTransfer
+		s.dailyTransfer = daily
+	}
+}
+
+// WithWithdrawalLimits устанавливает лимиты списаний по умолчанию.
+// Индивидуальные лимиты пользователя, назначенные администратором,
+// имеют приоритет над ними.
+func WithWithdrawalLimits(limits domain.WithdrawalLimits) Option {
+	return func(s *Service) {
+		s.withdrawalLimits = limits
+	}
+}
+
+// WithOrderNumberValidator задаёт проверку номеров заказов при списании
+// и блокировке баллов. По умолчанию номера проверяются алгоритмом Луна.
+func WithOrderNumberValidator(validator ports.OrderNumberValidator) Option {
+	return func(s *Service) {
+		if validator != nil {
+			s.validator = validator
+		}
+	}
 }
 
 // NewService создаёт новый сервис баланса.
-func NewService(balanceRepo ports.BalanceRepository, withdrawalRepo ports.WithdrawalRepository) *Service {
-	return &Service{
+func NewService(
+	balanceRepo ports.BalanceRepository,
+	withdrawalRepo ports.WithdrawalRepository,
+	userRepo ports.UserRepository,
+	opts ...Option,
+) *Service {
+	s := &Service{
 		balanceRepo:    balanceRepo,
 		withdrawalRepo: withdrawalRepo,
+		userRepo:       userRepo,
+		holdTTL:        DefaultHoldTTL,
+		expiringWindow: DefaultExpiringWindow,
+		validator:      ordernumber.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
 }
 
-// GetBalance возвращает баланс пользователя.
+// GetBalance возвращает баланс пользователя вместе с суммой баллов,
+// которые сгорят в пределах expiringWindow.
 func (s *Service) GetBalance(ctx context.Context, userID int64) (*domain.Balance, error) {
-	return s.balanceRepo.GetByUserID(ctx, userID)
+	balance, err := s.balanceRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance.ExpiringSoon, err = s.balanceRepo.GetExpiring(ctx, userID, time.Now().Add(s.expiringWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	return balance, nil
 }
 
 // Withdraw выполняет списание средств с баланса пользователя.
+//
+// Лимиты списаний проверяются в репозитории в одной транзакции со списанием.
 func (s *Service) Withdraw(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal) error {
-	if !luhn.IsValid(orderNumber) {
-		return domain.ErrInvalidOrderNumber
+	if err := s.validator.Validate(orderNumber); err != nil {
+		return err
 	}
 
-	return s.balanceRepo.Withdraw(ctx, userID, orderNumber, amount)
+	return s.balanceRepo.Withdraw(ctx, userID, orderNumber, amount, s.withdrawalLimits)
 }
 
 // GetWithdrawals возвращает все списания пользователя.
 func (s *Service) GetWithdrawals(ctx context.Context, userID int64) ([]*domain.Withdrawal, error) {
 	return s.withdrawalRepo.GetByUserID(ctx, userID)
 }
+
+// ListWithdrawals возвращает страницу списаний пользователя, отобранных по query.
+// Без лимита возвращаются все подходящие списания одной страницей.
+func (s *Service) ListWithdrawals(ctx context.Context, userID int64, query domain.ListQuery) (*domain.WithdrawalPage, error) {
+	if !query.IsValid() {
+		return nil, domain.ErrInvalidListQuery
+	}
+
+	// Лишняя строка показывает, есть ли следующая страница.
+	limit := query.Limit
+	if limit > 0 {
+		query.Limit++
+	}
+
+	withdrawals, err := s.withdrawalRepo.List(ctx, userID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.WithdrawalPage{Withdrawals: withdrawals}
+	if limit > 0 && len(withdrawals) > limit {
+		last := withdrawals[limit-1]
+		page.Withdrawals = withdrawals[:limit]
+		page.Next = &domain.PageCursor{Time: last.ProcessedAt, ID: last.ID}
+	}
+
+	return page, nil
+}
+
+// Refund возвращает баллы по списанию полностью или частично на счёт
+// пользователя, оформившего списание. Если amount равен nil, возвращается
+// вся ещё не возвращённая часть списания.
+func (s *Service) Refund(ctx context.Context, withdrawalID int64, amount *decimal.Decimal) (*domain.Refund, error) {
+	if amount != nil && !amount.IsPositive() {
+		return nil, domain.ErrInvalidRefundAmount
+	}
+
+	return s.balanceRepo.Refund(ctx, withdrawalID, amount)
+}
+
+// Hold блокирует баллы пользователя под оплату заказа на срок holdTTL.
+// Блокировка проверяется на лимиты списаний так же, как списание.
+func (s *Service) Hold(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal) (*domain.Hold, error) {
+	if err := s.validator.Validate(orderNumber); err != nil {
+		return nil, err
+	}
+
+	return s.balanceRepo.Hold(ctx, userID, orderNumber, amount, time.Now().Add(s.holdTTL), s.withdrawalLimits)
+}
+
+// CaptureHold подтверждает блокировку и списывает заблокированные баллы.
+// Вызывается магазином после оплаты заказа; к списанию применяются
+// лимиты, как и к обычному списанию.
+func (s *Service) CaptureHold(ctx context.Context, holdID int64) (*domain.Withdrawal, error) {
+	return s.balanceRepo.CaptureHold(ctx, holdID, s.withdrawalLimits)
+}
+
+// ReleaseHold отменяет блокировку и возвращает баллы в доступный остаток.
+func (s *Service) ReleaseHold(ctx context.Context, holdID int64) (*domain.Hold, error) {
+	return s.balanceRepo.ReleaseHold(ctx, holdID)
+}
+
+// Transfer переводит баллы пользователю с логином toLogin.
+//
+// Лимит на один перевод проверяется сразу, суточный лимит — атомарно вместе
+// со списанием в репозитории.
+func (s *Service) Transfer(ctx context.Context, fromUserID int64, toLogin string, amount decimal.Decimal) (*domain.Transfer, error) {
+	if s.maxTransfer.IsPositive() && amount.GreaterThan(s.maxTransfer) {
+		return nil, domain.ErrTransferLimitExceeded
+	}
+
+	recipient, err := s.userRepo.GetByLogin(ctx, toLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	if recipient.ID == fromUserID {
+		return nil, domain.ErrTransferToSelf
+	}
+
+	return s.balanceRepo.Transfer(ctx, fromUserID, recipient.ID, amount, s.dailyTransfer)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
 	"github.com/arvaliullin/gophermart/internal/core/services/balance"
+	"github.com/arvaliullin/gophermart/internal/core/services/ordernumber"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,7 +21,7 @@ func TestService_GetBalance_Success(t *testing.T) {
 
 	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
 	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
-	service := balance.NewService(balanceRepo, withdrawalRepo)
+	service := balance.NewService(balanceRepo, withdrawalRepo, mocks.NewMockUserRepository(ctrl))
 
 	expectedBalance := &domain.Balance{
 		UserID:    1,
@@ -31,12 +32,41 @@ func TestService_GetBalance_Success(t *testing.T) {
 	balanceRepo.EXPECT().
 		GetByUserID(gomock.Any(), int64(1)).
 		Return(expectedBalance, nil)
+	balanceRepo.EXPECT().
+		GetExpiring(gomock.Any(), int64(1), gomock.Any()).
+		Return(decimal.NewFromFloat(20.0), nil)
 
 	result, err := service.GetBalance(context.Background(), 1)
 
 	require.NoError(t, err)
 	assert.True(t, expectedBalance.Current.Equal(result.Current))
 	assert.True(t, expectedBalance.Withdrawn.Equal(result.Withdrawn))
+	assert.True(t, decimal.NewFromFloat(20.0).Equal(result.ExpiringSoon))
+}
+
+func TestService_GetBalance_ExpiringWindow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
+	service := balance.NewService(balanceRepo, withdrawalRepo, mocks.NewMockUserRepository(ctrl), balance.WithExpiringWindow(7*24*time.Hour))
+
+	balanceRepo.EXPECT().
+		GetByUserID(gomock.Any(), int64(1)).
+		Return(&domain.Balance{UserID: 1}, nil)
+
+	wantBefore := time.Now().Add(7 * 24 * time.Hour)
+	balanceRepo.EXPECT().
+		GetExpiring(gomock.Any(), int64(1), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int64, before time.Time) (decimal.Decimal, error) {
+			assert.WithinDuration(t, wantBefore, before, time.Minute)
+			return decimal.Zero, nil
+		})
+
+	_, err := service.GetBalance(context.Background(), 1)
+
+	require.NoError(t, err)
 }
 
 func TestService_Withdraw_Success(t *testing.T) {
@@ -45,11 +75,11 @@ func TestService_Withdraw_Success(t *testing.T) {
 
 	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
 	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
-	service := balance.NewService(balanceRepo, withdrawalRepo)
+	service := balance.NewService(balanceRepo, withdrawalRepo, mocks.NewMockUserRepository(ctrl))
 
 	amount := decimal.NewFromInt(100)
 	balanceRepo.EXPECT().
-		Withdraw(gomock.Any(), int64(1), "79927398713", amount).
+		Withdraw(gomock.Any(), int64(1), "79927398713", amount, domain.WithdrawalLimits{}).
 		Return(nil)
 
 	err := service.Withdraw(context.Background(), 1, "79927398713", amount)
@@ -63,11 +93,31 @@ func TestService_Withdraw_InvalidOrderNumber(t *testing.T) {
 
 	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
 	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
-	service := balance.NewService(balanceRepo, withdrawalRepo)
+	service := balance.NewService(balanceRepo, withdrawalRepo, mocks.NewMockUserRepository(ctrl))
 
 	err := service.Withdraw(context.Background(), 1, "invalid-number", decimal.NewFromInt(100))
 
 	assert.ErrorIs(t, err, domain.ErrInvalidOrderNumber)
+	assert.ErrorIs(t, err, ordernumber.ErrNonDigit)
+}
+
+func TestService_Withdraw_CustomValidator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validator := mocks.NewMockOrderNumberValidator(ctrl)
+	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	service := balance.NewService(balanceRepo, mocks.NewMockWithdrawalRepository(ctrl),
+		mocks.NewMockUserRepository(ctrl), balance.WithOrderNumberValidator(validator))
+
+	validator.EXPECT().Validate("2363").Return(nil)
+	balanceRepo.EXPECT().
+		Withdraw(gomock.Any(), int64(1), "2363", decimal.NewFromInt(100), gomock.Any()).
+		Return(nil)
+
+	err := service.Withdraw(context.Background(), 1, "2363", decimal.NewFromInt(100))
+
+	require.NoError(t, err)
 }
 
 func TestService_Withdraw_InsufficientBalance(t *testing.T) {
@@ -76,11 +126,11 @@ func TestService_Withdraw_InsufficientBalance(t *testing.T) {
 
 	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
 	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
-	service := balance.NewService(balanceRepo, withdrawalRepo)
+	service := balance.NewService(balanceRepo, withdrawalRepo, mocks.NewMockUserRepository(ctrl))
 
 	amount := decimal.NewFromInt(1000)
 	balanceRepo.EXPECT().
-		Withdraw(gomock.Any(), int64(1), "79927398713", amount).
+		Withdraw(gomock.Any(), int64(1), "79927398713", amount, domain.WithdrawalLimits{}).
 		Return(domain.ErrInsufficientBalance)
 
 	err := service.Withdraw(context.Background(), 1, "79927398713", amount)
@@ -88,13 +138,36 @@ func TestService_Withdraw_InsufficientBalance(t *testing.T) {
 	assert.ErrorIs(t, err, domain.ErrInsufficientBalance)
 }
 
+func TestService_Withdraw_PassesLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	limits := domain.WithdrawalLimits{
+		PerTransaction: decimal.NewFromInt(500),
+		Daily:          decimal.NewFromInt(1000),
+		Monthly:        decimal.NewFromInt(5000),
+	}
+	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	service := balance.NewService(balanceRepo, mocks.NewMockWithdrawalRepository(ctrl), mocks.NewMockUserRepository(ctrl),
+		balance.WithWithdrawalLimits(limits))
+
+	amount := decimal.NewFromInt(600)
+	balanceRepo.EXPECT().
+		Withdraw(gomock.Any(), int64(1), "79927398713", amount, limits).
+		Return(domain.ErrWithdrawalLimitExceeded)
+
+	err := service.Withdraw(context.Background(), 1, "79927398713", amount)
+
+	assert.ErrorIs(t, err, domain.ErrWithdrawalLimitExceeded)
+}
+
 func TestService_GetWithdrawals_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
 	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
-	service := balance.NewService(balanceRepo, withdrawalRepo)
+	service := balance.NewService(balanceRepo, withdrawalRepo, mocks.NewMockUserRepository(ctrl))
 
 	withdrawals := []*domain.Withdrawal{
 		{
@@ -123,13 +196,39 @@ func TestService_GetWithdrawals_Success(t *testing.T) {
 	assert.Len(t, result, 2)
 }
 
+func TestService_ListWithdrawals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
+	service := balance.NewService(mocks.NewMockBalanceRepository(ctrl), withdrawalRepo, mocks.NewMockUserRepository(ctrl))
+
+	processedAt := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	withdrawalRepo.EXPECT().
+		List(gomock.Any(), int64(1), domain.ListQuery{Limit: 2, Ascending: true}).
+		Return([]*domain.Withdrawal{
+			{ID: 1, ProcessedAt: processedAt},
+			{ID: 2, ProcessedAt: processedAt},
+		}, nil)
+
+	page, err := service.ListWithdrawals(context.Background(), 1, domain.ListQuery{Limit: 1, Ascending: true})
+
+	require.NoError(t, err)
+	assert.Len(t, page.Withdrawals, 1)
+	require.NotNil(t, page.Next)
+	assert.Equal(t, int64(1), page.Next.ID)
+
+	_, err = service.ListWithdrawals(context.Background(), 1, domain.ListQuery{Limit: -1})
+	assert.ErrorIs(t, err, domain.ErrInvalidListQuery)
+}
+
 func TestService_GetWithdrawals_Empty(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
 	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
-	service := balance.NewService(balanceRepo, withdrawalRepo)
+	service := balance.NewService(balanceRepo, withdrawalRepo, mocks.NewMockUserRepository(ctrl))
 
 	withdrawalRepo.EXPECT().
 		GetByUserID(gomock.Any(), int64(1)).
@@ -140,3 +239,237 @@ func TestService_GetWithdrawals_Empty(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, result)
 }
+
+func TestService_Refund_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
+	service := balance.NewService(balanceRepo, withdrawalRepo, mocks.NewMockUserRepository(ctrl))
+
+	amount := decimal.NewFromInt(40)
+	expected := &domain.Refund{ID: 1, WithdrawalID: 7, UserID: 1, Sum: amount}
+	balanceRepo.EXPECT().
+		Refund(gomock.Any(), int64(7), &amount).
+		Return(expected, nil)
+
+	refund, err := service.Refund(context.Background(), 7, &amount)
+
+	require.NoError(t, err)
+	assert.Equal(t, expected, refund)
+}
+
+func TestService_Refund_Full(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
+	service := balance.NewService(balanceRepo, withdrawalRepo, mocks.NewMockUserRepository(ctrl))
+
+	balanceRepo.EXPECT().
+		Refund(gomock.Any(), int64(7), (*decimal.Decimal)(nil)).
+		Return(&domain.Refund{ID: 1, WithdrawalID: 7, UserID: 1, Sum: decimal.NewFromInt(100)}, nil)
+
+	refund, err := service.Refund(context.Background(), 7, nil)
+
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(100).Equal(refund.Sum))
+}
+
+func TestService_Refund_InvalidAmount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
+	service := balance.NewService(balanceRepo, withdrawalRepo, mocks.NewMockUserRepository(ctrl))
+
+	amount := decimal.NewFromInt(-10)
+	_, err := service.Refund(context.Background(), 7, &amount)
+
+	assert.ErrorIs(t, err, domain.ErrInvalidRefundAmount)
+}
+
+func TestService_Refund_ExceedsWithdrawal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
+	service := balance.NewService(balanceRepo, withdrawalRepo, mocks.NewMockUserRepository(ctrl))
+
+	amount := decimal.NewFromInt(1000)
+	balanceRepo.EXPECT().
+		Refund(gomock.Any(), int64(7), &amount).
+		Return(nil, domain.ErrRefundExceedsWithdrawal)
+
+	_, err := service.Refund(context.Background(), 7, &amount)
+
+	assert.ErrorIs(t, err, domain.ErrRefundExceedsWithdrawal)
+}
+
+func TestService_Hold_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
+	limits := domain.WithdrawalLimits{Daily: decimal.NewFromInt(1000)}
+	service := balance.NewService(balanceRepo, withdrawalRepo, mocks.NewMockUserRepository(ctrl),
+		balance.WithHoldTTL(time.Hour), balance.WithWithdrawalLimits(limits))
+
+	amount := decimal.NewFromInt(100)
+	before := time.Now()
+	balanceRepo.EXPECT().
+		Hold(gomock.Any(), int64(1), "79927398713", amount, gomock.Any(), limits).
+		DoAndReturn(func(_ context.Context, userID int64, orderNumber string, amount decimal.Decimal, expiresAt time.Time, _ domain.WithdrawalLimits) (*domain.Hold, error) {
+			assert.WithinDuration(t, before.Add(time.Hour), expiresAt, time.Minute)
+			return &domain.Hold{ID: 1, UserID: userID, OrderNumber: orderNumber, Amount: amount, ExpiresAt: expiresAt}, nil
+		})
+
+	hold, err := service.Hold(context.Background(), 1, "79927398713", amount)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), hold.ID)
+}
+
+func TestService_Hold_InvalidOrderNumber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
+	service := balance.NewService(balanceRepo, withdrawalRepo, mocks.NewMockUserRepository(ctrl))
+
+	_, err := service.Hold(context.Background(), 1, "invalid-number", decimal.NewFromInt(100))
+
+	assert.ErrorIs(t, err, domain.ErrInvalidOrderNumber)
+}
+
+func TestService_CaptureHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
+	service := balance.NewService(balanceRepo, withdrawalRepo, mocks.NewMockUserRepository(ctrl))
+
+	expected := &domain.Withdrawal{ID: 3, UserID: 1, OrderNumber: "79927398713", Sum: decimal.NewFromInt(100)}
+	balanceRepo.EXPECT().
+		CaptureHold(gomock.Any(), int64(5), domain.WithdrawalLimits{}).
+		Return(expected, nil)
+
+	withdrawal, err := service.CaptureHold(context.Background(), 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, expected, withdrawal)
+}
+
+func TestService_CaptureHold_PassesLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	limits := domain.WithdrawalLimits{Daily: decimal.NewFromInt(1000)}
+	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	service := balance.NewService(balanceRepo, mocks.NewMockWithdrawalRepository(ctrl), mocks.NewMockUserRepository(ctrl),
+		balance.WithWithdrawalLimits(limits))
+
+	balanceRepo.EXPECT().
+		CaptureHold(gomock.Any(), int64(5), limits).
+		Return(nil, domain.ErrWithdrawalLimitExceeded)
+
+	_, err := service.CaptureHold(context.Background(), 5)
+
+	assert.ErrorIs(t, err, domain.ErrWithdrawalLimitExceeded)
+}
+
+func TestService_ReleaseHold_NotActive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
+	service := balance.NewService(balanceRepo, withdrawalRepo, mocks.NewMockUserRepository(ctrl))
+
+	balanceRepo.EXPECT().
+		ReleaseHold(gomock.Any(), int64(5)).
+		Return(nil, domain.ErrHoldNotActive)
+
+	_, err := service.ReleaseHold(context.Background(), 5)
+
+	assert.ErrorIs(t, err, domain.ErrHoldNotActive)
+}
+
+func TestService_Transfer(t *testing.T) {
+	amount := decimal.NewFromInt(100)
+
+	tests := []struct {
+		name    string
+		login   string
+		amount  decimal.Decimal
+		setup   func(*mocks.MockBalanceRepository, *mocks.MockUserRepository)
+		wantErr error
+	}{
+		{
+			name:   "success",
+			login:  "friend",
+			amount: amount,
+			setup: func(balanceRepo *mocks.MockBalanceRepository, userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().GetByLogin(gomock.Any(), "friend").Return(&domain.User{ID: 2, Login: "friend"}, nil)
+				balanceRepo.EXPECT().
+					Transfer(gomock.Any(), int64(1), int64(2), amount, decimal.NewFromInt(500)).
+					Return(&domain.Transfer{ID: 1, FromUserID: 1, ToUserID: 2, Amount: amount}, nil)
+			},
+		},
+		{
+			name:   "unknown recipient",
+			login:  "ghost",
+			amount: amount,
+			setup: func(balanceRepo *mocks.MockBalanceRepository, userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().GetByLogin(gomock.Any(), "ghost").Return(nil, domain.ErrUserNotFound)
+			},
+			wantErr: domain.ErrUserNotFound,
+		},
+		{
+			name:   "transfer to self",
+			login:  "me",
+			amount: amount,
+			setup: func(balanceRepo *mocks.MockBalanceRepository, userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().GetByLogin(gomock.Any(), "me").Return(&domain.User{ID: 1, Login: "me"}, nil)
+			},
+			wantErr: domain.ErrTransferToSelf,
+		},
+		{
+			name:    "per-transfer limit exceeded",
+			login:   "friend",
+			amount:  decimal.NewFromInt(300),
+			setup:   func(*mocks.MockBalanceRepository, *mocks.MockUserRepository) {},
+			wantErr: domain.ErrTransferLimitExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+			userRepo := mocks.NewMockUserRepository(ctrl)
+			tt.setup(balanceRepo, userRepo)
+
+			service := balance.NewService(balanceRepo, mocks.NewMockWithdrawalRepository(ctrl), userRepo,
+				balance.WithTransferLimits(decimal.NewFromInt(200), decimal.NewFromInt(500)))
+
+			transfer, err := service.Transfer(context.Background(), 1, tt.login, tt.amount)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), transfer.ToUserID)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package balance
+
+import (
+	"context"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultTierRecomputeInterval задаёт период пересчёта уровней лояльности по умолчанию.
+	DefaultTierRecomputeInterval = 1 * time.Hour
+
+	msgTierRecomputerStopping = "остановка воркера пересчёта уровней лояльности"
+	msgRecomputeTiersError    = "ошибка пересчёта уровней лояльности"
+	msgTiersRecomputed        = "уровни лояльности пересчитаны"
+)
+
+// TierRecomputer периодически пересчитывает уровни лояльности пользователей
+// по сумме начислений за заказы.
+type TierRecomputer struct {
+	tierRepo ports.TierRepository
+	policy   domain.TierPolicy
+	logger   zerolog.Logger
+	interval time.Duration
+}
+
+// NewTierRecomputer создаёт новый воркер пересчёта уровней лояльности.
+func NewTierRecomputer(tierRepo ports.TierRepository, policy domain.TierPolicy, logger zerolog.Logger, interval time.Duration) *TierRecomputer {
+	if interval <= 0 {
+		interval = DefaultTierRecomputeInterval
+	}
+
+	return &TierRecomputer{
+		tierRepo: tierRepo,
+		policy:   policy,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// Run запускает воркер пересчёта уровней лояльности.
+// Первый пересчёт выполняется сразу, чтобы уровни были доступны после запуска.
+func (r *TierRecomputer) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.recompute(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info().Msg(msgTierRecomputerStopping)
+			return
+		case <-ticker.C:
+			r.recompute(ctx)
+		}
+	}
+}
+
+func (r *TierRecomputer) recompute(ctx context.Context) {
+	updated, err := r.tierRepo.Recompute(ctx, r.policy)
+	if err != nil {
+		r.logger.Error().Err(err).Msg(msgRecomputeTiersError)
+		return
+	}
+
+	if updated > 0 {
+		r.logger.Info().Int64("count", updated).Msg(msgTiersRecomputed)
+	}
+}
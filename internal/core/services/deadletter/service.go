@@ -0,0 +1,53 @@
+package deadletter
+
+import (
+	"context"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+)
+
+// Service реализует администрирование заказов, снятых с опроса системы начислений.
+type Service struct {
+	orderRepo ports.OrderRepository
+}
+
+// NewService создаёт новый сервис снятых с опроса заказов.
+func NewService(orderRepo ports.OrderRepository) *Service {
+	return &Service{
+		orderRepo: orderRepo,
+	}
+}
+
+// List возвращает страницу снятых с опроса заказов, отобранных по query.
+// Без лимита возвращаются все подходящие заказы одной страницей.
+func (s *Service) List(ctx context.Context, query domain.ListQuery) (*domain.DeadLetterPage, error) {
+	if !query.IsValid() {
+		return nil, domain.ErrInvalidListQuery
+	}
+
+	// Лишняя строка показывает, есть ли следующая страница.
+	limit := query.Limit
+	if limit > 0 {
+		query.Limit++
+	}
+
+	orders, err := s.orderRepo.ListDeadLetters(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.DeadLetterPage{Orders: orders}
+	if limit > 0 && len(orders) > limit {
+		last := orders[limit-1]
+		page.Orders = orders[:limit]
+		page.Next = &domain.PageCursor{Time: last.DeadLetteredAt, ID: last.Order.ID}
+	}
+
+	return page, nil
+}
+
+// Requeue возвращает заказ в опрос системы начислений с начала расписания проверок.
+func (s *Service) Requeue(ctx context.Context, number string) (*domain.Order, error) {
+	return s.orderRepo.Requeue(ctx, number)
+}
@@ -0,0 +1,69 @@
+package deadletter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
+	"github.com/arvaliullin/gophermart/internal/core/services/deadletter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	service := deadletter.NewService(orderRepo)
+
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	orders := []*domain.DeadLetterOrder{
+		{Order: &domain.Order{ID: 3, Number: "3"}, LastError: "timeout", DeadLetteredAt: base.Add(2 * time.Hour)},
+		{Order: &domain.Order{ID: 2, Number: "2"}, LastError: "timeout", DeadLetteredAt: base.Add(time.Hour)},
+		{Order: &domain.Order{ID: 1, Number: "1"}, LastError: "timeout", DeadLetteredAt: base},
+	}
+
+	orderRepo.EXPECT().
+		ListDeadLetters(gomock.Any(), domain.ListQuery{Limit: 3}).
+		Return(orders, nil)
+
+	page, err := service.List(context.Background(), domain.ListQuery{Limit: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, orders[:2], page.Orders)
+	assert.Equal(t, &domain.PageCursor{Time: base.Add(time.Hour), ID: 2}, page.Next)
+}
+
+func TestService_List_InvalidQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := deadletter.NewService(mocks.NewMockOrderRepository(ctrl))
+
+	_, err := service.List(context.Background(), domain.ListQuery{Limit: domain.MaxListLimit + 1})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidListQuery)
+}
+
+func TestService_Requeue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	service := deadletter.NewService(orderRepo)
+
+	order := &domain.Order{ID: 1, Number: "12345678903", Status: domain.OrderStatusNew}
+	orderRepo.EXPECT().Requeue(gomock.Any(), "12345678903").Return(order, nil)
+	orderRepo.EXPECT().Requeue(gomock.Any(), "79927398713").Return(nil, domain.ErrOrderNotFound)
+
+	requeued, err := service.Requeue(context.Background(), "12345678903")
+	require.NoError(t, err)
+	assert.Equal(t, order, requeued)
+
+	_, err = service.Requeue(context.Background(), "79927398713")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+}
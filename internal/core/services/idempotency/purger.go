@@ -0,0 +1,74 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultPurgeInterval задаёт период очистки ключей идемпотентности по умолчанию.
+	DefaultPurgeInterval = 1 * time.Hour
+	// DefaultKeyTTL задаёт срок хранения ключей идемпотентности по умолчанию.
+	DefaultKeyTTL = 24 * time.Hour
+
+	msgPurgerStopping = "остановка воркера очистки ключей идемпотентности"
+	msgPurgeError     = "ошибка очистки ключей идемпотентности"
+	msgKeysPurged     = "устаревшие ключи идемпотентности удалены"
+)
+
+// Purger периодически удаляет ключи идемпотентности старше срока хранения,
+// чтобы таблица ключей не росла бесконечно.
+type Purger struct {
+	repo     ports.IdempotencyRepository
+	logger   zerolog.Logger
+	interval time.Duration
+	ttl      time.Duration
+}
+
+// NewPurger создаёт новый воркер очистки ключей идемпотентности.
+func NewPurger(repo ports.IdempotencyRepository, logger zerolog.Logger, interval, ttl time.Duration) *Purger {
+	if interval <= 0 {
+		interval = DefaultPurgeInterval
+	}
+	if ttl <= 0 {
+		ttl = DefaultKeyTTL
+	}
+
+	return &Purger{
+		repo:     repo,
+		logger:   logger,
+		interval: interval,
+		ttl:      ttl,
+	}
+}
+
+// Run запускает воркер очистки ключей идемпотентности.
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.logger.Info().Msg(msgPurgerStopping)
+			return
+		case <-ticker.C:
+			p.purge(ctx)
+		}
+	}
+}
+
+func (p *Purger) purge(ctx context.Context) {
+	purged, err := p.repo.PurgeExpired(ctx, time.Now().Add(-p.ttl))
+	if err != nil {
+		p.logger.Error().Err(err).Msg(msgPurgeError)
+		return
+	}
+
+	if purged > 0 {
+		p.logger.Info().Int64("count", purged).Msg(msgKeysPurged)
+	}
+}
@@ -0,0 +1,58 @@
+package idempotency_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
+	"github.com/arvaliullin/gophermart/internal/core/services/idempotency"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPurger_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
+
+	purger := idempotency.NewPurger(repo, logger, 10*time.Millisecond, time.Hour)
+
+	repo.EXPECT().
+		PurgeExpired(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, before time.Time) (int64, error) {
+			assert.WithinDuration(t, time.Now().Add(-time.Hour), before, time.Second)
+			return 3, nil
+		}).
+		MinTimes(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	purger.Run(ctx)
+}
+
+func TestPurger_Run_RepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
+
+	purger := idempotency.NewPurger(repo, logger, 10*time.Millisecond, time.Hour)
+
+	repo.EXPECT().
+		PurgeExpired(gomock.Any(), gomock.Any()).
+		Return(int64(0), errors.New("database error")).
+		MinTimes(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	purger.Run(ctx)
+}
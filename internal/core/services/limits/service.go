@@ -0,0 +1,79 @@
+package limits
+
+import (
+	"context"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+)
+
+// Service реализует администрирование индивидуальных лимитов списаний.
+type Service struct {
+	limitRepo ports.WithdrawalLimitRepository
+	userRepo  ports.UserRepository
+	defaults  domain.WithdrawalLimits
+}
+
+// NewService создаёт новый сервис лимитов списаний.
+// defaults задаёт лимиты, действующие для пользователей без индивидуальных.
+func NewService(limitRepo ports.WithdrawalLimitRepository, userRepo ports.UserRepository, defaults domain.WithdrawalLimits) *Service {
+	return &Service{
+		limitRepo: limitRepo,
+		userRepo:  userRepo,
+		defaults:  defaults,
+	}
+}
+
+// Get возвращает лимиты списаний, действующие для пользователя.
+func (s *Service) Get(ctx context.Context, userID int64) (*domain.UserWithdrawalLimits, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	override, err := s.limitRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.effective(userID, override), nil
+}
+
+// Set назначает пользователю индивидуальные лимиты и возвращает
+// действующие с их учётом лимиты.
+func (s *Service) Set(ctx context.Context, override *domain.WithdrawalLimitsOverride) (*domain.UserWithdrawalLimits, error) {
+	if !override.Apply(domain.WithdrawalLimits{}).IsValid() {
+		return nil, domain.ErrInvalidWithdrawalLimits
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, override.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.limitRepo.Set(ctx, override); err != nil {
+		return nil, err
+	}
+
+	return s.effective(override.UserID, override), nil
+}
+
+// Reset снимает индивидуальные лимиты пользователя и возвращает
+// лимиты по умолчанию.
+func (s *Service) Reset(ctx context.Context, userID int64) (*domain.UserWithdrawalLimits, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.limitRepo.Delete(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.effective(userID, nil), nil
+}
+
+func (s *Service) effective(userID int64, override *domain.WithdrawalLimitsOverride) *domain.UserWithdrawalLimits {
+	return &domain.UserWithdrawalLimits{
+		UserID:     userID,
+		Limits:     override.Apply(s.defaults),
+		Overridden: override != nil,
+	}
+}
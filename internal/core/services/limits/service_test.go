@@ -0,0 +1,106 @@
+package limits_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
+	"github.com/arvaliullin/gophermart/internal/core/services/limits"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var defaults = domain.WithdrawalLimits{
+	PerTransaction: decimal.NewFromInt(500),
+	Daily:          decimal.NewFromInt(1000),
+	Monthly:        decimal.NewFromInt(3000),
+}
+
+func TestService_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	limitRepo := mocks.NewMockWithdrawalLimitRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	service := limits.NewService(limitRepo, userRepo, defaults)
+
+	daily := decimal.NewFromInt(200)
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&domain.User{ID: 1}, nil)
+	limitRepo.EXPECT().GetByUserID(gomock.Any(), int64(1)).
+		Return(&domain.WithdrawalLimitsOverride{UserID: 1, Daily: &daily}, nil)
+
+	result, err := service.Get(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.True(t, result.Overridden)
+	assert.True(t, daily.Equal(result.Limits.Daily))
+	assert.True(t, defaults.Monthly.Equal(result.Limits.Monthly))
+}
+
+func TestService_Get_UserNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	service := limits.NewService(mocks.NewMockWithdrawalLimitRepository(ctrl), userRepo, defaults)
+
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(nil, domain.ErrUserNotFound)
+
+	_, err := service.Get(context.Background(), 1)
+
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+}
+
+func TestService_Set(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	limitRepo := mocks.NewMockWithdrawalLimitRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	service := limits.NewService(limitRepo, userRepo, defaults)
+
+	perTransaction := decimal.NewFromInt(50)
+	override := &domain.WithdrawalLimitsOverride{UserID: 1, PerTransaction: &perTransaction}
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&domain.User{ID: 1}, nil)
+	limitRepo.EXPECT().Set(gomock.Any(), override).Return(nil)
+
+	result, err := service.Set(context.Background(), override)
+
+	require.NoError(t, err)
+	assert.True(t, result.Overridden)
+	assert.True(t, perTransaction.Equal(result.Limits.PerTransaction))
+	assert.True(t, defaults.Daily.Equal(result.Limits.Daily))
+}
+
+func TestService_Set_NegativeLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := limits.NewService(mocks.NewMockWithdrawalLimitRepository(ctrl), mocks.NewMockUserRepository(ctrl), defaults)
+
+	negative := decimal.NewFromInt(-1)
+	_, err := service.Set(context.Background(), &domain.WithdrawalLimitsOverride{UserID: 1, Monthly: &negative})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidWithdrawalLimits)
+}
+
+func TestService_Reset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	limitRepo := mocks.NewMockWithdrawalLimitRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	service := limits.NewService(limitRepo, userRepo, defaults)
+
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&domain.User{ID: 1}, nil)
+	limitRepo.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+
+	result, err := service.Reset(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.False(t, result.Overridden)
+	assert.Equal(t, defaults, result.Limits)
+}
@@ -6,26 +6,56 @@ import (
 
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/arvaliullin/gophermart/internal/core/ports"
-	"github.com/arvaliullin/gophermart/internal/pkg/luhn"
+	"github.com/arvaliullin/gophermart/internal/core/services/ordernumber"
 )
 
+// MaxBatchSize задаёт максимальное количество номеров в пакетной загрузке заказов.
+const MaxBatchSize = 500
+
 // Service реализует бизнес-логику управления заказами.
 type Service struct {
-	orderRepo ports.OrderRepository
+	orderRepo      ports.OrderRepository
+	withdrawalRepo ports.WithdrawalRepository
+	validator      ports.OrderNumberValidator
+}
+
+// Option определяет функциональную опцию для настройки сервиса.
+type Option func(*Service)
+
+// WithWithdrawals включает в сведения о заказе списания, оформленные на его номер.
+func WithWithdrawals(withdrawalRepo ports.WithdrawalRepository) Option {
+	return func(s *Service) {
+		s.withdrawalRepo = withdrawalRepo
+	}
+}
+
+// WithOrderNumberValidator задаёт проверку номеров заказов.
+// По умолчанию номера проверяются алгоритмом Луна.
+func WithOrderNumberValidator(validator ports.OrderNumberValidator) Option {
+	return func(s *Service) {
+		if validator != nil {
+			s.validator = validator
+		}
+	}
 }
 
 // NewService создаёт новый сервис заказов.
-func NewService(orderRepo ports.OrderRepository) *Service {
-	return &Service{
+func NewService(orderRepo ports.OrderRepository, opts ...Option) *Service {
+	s := &Service{
 		orderRepo: orderRepo,
+		validator: ordernumber.Default(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // SubmitOrder добавляет новый заказ для пользователя.
 // Возвращает true, если заказ уже существовал у этого пользователя.
 func (s *Service) SubmitOrder(ctx context.Context, userID int64, number string) (bool, error) {
-	if !luhn.IsValid(number) {
-		return false, domain.ErrInvalidOrderNumber
+	if err := s.validator.Validate(number); err != nil {
+		return false, err
 	}
 
 	_, err := s.orderRepo.Create(ctx, userID, number)
@@ -47,7 +77,117 @@ func (s *Service) SubmitOrder(ctx context.Context, userID int64, number string)
 	return false, nil
 }
 
+// SubmitOrders добавляет пакет заказов пользователя.
+//
+// Повторяющиеся номера учитываются один раз. Номера, не прошедшие проверку,
+// получают результат domain.OrderSubmitInvalid, остальные создаются одним
+// запросом к репозиторию. Результаты возвращаются в порядке первого
+// вхождения номера в пакет.
+func (s *Service) SubmitOrders(ctx context.Context, userID int64, numbers []string) ([]*domain.OrderSubmission, error) {
+	if len(numbers) == 0 || len(numbers) > MaxBatchSize {
+		return nil, domain.ErrInvalidOrderBatch
+	}
+
+	results := make(map[string]*domain.OrderSubmission, len(numbers))
+	sequence := make([]string, 0, len(numbers))
+	valid := make([]string, 0, len(numbers))
+	for _, number := range numbers {
+		if _, seen := results[number]; seen {
+			continue
+		}
+		sequence = append(sequence, number)
+
+		if err := s.validator.Validate(number); err != nil {
+			results[number] = &domain.OrderSubmission{Number: number, Result: domain.OrderSubmitInvalid, Reason: err.Error()}
+			continue
+		}
+		results[number] = nil
+		valid = append(valid, number)
+	}
+
+	if len(valid) > 0 {
+		submissions, err := s.orderRepo.CreateBatch(ctx, userID, valid)
+		if err != nil {
+			return nil, err
+		}
+		for _, submission := range submissions {
+			results[submission.Number] = submission
+		}
+	}
+
+	submissions := make([]*domain.OrderSubmission, 0, len(sequence))
+	for _, number := range sequence {
+		if result := results[number]; result != nil {
+			submissions = append(submissions, result)
+		}
+	}
+
+	return submissions, nil
+}
+
 // GetUserOrders возвращает все заказы пользователя.
 func (s *Service) GetUserOrders(ctx context.Context, userID int64) ([]*domain.Order, error) {
 	return s.orderRepo.GetByUserID(ctx, userID)
 }
+
+// ListOrders возвращает страницу заказов пользователя, отобранных по query.
+// Без лимита возвращаются все подходящие заказы одной страницей.
+func (s *Service) ListOrders(ctx context.Context, userID int64, query domain.OrderListQuery) (*domain.OrderPage, error) {
+	if !query.IsValid() {
+		return nil, domain.ErrInvalidListQuery
+	}
+
+	// Лишняя строка показывает, есть ли следующая страница.
+	limit := query.Limit
+	if limit > 0 {
+		query.Limit++
+	}
+
+	orders, err := s.orderRepo.List(ctx, userID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.OrderPage{Orders: orders}
+	if limit > 0 && len(orders) > limit {
+		last := orders[limit-1]
+		page.Orders = orders[:limit]
+		page.Next = &domain.PageCursor{Time: last.UploadedAt, ID: last.ID}
+	}
+
+	return page, nil
+}
+
+// GetOrder возвращает заказ пользователя с историей статусов и списаниями
+// пользователя, оформленными на номер заказа.
+// Чужой заказ не раскрывается: возвращается domain.ErrOrderNotFound.
+func (s *Service) GetOrder(ctx context.Context, userID int64, number string) (*domain.OrderDetails, error) {
+	order, err := s.orderRepo.GetByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	history, err := s.orderRepo.GetStatusHistory(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &domain.OrderDetails{Order: order, History: history}
+	if s.withdrawalRepo != nil {
+		details.Withdrawals, err = s.withdrawalRepo.GetByOrderNumber(ctx, userID, number)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return details, nil
+}
+
+// CancelOrder отменяет заказ пользователя, обработка которого ещё не началась.
+// После отмены номер заказа может быть загружен повторно.
+func (s *Service) CancelOrder(ctx context.Context, userID int64, number string) (*domain.Order, error) {
+	return s.orderRepo.Cancel(ctx, userID, number)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
 	"github.com/arvaliullin/gophermart/internal/core/services/order"
+	"github.com/arvaliullin/gophermart/internal/core/services/ordernumber"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -49,6 +50,43 @@ func TestService_SubmitOrder_InvalidLuhn(t *testing.T) {
 	_, err := service.SubmitOrder(context.Background(), 1, "12345678901")
 
 	assert.ErrorIs(t, err, domain.ErrInvalidOrderNumber)
+	assert.ErrorIs(t, err, ordernumber.ErrChecksumMismatch)
+}
+
+func TestService_SubmitOrder_CustomValidator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validator, err := ordernumber.NewValidator(ordernumber.AlgorithmDamm)
+	require.NoError(t, err)
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	service := order.NewService(orderRepo, order.WithOrderNumberValidator(validator))
+
+	orderRepo.EXPECT().
+		Create(gomock.Any(), int64(1), "5724").
+		Return(&domain.Order{ID: 1, UserID: 1, Number: "5724", Status: domain.OrderStatusNew}, nil)
+
+	alreadyExists, err := service.SubmitOrder(context.Background(), 1, "5724")
+	require.NoError(t, err)
+	assert.False(t, alreadyExists)
+
+	_, err = service.SubmitOrder(context.Background(), 1, "12345678903")
+	assert.ErrorIs(t, err, domain.ErrInvalidOrderNumber)
+}
+
+func TestService_SubmitOrder_PrefixValidator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validator, err := ordernumber.NewValidator("damm:57")
+	require.NoError(t, err)
+
+	service := order.NewService(mocks.NewMockOrderRepository(ctrl), order.WithOrderNumberValidator(validator))
+
+	_, err = service.SubmitOrder(context.Background(), 1, "79927398713")
+	assert.ErrorIs(t, err, domain.ErrInvalidOrderNumber)
+	assert.ErrorIs(t, err, ordernumber.ErrPrefixMismatch)
 }
 
 func TestService_SubmitOrder_AlreadyExistsSameUser(t *testing.T) {
@@ -160,3 +198,204 @@ func TestService_GetUserOrders_Empty(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, result)
 }
+
+func TestService_SubmitOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	service := order.NewService(orderRepo)
+
+	orderRepo.EXPECT().
+		CreateBatch(gomock.Any(), int64(1), []string{"12345678903", "79927398713", "2377225624"}).
+		Return([]*domain.OrderSubmission{
+			{Number: "2377225624", Result: domain.OrderSubmitBelongsToOther},
+			{Number: "12345678903", Result: domain.OrderSubmitAccepted},
+			{Number: "79927398713", Result: domain.OrderSubmitAlreadyUploaded},
+		}, nil)
+
+	submissions, err := service.SubmitOrders(context.Background(), 1,
+		[]string{"12345678903", "123", "79927398713", "12345678903", "2377225624"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.OrderSubmission{
+		{Number: "12345678903", Result: domain.OrderSubmitAccepted},
+		{Number: "123", Result: domain.OrderSubmitInvalid,
+			Reason: "неверный формат номера заказа: не совпадает контрольная сумма (luhn)"},
+		{Number: "79927398713", Result: domain.OrderSubmitAlreadyUploaded},
+		{Number: "2377225624", Result: domain.OrderSubmitBelongsToOther},
+	}, submissions)
+}
+
+func TestService_SubmitOrders_AllInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := order.NewService(mocks.NewMockOrderRepository(ctrl))
+
+	submissions, err := service.SubmitOrders(context.Background(), 1, []string{"123", "abc"})
+
+	require.NoError(t, err)
+	require.Len(t, submissions, 2)
+	assert.Equal(t, domain.OrderSubmitInvalid, submissions[0].Result)
+	assert.Equal(t, domain.OrderSubmitInvalid, submissions[1].Result)
+}
+
+func TestService_SubmitOrders_InvalidBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := order.NewService(mocks.NewMockOrderRepository(ctrl))
+
+	_, err := service.SubmitOrders(context.Background(), 1, nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidOrderBatch)
+
+	_, err = service.SubmitOrders(context.Background(), 1, make([]string, order.MaxBatchSize+1))
+	assert.ErrorIs(t, err, domain.ErrInvalidOrderBatch)
+}
+
+func TestService_ListOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	service := order.NewService(orderRepo)
+
+	uploadedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	orders := []*domain.Order{
+		{ID: 3, Number: "12345678903", UploadedAt: uploadedAt},
+		{ID: 2, Number: "79927398713", UploadedAt: uploadedAt},
+		{ID: 1, Number: "2377225624", UploadedAt: uploadedAt},
+	}
+	query := domain.OrderListQuery{
+		ListQuery: domain.ListQuery{Limit: 2},
+		Statuses:  []domain.OrderStatus{domain.OrderStatusNew},
+	}
+	repoQuery := query
+	repoQuery.Limit = 3
+	orderRepo.EXPECT().List(gomock.Any(), int64(1), repoQuery).Return(orders, nil)
+
+	page, err := service.ListOrders(context.Background(), 1, query)
+
+	require.NoError(t, err)
+	assert.Len(t, page.Orders, 2)
+	require.NotNil(t, page.Next)
+	assert.Equal(t, int64(2), page.Next.ID)
+	assert.True(t, uploadedAt.Equal(page.Next.Time))
+}
+
+func TestService_ListOrders_LastPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	service := order.NewService(orderRepo)
+
+	orderRepo.EXPECT().List(gomock.Any(), int64(1), gomock.Any()).
+		Return([]*domain.Order{{ID: 1, Number: "12345678903"}}, nil)
+
+	page, err := service.ListOrders(context.Background(), 1, domain.OrderListQuery{ListQuery: domain.ListQuery{Limit: 2}})
+
+	require.NoError(t, err)
+	assert.Len(t, page.Orders, 1)
+	assert.Nil(t, page.Next)
+}
+
+func TestService_ListOrders_InvalidQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := order.NewService(mocks.NewMockOrderRepository(ctrl))
+
+	_, err := service.ListOrders(context.Background(), 1, domain.OrderListQuery{Statuses: []domain.OrderStatus{"DONE"}})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidListQuery)
+}
+
+func TestService_GetOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	service := order.NewService(orderRepo)
+
+	existing := &domain.Order{ID: 7, UserID: 1, Number: "12345678903", Status: domain.OrderStatusProcessing}
+	history := []*domain.OrderStatusChange{
+		{Status: domain.OrderStatusNew},
+		{Status: domain.OrderStatusProcessing},
+	}
+	orderRepo.EXPECT().GetByNumber(gomock.Any(), "12345678903").Return(existing, nil)
+	orderRepo.EXPECT().GetStatusHistory(gomock.Any(), int64(7)).Return(history, nil)
+
+	details, err := service.GetOrder(context.Background(), 1, "12345678903")
+
+	require.NoError(t, err)
+	assert.Equal(t, existing, details.Order)
+	assert.Equal(t, history, details.History)
+}
+
+func TestService_GetOrder_WithWithdrawals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
+	service := order.NewService(orderRepo, order.WithWithdrawals(withdrawalRepo))
+
+	withdrawals := []*domain.Withdrawal{{ID: 3, UserID: 1, OrderNumber: "12345678903", Sum: decimal.NewFromInt(50)}}
+	orderRepo.EXPECT().GetByNumber(gomock.Any(), "12345678903").
+		Return(&domain.Order{ID: 7, UserID: 1, Number: "12345678903"}, nil)
+	orderRepo.EXPECT().GetStatusHistory(gomock.Any(), int64(7)).Return(nil, nil)
+	withdrawalRepo.EXPECT().GetByOrderNumber(gomock.Any(), int64(1), "12345678903").Return(withdrawals, nil)
+
+	details, err := service.GetOrder(context.Background(), 1, "12345678903")
+
+	require.NoError(t, err)
+	assert.Equal(t, withdrawals, details.Withdrawals)
+}
+
+func TestService_GetOrder_NotFound(t *testing.T) {
+	tests := []struct {
+		name  string
+		order *domain.Order
+		err   error
+	}{
+		{name: "заказ не существует", err: domain.ErrOrderNotFound},
+		{name: "заказ другого пользователя", order: &domain.Order{ID: 7, UserID: 2, Number: "12345678903"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			orderRepo := mocks.NewMockOrderRepository(ctrl)
+			service := order.NewService(orderRepo)
+
+			orderRepo.EXPECT().GetByNumber(gomock.Any(), "12345678903").Return(tt.order, tt.err)
+
+			_, err := service.GetOrder(context.Background(), 1, "12345678903")
+
+			assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		})
+	}
+}
+
+func TestService_CancelOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	service := order.NewService(orderRepo)
+
+	canceled := &domain.Order{ID: 7, UserID: 1, Number: "12345678903", Status: domain.OrderStatusCanceled}
+	orderRepo.EXPECT().Cancel(gomock.Any(), int64(1), "12345678903").Return(canceled, nil)
+	orderRepo.EXPECT().Cancel(gomock.Any(), int64(1), "79927398713").Return(nil, domain.ErrOrderNotCancelable)
+
+	result, err := service.CancelOrder(context.Background(), 1, "12345678903")
+	require.NoError(t, err)
+	assert.Equal(t, canceled, result)
+
+	_, err = service.CancelOrder(context.Background(), 1, "79927398713")
+	assert.ErrorIs(t, err, domain.ErrOrderNotCancelable)
+}
@@ -0,0 +1,173 @@
+package ordernumber
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/pkg/damm"
+	"github.com/arvaliullin/gophermart/internal/pkg/luhn"
+	"github.com/arvaliullin/gophermart/internal/pkg/mod97"
+	"github.com/arvaliullin/gophermart/internal/pkg/verhoeff"
+)
+
+const (
+	// AlgorithmLuhn — проверка алгоритмом Луна.
+	AlgorithmLuhn = "luhn"
+	// AlgorithmVerhoeff — проверка алгоритмом Верхуффа.
+	AlgorithmVerhoeff = "verhoeff"
+	// AlgorithmDamm — проверка алгоритмом Дамма.
+	AlgorithmDamm = "damm"
+	// AlgorithmMod97 — проверка по ISO 7064 MOD 97-10.
+	AlgorithmMod97 = "mod97"
+)
+
+var (
+	// ErrUnknownAlgorithm возвращается при настройке неизвестного алгоритма проверки.
+	ErrUnknownAlgorithm = fmt.Errorf("неизвестный алгоритм проверки номера заказа")
+	// ErrNoAlgorithms возвращается, если не задан ни один алгоритм проверки.
+	ErrNoAlgorithms = fmt.Errorf("не задан ни один алгоритм проверки номера заказа")
+	// ErrInvalidPrefix возвращается при настройке префикса не из цифр.
+	ErrInvalidPrefix = fmt.Errorf("префикс номера заказа должен состоять только из цифр")
+
+	// ErrEmptyNumber описывает отказ из-за пустого номера.
+	ErrEmptyNumber = fmt.Errorf("номер пуст")
+	// ErrNonDigit описывает отказ из-за посторонних символов в номере.
+	ErrNonDigit = fmt.Errorf("номер должен состоять только из цифр")
+	// ErrChecksumMismatch описывает отказ из-за неверной контрольной суммы.
+	ErrChecksumMismatch = fmt.Errorf("не совпадает контрольная сумма")
+	// ErrPrefixMismatch описывает отказ из-за номера с неизвестным префиксом.
+	ErrPrefixMismatch = fmt.Errorf("номер не начинается с допустимого префикса")
+)
+
+// Algorithm проверяет контрольную сумму номера, состоящего только из цифр.
+type Algorithm func(number string) bool
+
+// registry содержит алгоритмы, доступные для настройки по имени.
+var registry = map[string]Algorithm{
+	AlgorithmLuhn:     luhn.IsValid,
+	AlgorithmVerhoeff: verhoeff.IsValid,
+	AlgorithmDamm:     damm.IsValid,
+	AlgorithmMod97:    mod97.IsValid,
+}
+
+// Algorithms возвращает отсортированные имена доступных алгоритмов.
+func Algorithms() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rule проверяет номера с префиксом prefix алгоритмом algorithm.
+// Пустой префикс подходит для любого номера.
+type rule struct {
+	prefix    string
+	algorithm Algorithm
+}
+
+// Validator проверяет номера заказов набором правил вида «алгоритм[:префикс]».
+// Номер считается корректным, если проходит проверку хотя бы одним правилом,
+// префикс которого совпадает с началом номера.
+type Validator struct {
+	names []string
+	rules []rule
+}
+
+// NewValidator создаёт валидатор из правил specs. Правило задаётся именем
+// алгоритма, например "luhn", или именем и префиксом номеров магазина через
+// двоеточие, например "damm:77": такое правило проверяет только номера,
+// начинающиеся с 77, с учётом префикса в контрольной сумме.
+// Имена нечувствительны к регистру, повторы игнорируются.
+func NewValidator(specs ...string) (*Validator, error) {
+	v := &Validator{}
+	seen := make(map[string]struct{}, len(specs))
+	for _, spec := range specs {
+		spec = strings.ToLower(strings.TrimSpace(spec))
+		if spec == "" {
+			continue
+		}
+
+		name, prefix, hasPrefix := strings.Cut(spec, ":")
+		name, prefix = strings.TrimSpace(name), strings.TrimSpace(prefix)
+		if hasPrefix && !isDigits(prefix) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPrefix, spec)
+		}
+		if hasPrefix {
+			spec = name + ":" + prefix
+		} else {
+			spec = name
+		}
+		if _, ok := seen[spec]; ok {
+			continue
+		}
+
+		algorithm, ok := registry[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s (доступны: %s)", ErrUnknownAlgorithm, name, strings.Join(Algorithms(), ", "))
+		}
+		seen[spec] = struct{}{}
+		v.names = append(v.names, spec)
+		v.rules = append(v.rules, rule{prefix: prefix, algorithm: algorithm})
+	}
+
+	if len(v.rules) == 0 {
+		return nil, ErrNoAlgorithms
+	}
+
+	return v, nil
+}
+
+// Default возвращает валидатор по алгоритму Луна, принятый в сервисе по умолчанию.
+func Default() *Validator {
+	return &Validator{
+		names: []string{AlgorithmLuhn},
+		rules: []rule{{algorithm: luhn.IsValid}},
+	}
+}
+
+// Validate проверяет номер заказа. Ошибка оборачивает domain.ErrInvalidOrderNumber
+// и содержит причину отказа: неверную контрольную сумму с именами правил,
+// подходящих по префиксу, или неизвестный префикс с перечнем допустимых.
+func (v *Validator) Validate(number string) error {
+	if number == "" {
+		return fmt.Errorf("%w: %w", domain.ErrInvalidOrderNumber, ErrEmptyNumber)
+	}
+
+	if !isDigits(number) {
+		return fmt.Errorf("%w: %w", domain.ErrInvalidOrderNumber, ErrNonDigit)
+	}
+
+	var matched, prefixes []string
+	for i, rule := range v.rules {
+		if !strings.HasPrefix(number, rule.prefix) {
+			prefixes = append(prefixes, rule.prefix)
+			continue
+		}
+		if rule.algorithm(number) {
+			return nil
+		}
+		matched = append(matched, v.names[i])
+	}
+
+	if len(matched) == 0 {
+		return fmt.Errorf("%w: %w (%s)", domain.ErrInvalidOrderNumber, ErrPrefixMismatch, strings.Join(prefixes, ", "))
+	}
+
+	return fmt.Errorf("%w: %w (%s)", domain.ErrInvalidOrderNumber, ErrChecksumMismatch, strings.Join(matched, ", "))
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
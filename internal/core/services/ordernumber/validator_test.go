@@ -0,0 +1,197 @@
+package ordernumber
+
+import (
+	"testing"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewValidator(t *testing.T) {
+	tests := []struct {
+		name      string
+		names     []string
+		wantNames []string
+		wantErr   error
+	}{
+		{
+			name:      "single algorithm",
+			names:     []string{"luhn"},
+			wantNames: []string{AlgorithmLuhn},
+		},
+		{
+			name:      "names are normalized and deduplicated",
+			names:     []string{" Damm", "luhn", "DAMM", ""},
+			wantNames: []string{AlgorithmDamm, AlgorithmLuhn},
+		},
+		{
+			name:      "algorithm with store prefix",
+			names:     []string{"luhn", " DAMM:77 ", "damm:77"},
+			wantNames: []string{AlgorithmLuhn, "damm:77"},
+		},
+		{
+			name:    "prefix must be digits",
+			names:   []string{"damm:ab"},
+			wantErr: ErrInvalidPrefix,
+		},
+		{
+			name:    "empty prefix",
+			names:   []string{"damm:"},
+			wantErr: ErrInvalidPrefix,
+		},
+		{
+			name:    "unknown algorithm with prefix",
+			names:   []string{"crc32:77"},
+			wantErr: ErrUnknownAlgorithm,
+		},
+		{
+			name:    "unknown algorithm",
+			names:   []string{"luhn", "crc32"},
+			wantErr: ErrUnknownAlgorithm,
+		},
+		{
+			name:    "no algorithms",
+			names:   []string{" "},
+			wantErr: ErrNoAlgorithms,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(tt.names...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, v)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNames, v.names)
+		})
+	}
+}
+
+func TestValidator_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		algorithms []string
+		number     string
+		wantReason error
+	}{
+		{
+			name:       "valid luhn number",
+			algorithms: []string{AlgorithmLuhn},
+			number:     "79927398713",
+		},
+		{
+			name:       "invalid luhn number",
+			algorithms: []string{AlgorithmLuhn},
+			number:     "79927398710",
+			wantReason: ErrChecksumMismatch,
+		},
+		{
+			name:       "valid verhoeff number",
+			algorithms: []string{AlgorithmVerhoeff},
+			number:     "2363",
+		},
+		{
+			name:       "valid damm number",
+			algorithms: []string{AlgorithmDamm},
+			number:     "5724",
+		},
+		{
+			name:       "valid mod97 number",
+			algorithms: []string{AlgorithmMod97},
+			number:     "12345678978",
+		},
+		{
+			name:       "any configured algorithm accepts",
+			algorithms: []string{AlgorithmLuhn, AlgorithmDamm},
+			number:     "5724",
+		},
+		{
+			name:       "prefix rule accepts matching number",
+			algorithms: []string{"damm:57"},
+			number:     "5724",
+		},
+		{
+			name:       "prefix rule rejects other prefix",
+			algorithms: []string{"damm:77"},
+			number:     "5724",
+			wantReason: ErrPrefixMismatch,
+		},
+		{
+			name:       "prefix rule rejects bad checksum",
+			algorithms: []string{"damm:57"},
+			number:     "5725",
+			wantReason: ErrChecksumMismatch,
+		},
+		{
+			name:       "rule without prefix accepts other stores",
+			algorithms: []string{"damm:77", AlgorithmLuhn},
+			number:     "79927398713",
+		},
+		{
+			name:       "empty number",
+			algorithms: []string{AlgorithmLuhn},
+			number:     "",
+			wantReason: ErrEmptyNumber,
+		},
+		{
+			name:       "non digit characters",
+			algorithms: []string{AlgorithmLuhn},
+			number:     "7992-7398713",
+			wantReason: ErrNonDigit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(tt.algorithms...)
+			require.NoError(t, err)
+
+			err = v.Validate(tt.number)
+			if tt.wantReason == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, domain.ErrInvalidOrderNumber)
+			assert.ErrorIs(t, err, tt.wantReason)
+		})
+	}
+}
+
+func TestValidator_ValidateReasonNamesAlgorithms(t *testing.T) {
+	v, err := NewValidator(AlgorithmLuhn, AlgorithmMod97)
+	require.NoError(t, err)
+
+	err = v.Validate("12345")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ErrChecksumMismatch.Error())
+	assert.Contains(t, err.Error(), "luhn, mod97")
+}
+
+func TestValidator_ValidateReasonNamesPrefixes(t *testing.T) {
+	v, err := NewValidator("damm:77", "luhn:42")
+	require.NoError(t, err)
+
+	err = v.Validate("5724")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ErrPrefixMismatch.Error())
+	assert.Contains(t, err.Error(), "77, 42")
+
+	err = v.Validate("7700")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ErrChecksumMismatch.Error())
+	assert.Contains(t, err.Error(), "damm:77")
+	assert.NotContains(t, err.Error(), "luhn:42")
+}
+
+func TestDefault(t *testing.T) {
+	v := Default()
+
+	assert.NoError(t, v.Validate("79927398713"))
+	assert.ErrorIs(t, v.Validate("79927398710"), domain.ErrInvalidOrderNumber)
+}
@@ -0,0 +1,77 @@
+package reconciliation
+
+import (
+	"context"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultInterval задаёт период сверки балансов по умолчанию.
+	DefaultInterval = 1 * time.Hour
+
+	msgJobStopping          = "остановка воркера сверки балансов"
+	msgReconciliationError  = "ошибка сверки балансов"
+	msgReconciliationFailed = "сверка балансов выявила расхождения"
+)
+
+// Job периодически выполняет сверку балансов и, если включено,
+// исправляет найденные расхождения.
+type Job struct {
+	service  ports.ReconciliationService
+	logger   zerolog.Logger
+	interval time.Duration
+	repair   bool
+}
+
+// NewJob создаёт новый воркер сверки балансов.
+func NewJob(service ports.ReconciliationService, logger zerolog.Logger, interval time.Duration, repair bool) *Job {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	return &Job{
+		service:  service,
+		logger:   logger,
+		interval: interval,
+		repair:   repair,
+	}
+}
+
+// Run запускает воркер сверки балансов.
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info().Msg(msgJobStopping)
+			return
+		case <-ticker.C:
+			j.reconcile(ctx)
+		}
+	}
+}
+
+func (j *Job) reconcile(ctx context.Context) {
+	run := j.service.Report
+	if j.repair {
+		run = j.service.Repair
+	}
+
+	report, err := run(ctx)
+	if err != nil {
+		j.logger.Error().Err(err).Msg(msgReconciliationError)
+		return
+	}
+
+	if len(report.Mismatches) > 0 {
+		j.logger.Warn().
+			Int("count", len(report.Mismatches)).
+			Bool("repair", j.repair).
+			Msg(msgReconciliationFailed)
+	}
+}
@@ -0,0 +1,49 @@
+package reconciliation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
+	"github.com/arvaliullin/gophermart/internal/core/services/reconciliation"
+	"go.uber.org/mock/gomock"
+)
+
+func TestJob_Run_Report(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockReconciliationService(ctrl)
+	job := reconciliation.NewJob(service, testLogger(), 10*time.Millisecond, false)
+
+	service.EXPECT().
+		Report(gomock.Any()).
+		Return(&domain.ReconciliationReport{Mismatches: []*domain.BalanceMismatch{{UserID: 1}}}, nil).
+		MinTimes(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	job.Run(ctx)
+}
+
+func TestJob_Run_Repair(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockReconciliationService(ctrl)
+	job := reconciliation.NewJob(service, testLogger(), 10*time.Millisecond, true)
+
+	service.EXPECT().
+		Repair(gomock.Any()).
+		Return(nil, errors.New("database error")).
+		MinTimes(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	job.Run(ctx)
+}
@@ -0,0 +1,85 @@
+package reconciliation
+
+import (
+	"context"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/rs/zerolog"
+)
+
+const (
+	msgBalanceMismatch = "расхождение баланса с заказами и списаниями"
+	msgBalanceRepaired = "расхождение остатка исправлено корректирующей проводкой"
+)
+
+// Service реализует сверку журнала проводок с заказами и списаниями.
+type Service struct {
+	repo   ports.ReconciliationRepository
+	logger zerolog.Logger
+}
+
+// NewService создаёт новый сервис сверки балансов.
+func NewService(repo ports.ReconciliationRepository, logger zerolog.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Report выполняет сверку и возвращает найденные расхождения.
+// Каждое расхождение записывается в лог.
+func (s *Service) Report(ctx context.Context) (*domain.ReconciliationReport, error) {
+	mismatches, err := s.repo.FindMismatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range mismatches {
+		s.logger.Warn().
+			Int64("user_id", m.UserID).
+			Str("expected_current", m.ExpectedCurrent.String()).
+			Str("actual_current", m.ActualCurrent.String()).
+			Str("expected_withdrawn", m.ExpectedWithdrawn.String()).
+			Str("actual_withdrawn", m.ActualWithdrawn.String()).
+			Msg(msgBalanceMismatch)
+	}
+
+	return &domain.ReconciliationReport{
+		CheckedAt:  time.Now(),
+		Mismatches: mismatches,
+	}, nil
+}
+
+// Repair выполняет сверку и исправляет расхождения остатка корректирующими
+// проводками. Записанные проводки возвращаются в отчёте.
+func (s *Service) Repair(ctx context.Context) (*domain.ReconciliationReport, error) {
+	report, err := s.Report(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range report.Mismatches {
+		if m.CurrentDiff().IsZero() {
+			continue
+		}
+
+		entry, err := s.repo.Repair(ctx, m.UserID)
+		if err != nil {
+			return report, err
+		}
+		if entry == nil {
+			continue
+		}
+
+		m.Adjustment = entry
+		s.logger.Info().
+			Int64("user_id", m.UserID).
+			Int64("entry_id", entry.ID).
+			Str("amount", entry.Amount.String()).
+			Msg(msgBalanceRepaired)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,83 @@
+package reconciliation_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
+	"github.com/arvaliullin/gophermart/internal/core/services/reconciliation"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func testLogger() zerolog.Logger {
+	return zerolog.New(os.Stdout).Level(zerolog.Disabled)
+}
+
+func TestService_Report(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockReconciliationRepository(ctrl)
+	service := reconciliation.NewService(repo, testLogger())
+
+	mismatches := []*domain.BalanceMismatch{{UserID: 1, ExpectedCurrent: decimal.NewFromInt(10)}}
+	repo.EXPECT().FindMismatches(gomock.Any()).Return(mismatches, nil)
+
+	report, err := service.Report(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, mismatches, report.Mismatches)
+	assert.False(t, report.CheckedAt.IsZero())
+}
+
+func TestService_Repair(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockReconciliationRepository(ctrl)
+	service := reconciliation.NewService(repo, testLogger())
+
+	drifted := &domain.BalanceMismatch{UserID: 1, ExpectedCurrent: decimal.NewFromInt(10), ActualCurrent: decimal.Zero}
+	withdrawnOnly := &domain.BalanceMismatch{
+		UserID:            2,
+		ExpectedCurrent:   decimal.NewFromInt(5),
+		ActualCurrent:     decimal.NewFromInt(5),
+		ExpectedWithdrawn: decimal.NewFromInt(3),
+		ActualWithdrawn:   decimal.Zero,
+	}
+	entry := &domain.LedgerEntry{ID: 9, UserID: 1, Kind: domain.LedgerEntryReconciliation, Amount: decimal.NewFromInt(10)}
+
+	repo.EXPECT().FindMismatches(gomock.Any()).Return([]*domain.BalanceMismatch{drifted, withdrawnOnly}, nil)
+	repo.EXPECT().Repair(gomock.Any(), int64(1)).Return(entry, nil)
+
+	report, err := service.Repair(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, entry, report.Mismatches[0].Adjustment)
+	assert.Nil(t, report.Mismatches[1].Adjustment)
+}
+
+func TestService_Repair_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockReconciliationRepository(ctrl)
+	service := reconciliation.NewService(repo, testLogger())
+
+	expectedErr := errors.New("database error")
+	repo.EXPECT().FindMismatches(gomock.Any()).Return([]*domain.BalanceMismatch{
+		{UserID: 1, ExpectedCurrent: decimal.NewFromInt(10)},
+	}, nil)
+	repo.EXPECT().Repair(gomock.Any(), int64(1)).Return(nil, expectedErr)
+
+	_, err := service.Repair(context.Background())
+
+	assert.ErrorIs(t, err, expectedErr)
+}
@@ -0,0 +1,83 @@
+package statement
+
+import (
+	"context"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+)
+
+const (
+	// DefaultLimit задаёт размер страницы выписки, если он не указан в запросе.
+	DefaultLimit = 50
+	// MaxLimit задаёт наибольший допустимый размер страницы выписки.
+	MaxLimit = 500
+)
+
+// Service реализует бизнес-логику выписки и выгрузки истории по счёту.
+type Service struct {
+	ledgerRepo  ports.LedgerRepository
+	historyRepo ports.HistoryRepository
+}
+
+// NewService создаёт новый сервис выписки.
+func NewService(ledgerRepo ports.LedgerRepository, historyRepo ports.HistoryRepository) *Service {
+	return &Service{
+		ledgerRepo:  ledgerRepo,
+		historyRepo: historyRepo,
+	}
+}
+
+// GetStatement возвращает страницу выписки по счёту пользователя
+// в хронологическом порядке.
+func (s *Service) GetStatement(ctx context.Context, userID int64, query domain.StatementQuery) (*domain.Statement, error) {
+	if query.Limit == 0 {
+		query.Limit = DefaultLimit
+	}
+
+	if query.Limit < 0 || query.Limit > MaxLimit || query.Cursor < 0 {
+		return nil, domain.ErrInvalidStatementQuery
+	}
+
+	if !isValidPeriod(query.From, query.To) {
+		return nil, domain.ErrInvalidStatementQuery
+	}
+
+	// Лишняя строка показывает, есть ли следующая страница.
+	limit := query.Limit
+	query.Limit++
+
+	entries, err := s.ledgerRepo.GetStatement(ctx, userID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &domain.Statement{Entries: entries}
+	if len(entries) > limit {
+		statement.Entries = entries[:limit]
+		statement.NextCursor = entries[limit-1].ID
+	}
+
+	return statement, nil
+}
+
+// Export выгружает в sink заказы и списания пользователя за период [from, to)
+// одной хронологической лентой, согласованной на один момент времени.
+// Записи передаются по мере чтения из хранилища, без загрузки в память.
+func (s *Service) Export(ctx context.Context, userID int64, from, to *time.Time, sink ports.ExportSink) error {
+	if !isValidPeriod(from, to) {
+		return domain.ErrInvalidStatementQuery
+	}
+
+	return s.historyRepo.StreamByUserID(ctx, userID, from, to, func(record *domain.HistoryRecord) error {
+		if record.Withdrawal != nil {
+			return sink.WriteWithdrawal(record.Withdrawal)
+		}
+		return sink.WriteOrder(record.Order)
+	})
+}
+
+func isValidPeriod(from, to *time.Time) bool {
+	return from == nil || to == nil || from.Before(*to)
+}
@@ -0,0 +1,181 @@
+package statement_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
+	"github.com/arvaliullin/gophermart/internal/core/services/statement"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_GetStatement_DefaultLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ledgerRepo := mocks.NewMockLedgerRepository(ctrl)
+	service := statement.NewService(ledgerRepo, mocks.NewMockHistoryRepository(ctrl))
+
+	entries := []*domain.StatementEntry{{LedgerEntry: domain.LedgerEntry{ID: 1}}}
+	ledgerRepo.EXPECT().
+		GetStatement(gomock.Any(), int64(1), domain.StatementQuery{Limit: statement.DefaultLimit + 1}).
+		Return(entries, nil)
+
+	result, err := service.GetStatement(context.Background(), 1, domain.StatementQuery{})
+
+	require.NoError(t, err)
+	assert.Equal(t, entries, result.Entries)
+	assert.Zero(t, result.NextCursor)
+}
+
+func TestService_GetStatement_NextCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ledgerRepo := mocks.NewMockLedgerRepository(ctrl)
+	service := statement.NewService(ledgerRepo, mocks.NewMockHistoryRepository(ctrl))
+
+	entries := []*domain.StatementEntry{
+		{LedgerEntry: domain.LedgerEntry{ID: 4}},
+		{LedgerEntry: domain.LedgerEntry{ID: 7}},
+		{LedgerEntry: domain.LedgerEntry{ID: 9}},
+	}
+	ledgerRepo.EXPECT().
+		GetStatement(gomock.Any(), int64(1), domain.StatementQuery{Cursor: 3, Limit: 3}).
+		Return(entries, nil)
+
+	result, err := service.GetStatement(context.Background(), 1, domain.StatementQuery{Cursor: 3, Limit: 2})
+
+	require.NoError(t, err)
+	assert.Len(t, result.Entries, 2)
+	assert.Equal(t, int64(7), result.NextCursor)
+}
+
+func TestService_GetStatement_InvalidQuery(t *testing.T) {
+	from := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query domain.StatementQuery
+	}{
+		{"отрицательный лимит", domain.StatementQuery{Limit: -1}},
+		{"лимит больше максимального", domain.StatementQuery{Limit: statement.MaxLimit + 1}},
+		{"отрицательный курсор", domain.StatementQuery{Cursor: -1}},
+		{"начало периода позже конца", domain.StatementQuery{From: &from, To: &to}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := statement.NewService(mocks.NewMockLedgerRepository(ctrl), mocks.NewMockHistoryRepository(ctrl))
+
+			_, err := service.GetStatement(context.Background(), 1, tt.query)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidStatementQuery)
+		})
+	}
+}
+
+func TestService_GetStatement_RepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ledgerRepo := mocks.NewMockLedgerRepository(ctrl)
+	service := statement.NewService(ledgerRepo, mocks.NewMockHistoryRepository(ctrl))
+
+	expectedErr := errors.New("database error")
+	ledgerRepo.EXPECT().GetStatement(gomock.Any(), int64(1), gomock.Any()).Return(nil, expectedErr)
+
+	_, err := service.GetStatement(context.Background(), 1, domain.StatementQuery{})
+
+	assert.ErrorIs(t, err, expectedErr)
+}
+
+func TestService_Export(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	historyRepo := mocks.NewMockHistoryRepository(ctrl)
+	sink := mocks.NewMockExportSink(ctrl)
+	service := statement.NewService(mocks.NewMockLedgerRepository(ctrl), historyRepo)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := &domain.Order{Number: "12345678903"}
+	withdrawal := &domain.Withdrawal{OrderNumber: "79927398713"}
+	second := &domain.Order{Number: "4111111111111111"}
+
+	historyRepo.EXPECT().StreamByUserID(gomock.Any(), int64(1), &from, nil, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int64, _, _ *time.Time, fn func(*domain.HistoryRecord) error) error {
+			for _, record := range []*domain.HistoryRecord{{Order: first}, {Withdrawal: withdrawal}, {Order: second}} {
+				if err := fn(record); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	gomock.InOrder(
+		sink.EXPECT().WriteOrder(first).Return(nil),
+		sink.EXPECT().WriteWithdrawal(withdrawal).Return(nil),
+		sink.EXPECT().WriteOrder(second).Return(nil),
+	)
+
+	err := service.Export(context.Background(), 1, &from, nil, sink)
+
+	require.NoError(t, err)
+}
+
+func TestService_Export_StopsOnRepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	historyRepo := mocks.NewMockHistoryRepository(ctrl)
+	service := statement.NewService(mocks.NewMockLedgerRepository(ctrl), historyRepo)
+
+	expectedErr := errors.New("database error")
+	historyRepo.EXPECT().StreamByUserID(gomock.Any(), int64(1), nil, nil, gomock.Any()).Return(expectedErr)
+
+	err := service.Export(context.Background(), 1, nil, nil, mocks.NewMockExportSink(ctrl))
+
+	assert.ErrorIs(t, err, expectedErr)
+}
+
+func TestService_Export_StopsOnSinkError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	historyRepo := mocks.NewMockHistoryRepository(ctrl)
+	sink := mocks.NewMockExportSink(ctrl)
+	service := statement.NewService(mocks.NewMockLedgerRepository(ctrl), historyRepo)
+
+	expectedErr := errors.New("write error")
+	withdrawal := &domain.Withdrawal{OrderNumber: "79927398713"}
+	historyRepo.EXPECT().StreamByUserID(gomock.Any(), int64(1), nil, nil, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int64, _, _ *time.Time, fn func(*domain.HistoryRecord) error) error {
+			return fn(&domain.HistoryRecord{Withdrawal: withdrawal})
+		})
+	sink.EXPECT().WriteWithdrawal(withdrawal).Return(expectedErr)
+
+	err := service.Export(context.Background(), 1, nil, nil, sink)
+
+	assert.ErrorIs(t, err, expectedErr)
+}
+
+func TestService_Export_InvalidPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := statement.NewService(mocks.NewMockLedgerRepository(ctrl), mocks.NewMockHistoryRepository(ctrl))
+
+	moment := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := service.Export(context.Background(), 1, &moment, &moment, mocks.NewMockExportSink(ctrl))
+
+	assert.ErrorIs(t, err, domain.ErrInvalidStatementQuery)
+}
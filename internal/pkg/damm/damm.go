@@ -0,0 +1,32 @@
+package damm
+
+// quasigroup — полностью антисимметричная квазигруппа порядка 10.
+var quasigroup = [10][10]int{
+	{0, 3, 1, 7, 5, 9, 8, 6, 4, 2},
+	{7, 0, 9, 2, 1, 5, 4, 8, 6, 3},
+	{4, 2, 0, 6, 8, 7, 1, 3, 5, 9},
+	{1, 7, 5, 0, 9, 8, 3, 4, 2, 6},
+	{6, 1, 2, 3, 0, 4, 5, 9, 7, 8},
+	{3, 6, 7, 4, 2, 0, 9, 5, 8, 1},
+	{5, 8, 6, 9, 7, 2, 0, 1, 3, 4},
+	{8, 9, 4, 5, 3, 6, 2, 0, 1, 7},
+	{9, 4, 3, 8, 6, 1, 7, 2, 0, 5},
+	{2, 5, 8, 1, 4, 3, 6, 7, 9, 0},
+}
+
+// IsValid проверяет номер на соответствие алгоритму Дамма.
+func IsValid(number string) bool {
+	if len(number) == 0 {
+		return false
+	}
+
+	var interim int
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return false
+		}
+		interim = quasigroup[interim][r-'0']
+	}
+
+	return interim == 0
+}
@@ -0,0 +1,27 @@
+package damm
+
+import "testing"
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		number string
+		want   bool
+	}{
+		{name: "valid damm number", number: "5724", want: true},
+		{name: "valid damm number 2", number: "1124", want: true},
+		{name: "transposed digits", number: "7524", want: false},
+		{name: "wrong check digit", number: "5723", want: false},
+		{name: "empty string", number: "", want: false},
+		{name: "contains letters", number: "57b4", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsValid(tt.number)
+			if got != tt.want {
+				t.Errorf("IsValid(%q) = %v, want %v", tt.number, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,11 @@
 package luhn
 
+import "fmt"
+
+// ErrInvalidPayload возвращается, если номер для расчёта контрольной цифры
+// пуст или содержит не только цифры.
+var ErrInvalidPayload = fmt.Errorf("номер должен состоять только из цифр")
+
 // IsValid проверяет номер на соответствие алгоритму Луна.
 func IsValid(number string) bool {
 	if len(number) == 0 {
@@ -28,3 +34,44 @@ func IsValid(number string) bool {
 
 	return sum%10 == 0
 }
+
+// CheckDigit возвращает контрольную цифру, которую нужно дописать к payload,
+// чтобы номер прошёл проверку алгоритмом Луна.
+func CheckDigit(payload string) (int, error) {
+	if len(payload) == 0 {
+		return 0, ErrInvalidPayload
+	}
+
+	var sum int
+	// Контрольная цифра займёт последнюю позицию, поэтому удваиваются
+	// цифры на нечётных позициях справа в исходном номере.
+	parity := (len(payload) + 1) % 2
+
+	for i, r := range payload {
+		if r < '0' || r > '9' {
+			return 0, ErrInvalidPayload
+		}
+
+		digit := int(r - '0')
+
+		if i%2 == parity {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+
+		sum += digit
+	}
+
+	return (10 - sum%10) % 10, nil
+}
+
+// Generate дописывает к payload контрольную цифру алгоритма Луна.
+func Generate(payload string) (string, error) {
+	digit, err := CheckDigit(payload)
+	if err != nil {
+		return "", err
+	}
+	return payload + string(rune('0'+digit)), nil
+}
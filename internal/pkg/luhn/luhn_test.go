@@ -64,3 +64,53 @@ func TestIsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "short payload",
+			payload: "7992739871",
+			want:    "79927398713",
+		},
+		{
+			name:    "even length payload",
+			payload: "1234567890",
+			want:    "12345678903",
+		},
+		{
+			name:    "long payload",
+			payload: "456126121234546",
+			want:    "4561261212345467",
+		},
+		{
+			name:    "empty payload",
+			payload: "",
+			wantErr: true,
+		},
+		{
+			name:    "contains letters",
+			payload: "12a4",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Generate(tt.payload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Generate(%q) error = %v, wantErr %v", tt.payload, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Generate(%q) = %q, want %q", tt.payload, got, tt.want)
+			}
+			if !tt.wantErr && !IsValid(got) {
+				t.Errorf("IsValid(%q) = false after Generate", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package mod97
+
+// IsValid проверяет номер по ISO 7064 MOD 97-10: число, записанное всеми
+// цифрами номера вместе с двумя контрольными, должно давать остаток 1 при делении на 97.
+func IsValid(number string) bool {
+	if len(number) < 3 {
+		return false
+	}
+
+	var remainder int
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return false
+		}
+		remainder = (remainder*10 + int(r-'0')) % 97
+	}
+
+	return remainder == 1
+}
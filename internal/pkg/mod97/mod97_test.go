@@ -0,0 +1,27 @@
+package mod97
+
+import "testing"
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		number string
+		want   bool
+	}{
+		{name: "valid mod97 number", number: "12345678978", want: true},
+		{name: "valid mod97 number 2", number: "100008", want: true},
+		{name: "wrong check digits", number: "12345678979", want: false},
+		{name: "too short", number: "1", want: false},
+		{name: "empty string", number: "", want: false},
+		{name: "contains letters", number: "1234a678978", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsValid(tt.number)
+			if got != tt.want {
+				t.Errorf("IsValid(%q) = %v, want %v", tt.number, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter ограничивает частоту операций алгоритмом token bucket.
+// Один Limiter безопасно разделяется между горутинами.
+type Limiter struct {
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	now         func() time.Time
+}
+
+// NewLimiter создаёт ограничитель на rate операций в секунду с запасом burst.
+// Неположительный rate снимает ограничение частоты, оставляя только паузы.
+func NewLimiter(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	l := &Limiter{
+		rate:  rate,
+		burst: float64(burst),
+		now:   time.Now,
+	}
+	l.tokens = l.burst
+	l.last = l.now()
+
+	return l
+}
+
+// Wait блокируется до получения разрешения на операцию или отмены ctx.
+func (l *Limiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Allow забирает разрешение на операцию без ожидания. Если разрешения
+// сейчас нет, возвращает false и время, через которое стоит повторить попытку.
+func (l *Limiter) Allow() (bool, time.Duration) {
+	delay := l.reserve()
+	return delay <= 0, delay
+}
+
+// Pause приостанавливает выдачу разрешений на d, например после ответа
+// 429 Too Many Requests. Более ранняя пауза не сокращает уже назначенную.
+func (l *Limiter) Pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until := l.now().Add(d)
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// SetRate меняет частоту выдачи разрешений на ходу, например по лимиту,
+// который сообщил внешний сервис. Накопленные токены сохраняются.
+func (l *Limiter) SetRate(rate float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(l.now())
+	l.rate = rate
+}
+
+// Rate возвращает текущую частоту выдачи разрешений в секунду.
+func (l *Limiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.rate
+}
+
+// PausedFor возвращает оставшуюся длительность паузы.
+func (l *Limiter) PausedFor() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if remaining := l.pausedUntil.Sub(l.now()); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// reserve забирает токен и возвращает 0 либо время, через которое
+// стоит повторить попытку.
+func (l *Limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.refill(now)
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// refill начисляет токены, накопленные с последнего обращения.
+func (l *Limiter) refill(now time.Time) {
+	if l.rate > 0 {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+}
@@ -0,0 +1,184 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestLimiter(rate float64, burst int) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := NewLimiter(rate, burst)
+	l.now = clock.Now
+	l.last = clock.now
+	return l, clock
+}
+
+func TestLimiter_Reserve(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    float64
+		burst   int
+		elapsed time.Duration
+		calls   int
+		want    time.Duration
+	}{
+		{
+			name:  "burst is available immediately",
+			rate:  1,
+			burst: 3,
+			calls: 3,
+			want:  0,
+		},
+		{
+			name:  "waits for next token after burst",
+			rate:  2,
+			burst: 2,
+			calls: 3,
+			want:  500 * time.Millisecond,
+		},
+		{
+			name:    "tokens are refilled over time",
+			rate:    1,
+			burst:   1,
+			elapsed: time.Second,
+			calls:   2,
+			want:    0,
+		},
+		{
+			name:  "unlimited rate",
+			rate:  0,
+			burst: 1,
+			calls: 100,
+			want:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, clock := newTestLimiter(tt.rate, tt.burst)
+
+			var got time.Duration
+			for i := 0; i < tt.calls; i++ {
+				if i == tt.calls-1 {
+					clock.now = clock.now.Add(tt.elapsed)
+				}
+				got = l.reserve()
+			}
+
+			if got != tt.want {
+				t.Errorf("reserve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimiter_Pause(t *testing.T) {
+	l, clock := newTestLimiter(0, 1)
+
+	l.Pause(time.Minute)
+	l.Pause(time.Second)
+
+	if got := l.PausedFor(); got != time.Minute {
+		t.Fatalf("PausedFor() = %v, want %v", got, time.Minute)
+	}
+	if got := l.reserve(); got != time.Minute {
+		t.Fatalf("reserve() = %v, want %v", got, time.Minute)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+
+	if got := l.PausedFor(); got != 0 {
+		t.Errorf("PausedFor() = %v, want 0", got)
+	}
+	if got := l.reserve(); got != 0 {
+		t.Errorf("reserve() = %v, want 0", got)
+	}
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	l, clock := newTestLimiter(1, 1)
+
+	if ok, delay := l.Allow(); !ok || delay != 0 {
+		t.Fatalf("Allow() = %v, %v, want true, 0", ok, delay)
+	}
+	if ok, delay := l.Allow(); ok || delay != time.Second {
+		t.Fatalf("Allow() = %v, %v, want false, %v", ok, delay, time.Second)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+	l.Pause(time.Minute)
+
+	if ok, delay := l.Allow(); ok || delay != time.Minute {
+		t.Fatalf("Allow() = %v, %v, want false, %v", ok, delay, time.Minute)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+
+	if ok, _ := l.Allow(); !ok {
+		t.Error("Allow() = false after pause, want true")
+	}
+}
+
+func TestLimiter_SetRate(t *testing.T) {
+	l, clock := newTestLimiter(0, 1)
+
+	if got := l.reserve(); got != 0 {
+		t.Fatalf("reserve() = %v, want 0", got)
+	}
+
+	l.SetRate(0.5)
+
+	if got := l.Rate(); got != 0.5 {
+		t.Fatalf("Rate() = %v, want 0.5", got)
+	}
+	if got := l.reserve(); got != 0 {
+		t.Fatalf("reserve() = %v, want 0", got)
+	}
+	if got := l.reserve(); got != 2*time.Second {
+		t.Fatalf("reserve() = %v, want %v", got, 2*time.Second)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+	l.SetRate(1)
+
+	if got := l.reserve(); got != 500*time.Millisecond {
+		t.Errorf("reserve() = %v, want %v", got, 500*time.Millisecond)
+	}
+}
+
+func TestLimiter_WaitCanceled(t *testing.T) {
+	l := NewLimiter(0, 1)
+	l.Pause(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	l := NewLimiter(100, 1)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("three waits at 100/s took %v, want at least 15ms", elapsed)
+	}
+}
@@ -0,0 +1,47 @@
+package verhoeff
+
+// multiplication — таблица умножения группы диэдра D5.
+var multiplication = [10][10]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+	{2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+	{3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+	{4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+	{5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+	{6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+	{7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+	{8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+	{9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+}
+
+// permutation — перестановки цифр в зависимости от позиции справа.
+var permutation = [8][10]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+	{5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+	{8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+	{9, 4, 5, 3, 1, 2, 6, 8, 7, 0},
+	{4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+	{2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+	{7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+}
+
+// IsValid проверяет номер на соответствие алгоритму Верхуффа.
+func IsValid(number string) bool {
+	if len(number) == 0 {
+		return false
+	}
+
+	var check int
+	for i := len(number) - 1; i >= 0; i-- {
+		r := number[i]
+		if r < '0' || r > '9' {
+			return false
+		}
+
+		position := len(number) - 1 - i
+		check = multiplication[check][permutation[position%8][r-'0']]
+	}
+
+	return check == 0
+}
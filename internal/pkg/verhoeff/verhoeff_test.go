@@ -0,0 +1,27 @@
+package verhoeff
+
+import "testing"
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		number string
+		want   bool
+	}{
+		{name: "valid verhoeff number", number: "2363", want: true},
+		{name: "valid verhoeff number 2", number: "123451", want: true},
+		{name: "transposed digits", number: "3263", want: false},
+		{name: "wrong check digit", number: "2364", want: false},
+		{name: "empty string", number: "", want: false},
+		{name: "contains letters", number: "23a3", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsValid(tt.number)
+			if got != tt.want {
+				t.Errorf("IsValid(%q) = %v, want %v", tt.number, got, tt.want)
+			}
+		})
+	}
+}
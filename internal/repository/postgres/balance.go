@@ -3,85 +3,250 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/shopspring/decimal"
 )
 
 // BalanceRepository реализует интерфейс ports.BalanceRepository для PostgreSQL.
 type BalanceRepository struct {
-	pool *pgxpool.Pool
+	db dbtx
 }
 
 // NewBalanceRepository создаёт новый репозиторий баланса.
 func NewBalanceRepository(pool *pgxpool.Pool) *BalanceRepository {
-	return &BalanceRepository{pool: pool}
+	return &BalanceRepository{db: pool}
 }
 
-// GetByUserID возвращает баланс пользователя.
+// GetByUserID возвращает баланс пользователя, рассчитанный по журналу проводок.
+// Возвраты уменьшают сумму списанных баллов, активные блокировки уменьшают
+// доступный остаток.
 func (r *BalanceRepository) GetByUserID(ctx context.Context, userID int64) (*domain.Balance, error) {
 	query := `
-		SELECT user_id, current, withdrawn
-		FROM balances
-		WHERE user_id = $1
+		WITH ledger AS (
+			SELECT
+				COALESCE(SUM(amount), 0) AS total,
+				COALESCE(-SUM(amount) FILTER (WHERE kind IN ($2, $3)), 0) AS withdrawn
+			FROM ledger_entries
+			WHERE user_id = $1
+		), held AS (
+			SELECT COALESCE(SUM(amount), 0) AS total
+			FROM point_holds
+			WHERE user_id = $1 AND status = $4 AND expires_at > NOW()
+		)
+		SELECT ledger.total - held.total, ledger.withdrawn, held.total,
+			COALESCE((SELECT tier FROM user_tiers WHERE user_id = $1), $5)
+		FROM ledger, held
 	`
 
-	var balance domain.Balance
-	err := r.pool.QueryRow(ctx, query, userID).Scan(
-		&balance.UserID,
+	balance := domain.Balance{UserID: userID}
+	err := r.db.QueryRow(ctx, query,
+		userID, domain.LedgerEntryWithdrawal, domain.LedgerEntryRefund, domain.HoldStatusActive, domain.DefaultTier,
+	).Scan(
 		&balance.Current,
 		&balance.Withdrawn,
+		&balance.OnHold,
+		&balance.Tier,
 	)
-
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return &domain.Balance{UserID: userID}, nil
-		}
 		return nil, err
 	}
 
 	return &balance, nil
 }
 
-// CreateForUser создаёт запись баланса для пользователя.
+// CreateForUser создаёт счёт баллов для пользователя.
 func (r *BalanceRepository) CreateForUser(ctx context.Context, userID int64) error {
 	query := `
-		INSERT INTO balances (user_id, current, withdrawn)
-		VALUES ($1, 0, 0)
+		INSERT INTO balances (user_id)
+		VALUES ($1)
 		ON CONFLICT (user_id) DO NOTHING
 	`
 
-	_, err := r.pool.Exec(ctx, query, userID)
+	_, err := r.db.Exec(ctx, query, userID)
+	return err
+}
+
+// AddAccrual записывает в журнал проводку начисления баллов пользователю за заказ
+// и заводит под неё партию баллов со сроком действия accrual.ExpiresAt.
+// Положительный accrual.Bonus записывается отдельной проводкой BONUS со своей партией.
+// Если прежнее начисление по заказу отозвано перепроверкой, баллы начисляются
+// заново проводкой ACCRUAL_CORRECTION, как в SettleAccrual.
+// Повторное начисление за тот же заказ отклоняется с ошибкой domain.ErrAccrualAlreadyApplied.
+func (r *BalanceRepository) AddAccrual(ctx context.Context, accrual *domain.Accrual) error {
+	entry, err := r.settleAccrual(ctx, accrual, "начисление после перепроверки заказа", true)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return domain.ErrAccrualAlreadyApplied
+	}
+	return nil
+}
+
+// SettleAccrual доводит сумму начислений пользователю за заказ до accrual.Amount.
+//
+// Первое начисление записывается проводкой ACCRUAL вместе с надбавкой BONUS.
+// Последующие изменения записываются проводкой ACCRUAL_CORRECTION на разницу
+// с причиной description: доначисление заводит партию баллов, отзыв расходует
+// партии в порядке FIFO и может увести баланс в минус, если баллы уже потрачены.
+// Вместе с начислением надбавка за уровень доводится до accrual.Bonus
+// отдельной проводкой BONUS_CORRECTION, поэтому при отзыве начисления
+// отзывается и надбавка.
+// Если сумма начислений не меняется, возвращается nil.
+func (r *BalanceRepository) SettleAccrual(ctx context.Context, accrual *domain.Accrual, description string) (*domain.LedgerEntry, error) {
+	return r.settleAccrual(ctx, accrual, description, false)
+}
+
+// settleAccrual реализует SettleAccrual. При revokedOnly действующее
+// начисление по заказу не изменяется: записывается только первое начисление
+// или повторное после полного отзыва.
+func (r *BalanceRepository) settleAccrual(ctx context.Context, accrual *domain.Accrual, description string, revokedOnly bool) (*domain.LedgerEntry, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Upsert блокирует строку счёта, чтобы параллельные начисления по заказу
+	// считали разницу от одной и той же суммы.
+	_, err = tx.Exec(ctx, `
+		INSERT INTO balances (user_id)
+		VALUES ($1)
+		ON CONFLICT (user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+	`, accrual.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		accrued  bool
+		credited decimal.Decimal
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE kind = $3) > 0, COALESCE(SUM(amount), 0)
+		FROM ledger_entries
+		WHERE user_id = $1 AND order_number = $2 AND kind IN ($3, $4)
+	`, accrual.UserID, accrual.OrderNumber, domain.LedgerEntryAccrual, domain.LedgerEntryAccrualCorrection,
+	).Scan(&accrued, &credited)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry *domain.LedgerEntry
+	switch {
+	case !accrued && accrual.Amount.IsPositive():
+		entry, err = addOrderEntry(ctx, tx, accrual, domain.LedgerEntryAccrual, accrual.Amount, "")
+		if err == nil && accrual.Bonus.IsPositive() {
+			_, err = addOrderEntry(ctx, tx, accrual, domain.LedgerEntryBonus, accrual.Bonus, "надбавка за уровень лояльности")
+		}
+	case accrued && revokedOnly && !credited.IsZero():
+		return nil, nil
+	case accrued && !accrual.Amount.Equal(credited):
+		entry, err = addOrderEntry(ctx, tx, accrual, domain.LedgerEntryAccrualCorrection, accrual.Amount.Sub(credited), description)
+		if err == nil {
+			err = settleBonus(ctx, tx, accrual, description)
+		}
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return nil, domain.ErrAccrualAlreadyApplied
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// settleBonus доводит сумму надбавок за уровень по заказу до accrual.Bonus
+// проводкой BONUS_CORRECTION на разницу.
+func settleBonus(ctx context.Context, tx pgx.Tx, accrual *domain.Accrual, description string) error {
+	var credited decimal.Decimal
+	err := tx.QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM ledger_entries
+		WHERE user_id = $1 AND order_number = $2 AND kind IN ($3, $4)
+	`, accrual.UserID, accrual.OrderNumber, domain.LedgerEntryBonus, domain.LedgerEntryBonusCorrection,
+	).Scan(&credited)
+	if err != nil {
+		return err
+	}
+
+	if accrual.Bonus.Equal(credited) {
+		return nil
+	}
+
+	description = fmt.Sprintf("пересчёт надбавки за уровень лояльности: %s", description)
+	_, err = addOrderEntry(ctx, tx, accrual, domain.LedgerEntryBonusCorrection, accrual.Bonus.Sub(credited), description)
 	return err
 }
 
-// AddAccrual добавляет начисление к балансу пользователя.
-func (r *BalanceRepository) AddAccrual(ctx context.Context, userID int64, amount decimal.Decimal) error {
+// addOrderEntry записывает проводку по заказу и обновляет партии баллов:
+// поступление заводит партию со сроком действия accrual.ExpiresAt,
+// отрицательная сумма расходует партии пользователя.
+func addOrderEntry(ctx context.Context, tx pgx.Tx, accrual *domain.Accrual, kind domain.LedgerEntryKind, amount decimal.Decimal, description string) (*domain.LedgerEntry, error) {
 	query := `
-		INSERT INTO balances (user_id, current, withdrawn)
-		VALUES ($1, $2, 0)
-		ON CONFLICT (user_id) DO UPDATE
-		SET current = balances.current + EXCLUDED.current
+		INSERT INTO ledger_entries (user_id, kind, amount, order_number, description)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, user_id, kind, amount, order_number, description, created_at
 	`
 
-	_, err := r.pool.Exec(ctx, query, userID, amount)
-	return err
+	var entry domain.LedgerEntry
+	err := tx.QueryRow(ctx, query, accrual.UserID, kind, amount, accrual.OrderNumber, description).Scan(
+		&entry.ID,
+		&entry.UserID,
+		&entry.Kind,
+		&entry.Amount,
+		&entry.OrderNumber,
+		&entry.Description,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if amount.IsPositive() {
+		err = creditLot(ctx, tx, accrual.UserID, entry.ID, amount, accrual.ExpiresAt)
+	} else {
+		_, err = consumeLots(ctx, tx, accrual.UserID, amount.Neg())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
 }
 
 // Withdraw выполняет списание средств с баланса пользователя.
-func (r *BalanceRepository) Withdraw(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal) error {
-	tx, err := r.pool.Begin(ctx)
+//
+// Строка счёта в таблице balances блокируется на время транзакции, чтобы
+// параллельные списания не могли одновременно израсходовать один остаток
+// или в сумме превысить лимиты. Лимиты limits действуют, если пользователю
+// не назначены индивидуальные; суточный и месячный лимиты считаются по
+// списаниям и действующим блокировкам за последние 24 часа и за последний месяц.
+func (r *BalanceRepository) Withdraw(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal, limits domain.WithdrawalLimits) error {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback(ctx)
 
-	var currentBalance decimal.Decimal
 	err = tx.QueryRow(ctx, `
-		SELECT current FROM balances WHERE user_id = $1 FOR UPDATE
-	`, userID).Scan(&currentBalance)
+		SELECT user_id FROM balances WHERE user_id = $1 FOR UPDATE
+	`, userID).Scan(&userID)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -90,26 +255,668 @@ func (r *BalanceRepository) Withdraw(ctx context.Context, userID int64, orderNum
 		return err
 	}
 
+	if err := checkWithdrawalLimits(ctx, tx, userID, amount, limits, 0); err != nil {
+		return err
+	}
+
+	currentBalance, err := availableBalance(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+
 	if currentBalance.LessThan(amount) {
 		return domain.ErrInsufficientBalance
 	}
 
 	_, err = tx.Exec(ctx, `
-		UPDATE balances
-		SET current = current - $1, withdrawn = withdrawn + $1
-		WHERE user_id = $2
-	`, amount, userID)
+		INSERT INTO withdrawals (user_id, order_number, sum)
+		VALUES ($1, $2, $3)
+	`, userID, orderNumber, amount)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO ledger_entries (user_id, kind, amount, order_number)
+		VALUES ($1, $2, $3, $4)
+	`, userID, domain.LedgerEntryWithdrawal, amount.Neg(), orderNumber)
 	if err != nil {
 		return err
 	}
 
+	if _, err := consumeLots(ctx, tx, userID, amount); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Refund возвращает баллы по списанию на счёт пользователя, оформившего списание.
+//
+// Если amount равен nil, возвращается вся ещё не возвращённая часть списания.
+// Строка списания блокируется на время транзакции, чтобы параллельные возвраты
+// не могли в сумме превысить списанное.
+func (r *BalanceRepository) Refund(ctx context.Context, withdrawalID int64, amount *decimal.Decimal) (*domain.Refund, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var withdrawal domain.Withdrawal
+	err = tx.QueryRow(ctx, `
+		SELECT id, user_id, order_number, sum FROM withdrawals
+		WHERE id = $1
+		FOR UPDATE
+	`, withdrawalID).Scan(&withdrawal.ID, &withdrawal.UserID, &withdrawal.OrderNumber, &withdrawal.Sum)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrWithdrawalNotFound
+		}
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(SUM(sum), 0) FROM withdrawal_refunds WHERE withdrawal_id = $1
+	`, withdrawalID).Scan(&withdrawal.Refunded)
+	if err != nil {
+		return nil, err
+	}
+
+	refundable := withdrawal.Refundable()
+	sum := refundable
+	if amount != nil {
+		sum = *amount
+	}
+
+	if !sum.IsPositive() || sum.GreaterThan(refundable) {
+		return nil, domain.ErrRefundExceedsWithdrawal
+	}
+
+	refund := domain.Refund{
+		WithdrawalID: withdrawalID,
+		UserID:       withdrawal.UserID,
+		Sum:          sum,
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO withdrawal_refunds (withdrawal_id, user_id, sum)
+		VALUES ($1, $2, $3)
+		RETURNING id, processed_at
+	`, withdrawalID, withdrawal.UserID, sum).Scan(&refund.ID, &refund.ProcessedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	var entryID int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO ledger_entries (user_id, kind, amount, order_number, description)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, withdrawal.UserID, domain.LedgerEntryRefund, sum, withdrawal.OrderNumber, fmt.Sprintf("возврат по списанию %d", withdrawalID)).Scan(&entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Возвращённые баллы образуют бессрочную партию: исходные партии
+	// к этому моменту могли уже сгореть.
+	if err := creditLot(ctx, tx, withdrawal.UserID, entryID, sum, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &refund, nil
+}
+
+// Hold блокирует баллы пользователя под оплату заказа до момента expiresAt.
+//
+// Как и при списании, строка счёта блокируется на время транзакции, чтобы
+// параллельные операции не могли одновременно израсходовать один остаток.
+// Блокировка проверяется на лимиты limits как списание, а действующие
+// блокировки учитываются в суточном и месячном лимитах, поэтому лимит
+// нельзя обойти, заблокировав баллы под несколько заказов сразу.
+func (r *BalanceRepository) Hold(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal, expiresAt time.Time, limits domain.WithdrawalLimits) (*domain.Hold, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		SELECT user_id FROM balances WHERE user_id = $1 FOR UPDATE
+	`, userID).Scan(&userID)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrInsufficientBalance
+		}
+		return nil, err
+	}
+
+	if err := checkWithdrawalLimits(ctx, tx, userID, amount, limits, 0); err != nil {
+		return nil, err
+	}
+
+	available, err := availableBalance(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if available.LessThan(amount) {
+		return nil, domain.ErrInsufficientBalance
+	}
+
+	hold := domain.Hold{
+		UserID:      userID,
+		OrderNumber: orderNumber,
+		Amount:      amount,
+		Status:      domain.HoldStatusActive,
+		ExpiresAt:   expiresAt,
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO point_holds (user_id, order_number, amount, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, userID, orderNumber, amount, domain.HoldStatusActive, expiresAt).Scan(&hold.ID, &hold.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+// CaptureHold подтверждает блокировку и превращает её в списание баллов
+// пользователя, за которым числится блокировка. Лимиты limits проверяются
+// уже при создании блокировки; повторная проверка при подтверждении
+// страхует от изменения лимитов за время её действия.
+func (r *BalanceRepository) CaptureHold(ctx context.Context, holdID int64, limits domain.WithdrawalLimits) (*domain.Withdrawal, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	hold, err := lockActiveHold(ctx, tx, holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Блокируем баланс, чтобы параллельные списания не обошли лимиты.
 	_, err = tx.Exec(ctx, `
+		SELECT user_id FROM balances WHERE user_id = $1 FOR UPDATE
+	`, hold.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkWithdrawalLimits(ctx, tx, hold.UserID, hold.Amount, limits, hold.ID); err != nil {
+		return nil, err
+	}
+
+	// Пока блокировка действовала, часть баллов могла сгореть. Доступный
+	// остаток уже учитывает эту блокировку, поэтому он не должен быть отрицательным.
+	available, err := availableBalance(ctx, tx, hold.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if available.IsNegative() {
+		return nil, domain.ErrInsufficientBalance
+	}
+
+	withdrawal := domain.Withdrawal{
+		UserID:      hold.UserID,
+		OrderNumber: hold.OrderNumber,
+		Sum:         hold.Amount,
+	}
+	err = tx.QueryRow(ctx, `
 		INSERT INTO withdrawals (user_id, order_number, sum)
 		VALUES ($1, $2, $3)
-	`, userID, orderNumber, amount)
+		RETURNING id, processed_at
+	`, hold.UserID, hold.OrderNumber, hold.Amount).Scan(&withdrawal.ID, &withdrawal.ProcessedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO ledger_entries (user_id, kind, amount, order_number)
+		VALUES ($1, $2, $3, $4)
+	`, hold.UserID, domain.LedgerEntryWithdrawal, hold.Amount.Neg(), hold.OrderNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := consumeLots(ctx, tx, hold.UserID, hold.Amount); err != nil {
+		return nil, err
+	}
+
+	if err := resolveHold(ctx, tx, holdID, domain.HoldStatusCaptured); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &withdrawal, nil
+}
+
+// ReleaseHold отменяет блокировку и возвращает баллы в доступный остаток.
+func (r *BalanceRepository) ReleaseHold(ctx context.Context, holdID int64) (*domain.Hold, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	hold, err := lockActiveHold(ctx, tx, holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resolveHold(ctx, tx, holdID, domain.HoldStatusReleased); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	hold.Status = domain.HoldStatusReleased
+	return hold, nil
+}
+
+// ExpireHolds переводит в статус EXPIRED все активные блокировки с истёкшим сроком
+// и возвращает их количество.
+func (r *BalanceRepository) ExpireHolds(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE point_holds
+		SET status = $1, resolved_at = NOW()
+		WHERE status = $2 AND expires_at <= NOW()
+	`
+
+	tag, err := r.db.Exec(ctx, query, domain.HoldStatusExpired, domain.HoldStatusActive)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// Transfer переводит баллы от одного пользователя другому.
+//
+// Строка счёта отправителя блокируется на время транзакции, поэтому проверка
+// остатка и суточного лимита не может быть обойдена параллельными переводами.
+// Суточный лимит считается по переводам за последние 24 часа; нулевой dailyLimit
+// означает отсутствие лимита. Получатель наследует сроки сгорания переданных партий.
+func (r *BalanceRepository) Transfer(ctx context.Context, fromUserID, toUserID int64, amount, dailyLimit decimal.Decimal) (*domain.Transfer, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		SELECT user_id FROM balances WHERE user_id = $1 FOR UPDATE
+	`, fromUserID).Scan(&fromUserID)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrInsufficientBalance
+		}
+		return nil, err
+	}
+
+	if dailyLimit.IsPositive() {
+		var transferred decimal.Decimal
+		err = tx.QueryRow(ctx, `
+			SELECT COALESCE(SUM(amount), 0)
+			FROM transfers
+			WHERE from_user_id = $1 AND created_at > NOW() - INTERVAL '1 day'
+		`, fromUserID).Scan(&transferred)
+		if err != nil {
+			return nil, err
+		}
+
+		if transferred.Add(amount).GreaterThan(dailyLimit) {
+			return nil, domain.ErrTransferLimitExceeded
+		}
+	}
+
+	available, err := availableBalance(ctx, tx, fromUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if available.LessThan(amount) {
+		return nil, domain.ErrInsufficientBalance
+	}
+
+	transfer := domain.Transfer{
+		FromUserID: fromUserID,
+		ToUserID:   toUserID,
+		Amount:     amount,
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO transfers (from_user_id, to_user_id, amount)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, fromUserID, toUserID, amount).Scan(&transfer.ID, &transfer.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO balances (user_id)
+		VALUES ($1)
+		ON CONFLICT (user_id) DO NOTHING
+	`, toUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO ledger_entries (user_id, kind, amount, description)
+		SELECT $1, $2, $3, 'перевод баллов пользователю ' || login
+		FROM users WHERE id = $4
+	`, fromUserID, domain.LedgerEntryTransferOut, amount.Neg(), toUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var entryID int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO ledger_entries (user_id, kind, amount, description)
+		SELECT $1, $2, $3, 'перевод баллов от пользователя ' || login
+		FROM users WHERE id = $4
+		RETURNING id
+	`, toUserID, domain.LedgerEntryTransferIn, amount, fromUserID).Scan(&entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	consumed, err := consumeLots(ctx, tx, fromUserID, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	left := amount
+	for _, lot := range consumed {
+		if err := creditLot(ctx, tx, toUserID, entryID, lot.Amount, lot.ExpiresAt); err != nil {
+			return nil, err
+		}
+		left = left.Sub(lot.Amount)
+	}
+
+	// Остаток, не покрытый партиями отправителя, зачисляется бессрочной партией.
+	if left.IsPositive() {
+		if err := creditLot(ctx, tx, toUserID, entryID, left, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+// GetExpiring возвращает сумму баллов пользователя, которые сгорят до момента before.
+func (r *BalanceRepository) GetExpiring(ctx context.Context, userID int64, before time.Time) (decimal.Decimal, error) {
+	query := `
+		SELECT COALESCE(SUM(remaining), 0)
+		FROM point_lots
+		WHERE user_id = $1 AND remaining > 0 AND expires_at <= $2
+	`
+
+	var expiring decimal.Decimal
+	err := r.db.QueryRow(ctx, query, userID, before).Scan(&expiring)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return expiring, nil
+}
+
+// ExpirePoints списывает остатки всех партий с истёкшим сроком действия
+// проводками EXPIRATION и возвращает количество сгоревших партий.
+//
+// Партии обрабатываются отдельной транзакцией на каждого пользователя
+// с блокировкой строки счёта, как при списании.
+func (r *BalanceRepository) ExpirePoints(ctx context.Context) (int64, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT DISTINCT user_id
+		FROM point_lots
+		WHERE remaining > 0 AND expires_at <= NOW()
+	`)
+	if err != nil {
+		return 0, err
+	}
+
+	var userIDs []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var expired int64
+	for _, userID := range userIDs {
+		n, err := r.expireUserPoints(ctx, userID)
+		if err != nil {
+			return expired, err
+		}
+		expired += n
+	}
+
+	return expired, nil
+}
+
+func (r *BalanceRepository) expireUserPoints(ctx context.Context, userID int64) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		SELECT user_id FROM balances WHERE user_id = $1 FOR UPDATE
+	`, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		WITH due AS (
+			SELECT id, remaining
+			FROM point_lots
+			WHERE user_id = $1 AND remaining > 0 AND expires_at <= NOW()
+			FOR UPDATE
+		), expired AS (
+			UPDATE point_lots l
+			SET remaining = 0, expired_at = NOW()
+			FROM due
+			WHERE l.id = due.id
+			RETURNING due.id, due.remaining
+		)
+		INSERT INTO ledger_entries (user_id, kind, amount, description)
+		SELECT $1, $2, -remaining, 'сгорание баллов партии ' || id
+		FROM expired
+	`, userID, domain.LedgerEntryExpiration)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// creditLot заводит партию баллов под проводку поступления.
+func creditLot(ctx context.Context, tx pgx.Tx, userID, entryID int64, amount decimal.Decimal, expiresAt *time.Time) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO point_lots (user_id, ledger_entry_id, amount, remaining, expires_at)
+		VALUES ($1, $2, $3, $3, $4)
+	`, userID, entryID, amount, expiresAt)
+	return err
+}
+
+// consumeLots расходует партии баллов пользователя в порядке FIFO: первыми
+// расходуются партии с ближайшим сроком сгорания, бессрочные — последними.
+// Возвращает израсходованные части партий вместе с их сроками действия.
+func consumeLots(ctx context.Context, tx pgx.Tx, userID int64, amount decimal.Decimal) ([]domain.PointLot, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT id, remaining, expires_at
+		FROM point_lots
+		WHERE user_id = $1 AND remaining > 0
+		ORDER BY expires_at ASC NULLS LAST, id ASC
+		FOR UPDATE
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var consumed []domain.PointLot
+	left := amount
+	for rows.Next() && left.IsPositive() {
+		lot := domain.PointLot{UserID: userID}
+		if err := rows.Scan(&lot.ID, &lot.Remaining, &lot.ExpiresAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		lot.Amount = decimal.Min(lot.Remaining, left)
+		lot.Remaining = lot.Remaining.Sub(lot.Amount)
+		left = left.Sub(lot.Amount)
+		consumed = append(consumed, lot)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, lot := range consumed {
+		_, err := tx.Exec(ctx, `
+			UPDATE point_lots SET remaining = $1 WHERE id = $2
+		`, lot.Remaining, lot.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return consumed, nil
+}
+
+// availableBalance возвращает доступный остаток пользователя: сумму проводок
+// за вычетом действующих блокировок.
+func availableBalance(ctx context.Context, tx pgx.Tx, userID int64) (decimal.Decimal, error) {
+	var available decimal.Decimal
+	err := tx.QueryRow(ctx, `
+		SELECT
+			COALESCE((SELECT SUM(amount) FROM ledger_entries WHERE user_id = $1), 0)
+			- COALESCE((
+				SELECT SUM(amount) FROM point_holds
+				WHERE user_id = $1 AND status = $2 AND expires_at > NOW()
+			), 0)
+	`, userID, domain.HoldStatusActive).Scan(&available)
+	return available, err
+}
+
+// lockActiveHold блокирует строку блокировки баллов пользователя до конца транзакции.
+// Завершённые и истёкшие блокировки отклоняются с ошибкой domain.ErrHoldNotActive.
+func lockActiveHold(ctx context.Context, tx pgx.Tx, holdID int64) (*domain.Hold, error) {
+	var (
+		hold domain.Hold
+		live bool
+	)
+	err := tx.QueryRow(ctx, `
+		SELECT id, user_id, order_number, amount, status, created_at, expires_at, expires_at > NOW()
+		FROM point_holds
+		WHERE id = $1
+		FOR UPDATE
+	`, holdID).Scan(
+		&hold.ID,
+		&hold.UserID,
+		&hold.OrderNumber,
+		&hold.Amount,
+		&hold.Status,
+		&hold.CreatedAt,
+		&hold.ExpiresAt,
+		&live,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrHoldNotFound
+		}
+		return nil, err
+	}
+
+	if hold.Status != domain.HoldStatusActive || !live {
+		return nil, domain.ErrHoldNotActive
+	}
+
+	return &hold, nil
+}
+
+func resolveHold(ctx context.Context, tx pgx.Tx, holdID int64, status domain.HoldStatus) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE point_holds SET status = $1, resolved_at = NOW() WHERE id = $2
+	`, status, holdID)
+	return err
+}
+
+// checkWithdrawalLimits проверяет, что списание amount укладывается в лимиты
+// пользователя. В суточный и месячный лимиты засчитываются выполненные
+// списания и действующие блокировки, кроме подтверждаемой блокировки holdID.
+func checkWithdrawalLimits(ctx context.Context, tx pgx.Tx, userID int64, amount decimal.Decimal, defaults domain.WithdrawalLimits, holdID int64) error {
+	override, err := getWithdrawalLimitsOverride(ctx, tx, userID)
 	if err != nil {
 		return err
 	}
 
-	return tx.Commit(ctx)
+	limits := override.Apply(defaults)
+	if err := limits.Check(amount, decimal.Zero, decimal.Zero); err != nil {
+		return err
+	}
+	if !limits.Daily.IsPositive() && !limits.Monthly.IsPositive() {
+		return nil
+	}
+
+	var withdrawnDay, withdrawnMonth decimal.Decimal
+	err = tx.QueryRow(ctx, `
+		SELECT
+			COALESCE(SUM(sum) FILTER (WHERE at > NOW() - INTERVAL '1 day'), 0),
+			COALESCE(SUM(sum), 0)
+		FROM (
+			SELECT sum, processed_at AS at
+			FROM withdrawals
+			WHERE user_id = $1
+			UNION ALL
+			SELECT amount, created_at
+			FROM point_holds
+			WHERE user_id = $1 AND status = $2 AND expires_at > NOW() AND id <> $3
+		) s
+		WHERE at > NOW() - INTERVAL '1 month'
+	`, userID, domain.HoldStatusActive, holdID).Scan(&withdrawnDay, &withdrawnMonth)
+	if err != nil {
+		return err
+	}
+
+	return limits.Check(amount, withdrawnDay, withdrawnMonth)
 }
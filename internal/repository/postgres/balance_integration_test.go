@@ -5,6 +5,7 @@ package postgres_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/arvaliullin/gophermart/internal/repository/postgres"
@@ -75,7 +76,7 @@ func TestBalanceRepository_AddAccrual(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("успешное добавление начисления", func(t *testing.T) {
-		err := balanceRepo.AddAccrual(ctx, user.ID, decimal.NewFromFloat(100.50))
+		err := balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "12345678903", Amount: decimal.NewFromFloat(100.50)})
 		require.NoError(t, err)
 
 		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
@@ -84,13 +85,187 @@ func TestBalanceRepository_AddAccrual(t *testing.T) {
 	})
 
 	t.Run("накопление начислений", func(t *testing.T) {
-		err := balanceRepo.AddAccrual(ctx, user.ID, decimal.NewFromFloat(50.25))
+		err := balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "79927398713", Amount: decimal.NewFromFloat(50.25)})
 		require.NoError(t, err)
 
 		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
 		require.NoError(t, err)
 		assert.True(t, decimal.NewFromFloat(150.75).Equal(balance.Current))
 	})
+
+	t.Run("повторное начисление за заказ отклоняется", func(t *testing.T) {
+		err := balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "79927398713", Amount: decimal.NewFromFloat(50.25)})
+		assert.ErrorIs(t, err, domain.ErrAccrualAlreadyApplied)
+
+		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(150.75).Equal(balance.Current))
+	})
+}
+
+func TestBalanceRepository_SettleAccrual(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	orderRepo := postgres.NewOrderRepository(testPool)
+	balanceRepo := postgres.NewBalanceRepository(testPool)
+	reconRepo := postgres.NewReconciliationRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "settleuser", "password")
+	require.NoError(t, err)
+	order, err := orderRepo.Create(ctx, user.ID, "12345678903")
+	require.NoError(t, err)
+
+	settle := func(amount float64) *domain.LedgerEntry {
+		t.Helper()
+		accrual := decimal.NewFromFloat(amount)
+		var orderAccrual *decimal.Decimal
+		status := domain.OrderStatusInvalid
+		if accrual.IsPositive() {
+			status, orderAccrual = domain.OrderStatusProcessed, &accrual
+		}
+		_, err := orderRepo.Reopen(ctx, order.ID)
+		require.NoError(t, err)
+		require.NoError(t, orderRepo.UpdateStatus(ctx, order.ID, status, orderAccrual))
+
+		entry, err := balanceRepo.SettleAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "12345678903", Amount: accrual}, "перепроверка")
+		require.NoError(t, err)
+		return entry
+	}
+	assertBalance := func(expected float64) {
+		t.Helper()
+		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(expected).Equal(balance.Current), balance.Current.String())
+
+		mismatches, err := reconRepo.FindMismatches(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, mismatches)
+	}
+
+	t.Run("первое начисление записывается проводкой ACCRUAL", func(t *testing.T) {
+		entry := settle(100)
+		require.NotNil(t, entry)
+		assert.Equal(t, domain.LedgerEntryAccrual, entry.Kind)
+		assertBalance(100)
+	})
+
+	t.Run("без изменения суммы проводка не записывается", func(t *testing.T) {
+		assert.Nil(t, settle(100))
+		assertBalance(100)
+	})
+
+	t.Run("доначисление записывается разницей", func(t *testing.T) {
+		entry := settle(150)
+		require.NotNil(t, entry)
+		assert.Equal(t, domain.LedgerEntryAccrualCorrection, entry.Kind)
+		assert.True(t, decimal.NewFromFloat(50).Equal(entry.Amount))
+		assert.Equal(t, "перепроверка", entry.Description)
+		assertBalance(150)
+	})
+
+	t.Run("отзыв начисления", func(t *testing.T) {
+		entry := settle(0)
+		require.NotNil(t, entry)
+		assert.True(t, decimal.NewFromFloat(-150).Equal(entry.Amount))
+		assertBalance(0)
+	})
+
+	t.Run("повторное начисление после отзыва", func(t *testing.T) {
+		err := balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "12345678903", Amount: decimal.NewFromFloat(120)})
+		require.NoError(t, err)
+
+		err = balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "12345678903", Amount: decimal.NewFromFloat(120)})
+		assert.ErrorIs(t, err, domain.ErrAccrualAlreadyApplied)
+
+		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(120).Equal(balance.Current))
+	})
+}
+
+func TestBalanceRepository_SettleAccrualBonus(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	orderRepo := postgres.NewOrderRepository(testPool)
+	balanceRepo := postgres.NewBalanceRepository(testPool)
+	ledgerRepo := postgres.NewLedgerRepository(testPool)
+	reconRepo := postgres.NewReconciliationRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "bonususer", "password")
+	require.NoError(t, err)
+	order, err := orderRepo.Create(ctx, user.ID, "12345678903")
+	require.NoError(t, err)
+
+	settle := func(amount, bonus float64) {
+		t.Helper()
+		accrual := decimal.NewFromFloat(amount)
+		var orderAccrual *decimal.Decimal
+		status := domain.OrderStatusInvalid
+		if accrual.IsPositive() {
+			status, orderAccrual = domain.OrderStatusProcessed, &accrual
+		}
+		_, err := orderRepo.Reopen(ctx, order.ID)
+		require.NoError(t, err)
+		require.NoError(t, orderRepo.UpdateStatus(ctx, order.ID, status, orderAccrual))
+
+		_, err = balanceRepo.SettleAccrual(ctx, &domain.Accrual{
+			UserID:      user.ID,
+			OrderNumber: "12345678903",
+			Amount:      accrual,
+			Bonus:       decimal.NewFromFloat(bonus),
+		}, "перепроверка")
+		require.NoError(t, err)
+	}
+	assertBalance := func(expected float64) {
+		t.Helper()
+		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(expected).Equal(balance.Current), balance.Current.String())
+
+		mismatches, err := reconRepo.FindMismatches(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, mismatches)
+	}
+	bonusCorrections := func() []decimal.Decimal {
+		t.Helper()
+		statement, err := ledgerRepo.GetStatement(ctx, user.ID, domain.StatementQuery{Limit: 100})
+		require.NoError(t, err)
+		var amounts []decimal.Decimal
+		for _, entry := range statement {
+			if entry.Kind == domain.LedgerEntryBonusCorrection {
+				assert.Contains(t, entry.Description, "перепроверка")
+				amounts = append(amounts, entry.Amount)
+			}
+		}
+		return amounts
+	}
+
+	settle(100, 25)
+	assertBalance(125)
+
+	t.Run("отзыв начисления отзывает надбавку", func(t *testing.T) {
+		settle(0, 0)
+		assertBalance(0)
+
+		corrections := bonusCorrections()
+		require.Len(t, corrections, 1)
+		assert.True(t, decimal.NewFromFloat(-25).Equal(corrections[0]))
+	})
+
+	t.Run("уменьшение начисления пересчитывает надбавку", func(t *testing.T) {
+		settle(80, 20)
+		assertBalance(100)
+
+		settle(40, 10)
+		assertBalance(50)
+
+		corrections := bonusCorrections()
+		require.Len(t, corrections, 3)
+		assert.True(t, decimal.NewFromFloat(20).Equal(corrections[1]))
+		assert.True(t, decimal.NewFromFloat(-10).Equal(corrections[2]))
+	})
 }
 
 func TestBalanceRepository_Withdraw(t *testing.T) {
@@ -102,11 +277,11 @@ func TestBalanceRepository_Withdraw(t *testing.T) {
 	user, err := userRepo.Create(ctx, "withdrawuser", "password")
 	require.NoError(t, err)
 
-	err = balanceRepo.AddAccrual(ctx, user.ID, decimal.NewFromFloat(500.0))
+	err = balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "4561261212345467", Amount: decimal.NewFromFloat(500.0)})
 	require.NoError(t, err)
 
 	t.Run("успешное списание средств", func(t *testing.T) {
-		err := balanceRepo.Withdraw(ctx, user.ID, "2377225624", decimal.NewFromFloat(100.0))
+		err := balanceRepo.Withdraw(ctx, user.ID, "2377225624", decimal.NewFromFloat(100.0), domain.WithdrawalLimits{})
 		require.NoError(t, err)
 
 		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
@@ -116,7 +291,7 @@ func TestBalanceRepository_Withdraw(t *testing.T) {
 	})
 
 	t.Run("повторное списание", func(t *testing.T) {
-		err := balanceRepo.Withdraw(ctx, user.ID, "1234567890", decimal.NewFromFloat(150.0))
+		err := balanceRepo.Withdraw(ctx, user.ID, "1234567890", decimal.NewFromFloat(150.0), domain.WithdrawalLimits{})
 		require.NoError(t, err)
 
 		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
@@ -126,7 +301,7 @@ func TestBalanceRepository_Withdraw(t *testing.T) {
 	})
 
 	t.Run("ошибка при недостаточном балансе", func(t *testing.T) {
-		err := balanceRepo.Withdraw(ctx, user.ID, "9999999999", decimal.NewFromFloat(1000.0))
+		err := balanceRepo.Withdraw(ctx, user.ID, "9999999999", decimal.NewFromFloat(1000.0), domain.WithdrawalLimits{})
 		assert.ErrorIs(t, err, domain.ErrInsufficientBalance)
 
 		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
@@ -138,7 +313,319 @@ func TestBalanceRepository_Withdraw(t *testing.T) {
 		user2, err := userRepo.Create(ctx, "nobalance", "password")
 		require.NoError(t, err)
 
-		err = balanceRepo.Withdraw(ctx, user2.ID, "0000000000", decimal.NewFromFloat(10.0))
+		err = balanceRepo.Withdraw(ctx, user2.ID, "0000000000", decimal.NewFromFloat(10.0), domain.WithdrawalLimits{})
+		assert.ErrorIs(t, err, domain.ErrInsufficientBalance)
+	})
+}
+
+func TestBalanceRepository_Refund(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	balanceRepo := postgres.NewBalanceRepository(testPool)
+	withdrawalRepo := postgres.NewWithdrawalRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "refunduser", "password")
+	require.NoError(t, err)
+
+	err = balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "4561261212345467", Amount: decimal.NewFromFloat(500.0)})
+	require.NoError(t, err)
+	err = balanceRepo.Withdraw(ctx, user.ID, "2377225624", decimal.NewFromFloat(200.0), domain.WithdrawalLimits{})
+	require.NoError(t, err)
+
+	withdrawals, err := withdrawalRepo.GetByUserID(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, withdrawals, 1)
+	withdrawalID := withdrawals[0].ID
+
+	t.Run("частичный возврат", func(t *testing.T) {
+		amount := decimal.NewFromFloat(50.0)
+		refund, err := balanceRepo.Refund(ctx, withdrawalID, &amount)
+		require.NoError(t, err)
+		assert.NotZero(t, refund.ID)
+		assert.Equal(t, withdrawalID, refund.WithdrawalID)
+		assert.Equal(t, user.ID, refund.UserID)
+		assert.True(t, amount.Equal(refund.Sum))
+
+		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(350.0).Equal(balance.Current))
+		assert.True(t, decimal.NewFromFloat(150.0).Equal(balance.Withdrawn))
+	})
+
+	t.Run("ошибка при превышении суммы списания", func(t *testing.T) {
+		amount := decimal.NewFromFloat(200.0)
+		_, err := balanceRepo.Refund(ctx, withdrawalID, &amount)
+		assert.ErrorIs(t, err, domain.ErrRefundExceedsWithdrawal)
+	})
+
+	t.Run("полный возврат остатка", func(t *testing.T) {
+		refund, err := balanceRepo.Refund(ctx, withdrawalID, nil)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(150.0).Equal(refund.Sum))
+
+		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(500.0).Equal(balance.Current))
+		assert.True(t, decimal.Zero.Equal(balance.Withdrawn))
+
+		withdrawals, err := withdrawalRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(200.0).Equal(withdrawals[0].Refunded))
+	})
+
+	t.Run("ошибка при повторном полном возврате", func(t *testing.T) {
+		_, err := balanceRepo.Refund(ctx, withdrawalID, nil)
+		assert.ErrorIs(t, err, domain.ErrRefundExceedsWithdrawal)
+	})
+
+	t.Run("ошибка для несуществующего списания", func(t *testing.T) {
+		_, err := balanceRepo.Refund(ctx, withdrawalID+1000, nil)
+		assert.ErrorIs(t, err, domain.ErrWithdrawalNotFound)
+	})
+}
+
+func TestBalanceRepository_Holds(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	balanceRepo := postgres.NewBalanceRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "holduser", "password")
+	require.NoError(t, err)
+
+	err = balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "4561261212345467", Amount: decimal.NewFromFloat(500.0)})
+	require.NoError(t, err)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	t.Run("блокировка уменьшает доступный остаток", func(t *testing.T) {
+		hold, err := balanceRepo.Hold(ctx, user.ID, "2377225624", decimal.NewFromFloat(200.0), expiresAt, domain.WithdrawalLimits{})
+		require.NoError(t, err)
+		assert.NotZero(t, hold.ID)
+		assert.Equal(t, domain.HoldStatusActive, hold.Status)
+
+		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(300.0).Equal(balance.Current))
+		assert.True(t, decimal.NewFromFloat(200.0).Equal(balance.OnHold))
+		assert.True(t, decimal.Zero.Equal(balance.Withdrawn))
+	})
+
+	t.Run("списание не может использовать заблокированные баллы", func(t *testing.T) {
+		err := balanceRepo.Withdraw(ctx, user.ID, "1234567890", decimal.NewFromFloat(400.0), domain.WithdrawalLimits{})
+		assert.ErrorIs(t, err, domain.ErrInsufficientBalance)
+
+		_, err = balanceRepo.Hold(ctx, user.ID, "1234567890", decimal.NewFromFloat(400.0), expiresAt, domain.WithdrawalLimits{})
+		assert.ErrorIs(t, err, domain.ErrInsufficientBalance)
+	})
+
+	t.Run("подтверждение превращает блокировку в списание", func(t *testing.T) {
+		hold, err := balanceRepo.Hold(ctx, user.ID, "79927398713", decimal.NewFromFloat(100.0), expiresAt, domain.WithdrawalLimits{})
+		require.NoError(t, err)
+
+		withdrawal, err := balanceRepo.CaptureHold(ctx, hold.ID, domain.WithdrawalLimits{})
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, withdrawal.UserID)
+		assert.Equal(t, "79927398713", withdrawal.OrderNumber)
+		assert.True(t, decimal.NewFromFloat(100.0).Equal(withdrawal.Sum))
+
+		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(200.0).Equal(balance.Current))
+		assert.True(t, decimal.NewFromFloat(200.0).Equal(balance.OnHold))
+		assert.True(t, decimal.NewFromFloat(100.0).Equal(balance.Withdrawn))
+
+		_, err = balanceRepo.CaptureHold(ctx, hold.ID, domain.WithdrawalLimits{})
+		assert.ErrorIs(t, err, domain.ErrHoldNotActive)
+	})
+
+	t.Run("подтверждение сверх лимита отклоняется", func(t *testing.T) {
+		hold, err := balanceRepo.Hold(ctx, user.ID, "378282246310005", decimal.NewFromFloat(60.0), expiresAt, domain.WithdrawalLimits{})
+		require.NoError(t, err)
+
+		limits := domain.WithdrawalLimits{Daily: decimal.NewFromFloat(150.0)}
+		_, err = balanceRepo.CaptureHold(ctx, hold.ID, limits)
+		assert.ErrorIs(t, err, domain.ErrWithdrawalLimitExceeded)
+
+		limits = domain.WithdrawalLimits{PerTransaction: decimal.NewFromFloat(50.0)}
+		_, err = balanceRepo.CaptureHold(ctx, hold.ID, limits)
+		assert.ErrorIs(t, err, domain.ErrWithdrawalLimitExceeded)
+
+		released, err := balanceRepo.ReleaseHold(ctx, hold.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.HoldStatusReleased, released.Status)
+	})
+
+	t.Run("блокировка сверх лимита отклоняется при создании", func(t *testing.T) {
+		// За сутки списано 100 и заблокировано 200.
+		limits := domain.WithdrawalLimits{Daily: decimal.NewFromFloat(400.0)}
+		hold, err := balanceRepo.Hold(ctx, user.ID, "6011111111111117", decimal.NewFromFloat(90.0), expiresAt, limits)
+		require.NoError(t, err)
+
+		_, err = balanceRepo.Hold(ctx, user.ID, "6011000990139424", decimal.NewFromFloat(20.0), expiresAt, limits)
+		assert.ErrorIs(t, err, domain.ErrWithdrawalLimitExceeded)
+
+		err = balanceRepo.Withdraw(ctx, user.ID, "6011000990139424", decimal.NewFromFloat(20.0), limits)
+		assert.ErrorIs(t, err, domain.ErrWithdrawalLimitExceeded)
+
+		limits = domain.WithdrawalLimits{PerTransaction: decimal.NewFromFloat(50.0)}
+		_, err = balanceRepo.Hold(ctx, user.ID, "6011000990139424", decimal.NewFromFloat(60.0), expiresAt, limits)
+		assert.ErrorIs(t, err, domain.ErrWithdrawalLimitExceeded)
+
+		_, err = balanceRepo.ReleaseHold(ctx, hold.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("отмена возвращает баллы", func(t *testing.T) {
+		hold, err := balanceRepo.Hold(ctx, user.ID, "12345678903", decimal.NewFromFloat(50.0), expiresAt, domain.WithdrawalLimits{})
+		require.NoError(t, err)
+
+		released, err := balanceRepo.ReleaseHold(ctx, hold.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.HoldStatusReleased, released.Status)
+
+		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(200.0).Equal(balance.Current))
+		assert.True(t, decimal.NewFromFloat(200.0).Equal(balance.OnHold))
+	})
+
+	t.Run("истёкшие блокировки завершаются", func(t *testing.T) {
+		hold, err := balanceRepo.Hold(ctx, user.ID, "4111111111111111", decimal.NewFromFloat(10.0), time.Now().Add(-time.Second), domain.WithdrawalLimits{})
+		require.NoError(t, err)
+
+		expired, err := balanceRepo.ExpireHolds(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), expired)
+
+		_, err = balanceRepo.CaptureHold(ctx, hold.ID, domain.WithdrawalLimits{})
+		assert.ErrorIs(t, err, domain.ErrHoldNotActive)
+	})
+
+	t.Run("ошибка для несуществующей блокировки", func(t *testing.T) {
+		hold, err := balanceRepo.Hold(ctx, user.ID, "5555555555554444", decimal.NewFromFloat(10.0), expiresAt, domain.WithdrawalLimits{})
+		require.NoError(t, err)
+
+		_, err = balanceRepo.ReleaseHold(ctx, hold.ID+1000)
+		assert.ErrorIs(t, err, domain.ErrHoldNotFound)
+	})
+}
+
+func TestBalanceRepository_PointsExpiration(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	balanceRepo := postgres.NewBalanceRepository(testPool)
+	ledgerRepo := postgres.NewLedgerRepository(testPool)
+
+	t.Run("списание расходует партии в порядке сгорания", func(t *testing.T) {
+		user, err := userRepo.Create(ctx, "fifouser", "password")
+		require.NoError(t, err)
+
+		soon := time.Now().Add(24 * time.Hour)
+		err = balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "12345678903", Amount: decimal.NewFromFloat(100.0)})
+		require.NoError(t, err)
+		err = balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "79927398713", Amount: decimal.NewFromFloat(100.0), ExpiresAt: &soon})
+		require.NoError(t, err)
+
+		expiring, err := balanceRepo.GetExpiring(ctx, user.ID, time.Now().Add(48*time.Hour))
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(100.0).Equal(expiring))
+
+		err = balanceRepo.Withdraw(ctx, user.ID, "2377225624", decimal.NewFromFloat(120.0), domain.WithdrawalLimits{})
+		require.NoError(t, err)
+
+		expiring, err = balanceRepo.GetExpiring(ctx, user.ID, time.Now().Add(48*time.Hour))
+		require.NoError(t, err)
+		assert.True(t, decimal.Zero.Equal(expiring))
+	})
+
+	t.Run("сгоревшие партии списываются проводкой EXPIRATION", func(t *testing.T) {
+		user, err := userRepo.Create(ctx, "expireuser", "password")
+		require.NoError(t, err)
+
+		past := time.Now().Add(-time.Second)
+		err = balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "4561261212345467", Amount: decimal.NewFromFloat(80.0), ExpiresAt: &past})
+		require.NoError(t, err)
+		err = balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "4111111111111111", Amount: decimal.NewFromFloat(50.0)})
+		require.NoError(t, err)
+		err = balanceRepo.Withdraw(ctx, user.ID, "1234567890", decimal.NewFromFloat(30.0), domain.WithdrawalLimits{})
+		require.NoError(t, err)
+
+		expired, err := balanceRepo.ExpirePoints(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), expired)
+
+		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(50.0).Equal(balance.Current))
+		assert.True(t, decimal.NewFromFloat(30.0).Equal(balance.Withdrawn))
+
+		entries, err := ledgerRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		last := entries[len(entries)-1]
+		assert.Equal(t, domain.LedgerEntryExpiration, last.Kind)
+		assert.True(t, decimal.NewFromFloat(-50.0).Equal(last.Amount))
+
+		expired, err = balanceRepo.ExpirePoints(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, expired)
+	})
+}
+
+func TestBalanceRepository_Transfer(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	balanceRepo := postgres.NewBalanceRepository(testPool)
+	ledgerRepo := postgres.NewLedgerRepository(testPool)
+
+	sender, err := userRepo.Create(ctx, "sender", "password")
+	require.NoError(t, err)
+	recipient, err := userRepo.Create(ctx, "recipient", "password")
+	require.NoError(t, err)
+
+	soon := time.Now().Add(24 * time.Hour)
+	err = balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: sender.ID, OrderNumber: "12345678903", Amount: decimal.NewFromFloat(100.0), ExpiresAt: &soon})
+	require.NoError(t, err)
+
+	t.Run("переводит баллы и сохраняет срок сгорания", func(t *testing.T) {
+		transfer, err := balanceRepo.Transfer(ctx, sender.ID, recipient.ID, decimal.NewFromFloat(40.0), decimal.Zero)
+		require.NoError(t, err)
+		assert.Equal(t, sender.ID, transfer.FromUserID)
+		assert.Equal(t, recipient.ID, transfer.ToUserID)
+
+		senderBalance, err := balanceRepo.GetByUserID(ctx, sender.ID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(60.0).Equal(senderBalance.Current))
+		assert.True(t, decimal.Zero.Equal(senderBalance.Withdrawn))
+
+		recipientBalance, err := balanceRepo.GetByUserID(ctx, recipient.ID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(40.0).Equal(recipientBalance.Current))
+
+		expiring, err := balanceRepo.GetExpiring(ctx, recipient.ID, time.Now().Add(48*time.Hour))
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(40.0).Equal(expiring))
+
+		entries, err := ledgerRepo.GetByUserID(ctx, recipient.ID)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, domain.LedgerEntryTransferIn, entries[0].Kind)
+
+		entries, err = ledgerRepo.GetByUserID(ctx, sender.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.LedgerEntryTransferOut, entries[len(entries)-1].Kind)
+	})
+
+	t.Run("отклоняет перевод сверх суточного лимита", func(t *testing.T) {
+		_, err := balanceRepo.Transfer(ctx, sender.ID, recipient.ID, decimal.NewFromFloat(20.0), decimal.NewFromFloat(50.0))
+		assert.ErrorIs(t, err, domain.ErrTransferLimitExceeded)
+	})
+
+	t.Run("отклоняет перевод при недостатке средств", func(t *testing.T) {
+		_, err := balanceRepo.Transfer(ctx, sender.ID, recipient.ID, decimal.NewFromFloat(100.0), decimal.Zero)
 		assert.ErrorIs(t, err, domain.ErrInsufficientBalance)
 	})
 }
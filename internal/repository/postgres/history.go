@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	historyRecordOrder      = "order"
+	historyRecordWithdrawal = "withdrawal"
+)
+
+// HistoryRepository реализует интерфейс ports.HistoryRepository для PostgreSQL.
+type HistoryRepository struct {
+	db dbtx
+}
+
+// NewHistoryRepository создаёт новый репозиторий истории по счёту.
+func NewHistoryRepository(pool *pgxpool.Pool) *HistoryRepository {
+	return &HistoryRepository{db: pool}
+}
+
+// StreamByUserID передаёт в fn заказы и списания пользователя за период
+// [from, to) одной хронологической лентой: заказы по времени загрузки,
+// списания по времени выполнения. nil снимает ограничение с соответствующей
+// стороны.
+//
+// Чтение выполняется в транзакции REPEATABLE READ READ ONLY, поэтому
+// выгрузка соответствует одному моменту времени, даже если пользователь
+// загружает заказы или списывает баллы во время её передачи.
+func (r *HistoryRepository) StreamByUserID(ctx context.Context, userID int64, from, to *time.Time, fn func(*domain.HistoryRecord) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY`); err != nil {
+		return err
+	}
+
+	query := `
+		SELECT kind, id, user_id, number, status, amount, refunded, at
+		FROM (
+			SELECT $4::text AS kind, o.id, o.user_id, o.number, o.status,
+				o.accrual AS amount, 0::numeric AS refunded, o.uploaded_at AS at
+			FROM orders o
+			WHERE o.user_id = $1
+				AND ($2::timestamptz IS NULL OR o.uploaded_at >= $2)
+				AND ($3::timestamptz IS NULL OR o.uploaded_at < $3)
+			UNION ALL
+			SELECT $5::text, w.id, w.user_id, w.order_number, '',
+				w.sum,
+				COALESCE((SELECT SUM(rf.sum) FROM withdrawal_refunds rf WHERE rf.withdrawal_id = w.id), 0),
+				w.processed_at
+			FROM withdrawals w
+			WHERE w.user_id = $1
+				AND ($2::timestamptz IS NULL OR w.processed_at >= $2)
+				AND ($3::timestamptz IS NULL OR w.processed_at < $3)
+		) h
+		ORDER BY at ASC, kind ASC, id ASC
+	`
+
+	rows, err := tx.Query(ctx, query, userID, from, to, historyRecordOrder, historyRecordWithdrawal)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			kind, number, status string
+			id, ownerID          int64
+			amount               *decimal.Decimal
+			refunded             decimal.Decimal
+			at                   time.Time
+		)
+		if err := rows.Scan(&kind, &id, &ownerID, &number, &status, &amount, &refunded, &at); err != nil {
+			return err
+		}
+
+		record := &domain.HistoryRecord{}
+		if kind == historyRecordWithdrawal {
+			record.Withdrawal = &domain.Withdrawal{
+				ID:          id,
+				UserID:      ownerID,
+				OrderNumber: number,
+				Sum:         *amount,
+				Refunded:    refunded,
+				ProcessedAt: at,
+			}
+		} else {
+			record.Order = &domain.Order{
+				ID:         id,
+				UserID:     ownerID,
+				Number:     number,
+				Status:     domain.OrderStatus(status),
+				Accrual:    amount,
+				UploadedAt: at,
+			}
+		}
+
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,73 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/repository/postgres"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryRepository_StreamByUserID(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	orderRepo := postgres.NewOrderRepository(testPool)
+	balanceRepo := postgres.NewBalanceRepository(testPool)
+	historyRepo := postgres.NewHistoryRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "streamhistory", "password")
+	require.NoError(t, err)
+
+	_, err = orderRepo.Create(ctx, user.ID, "2222222222")
+	require.NoError(t, err)
+	err = balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "4111111111111111", Amount: decimal.NewFromFloat(500.0)})
+	require.NoError(t, err)
+	err = balanceRepo.Withdraw(ctx, user.ID, "1111111111", decimal.NewFromFloat(100.0), domain.WithdrawalLimits{})
+	require.NoError(t, err)
+	_, err = orderRepo.Create(ctx, user.ID, "3333333333")
+	require.NoError(t, err)
+
+	t.Run("передаёт заказы и списания одной хронологической лентой", func(t *testing.T) {
+		var numbers []string
+		err := historyRepo.StreamByUserID(ctx, user.ID, nil, nil, func(record *domain.HistoryRecord) error {
+			if record.Withdrawal != nil {
+				numbers = append(numbers, "-"+record.Withdrawal.OrderNumber)
+				return nil
+			}
+			numbers = append(numbers, record.Order.Number)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2222222222", "-1111111111", "3333333333"}, numbers)
+	})
+
+	t.Run("учитывает период", func(t *testing.T) {
+		future := time.Now().Add(time.Hour)
+		calls := 0
+		err := historyRepo.StreamByUserID(ctx, user.ID, &future, nil, func(*domain.HistoryRecord) error {
+			calls++
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Zero(t, calls)
+	})
+
+	t.Run("ошибка обработчика прерывает чтение", func(t *testing.T) {
+		stopErr := errors.New("stop")
+		calls := 0
+		err := historyRepo.StreamByUserID(ctx, user.ID, nil, nil, func(*domain.HistoryRecord) error {
+			calls++
+			return stopErr
+		})
+		assert.ErrorIs(t, err, stopErr)
+		assert.Equal(t, 1, calls)
+	})
+}
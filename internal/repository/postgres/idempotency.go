@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IdempotencyRepository реализует интерфейс ports.IdempotencyRepository для PostgreSQL.
+type IdempotencyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewIdempotencyRepository создаёт новый репозиторий ключей идемпотентности.
+func NewIdempotencyRepository(pool *pgxpool.Pool) *IdempotencyRepository {
+	return &IdempotencyRepository{pool: pool}
+}
+
+// Reserve занимает ключ идемпотентности за пользователем на lockTTL.
+// Незавершённый ключ с истёкшей блокировкой занимается заново.
+// Если ключ уже занят, возвращает существующую запись и created == false.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, userID int64, key, requestHash string, lockTTL time.Duration) (*domain.IdempotencyRecord, bool, error) {
+	insertQuery := `
+		INSERT INTO idempotency_keys (user_id, key, request_hash, locked_until)
+		VALUES ($1, $2, $3, NOW() + make_interval(secs => $4))
+		ON CONFLICT (user_id, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+			locked_until = EXCLUDED.locked_until,
+			created_at = NOW()
+		WHERE idempotency_keys.status_code IS NULL
+			AND idempotency_keys.locked_until < NOW()
+		RETURNING created_at
+	`
+
+	record := domain.IdempotencyRecord{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: requestHash,
+	}
+	err := r.pool.QueryRow(ctx, insertQuery, userID, key, requestHash, lockTTL.Seconds()).Scan(&record.CreatedAt)
+	if err == nil {
+		return &record, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, err
+	}
+
+	// Ключ уже занят: отдельный запрос видит строку, зафиксированную конкурирующей транзакцией.
+	selectQuery := `
+		SELECT request_hash, COALESCE(status_code, 0), content_type, response_body, created_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND key = $2
+	`
+
+	err = r.pool.QueryRow(ctx, selectQuery, userID, key).Scan(
+		&record.RequestHash,
+		&record.StatusCode,
+		&record.ContentType,
+		&record.ResponseBody,
+		&record.CreatedAt,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &record, false, nil
+}
+
+// Extend продлевает блокировку незавершённого ключа на lockTTL от текущего момента.
+func (r *IdempotencyRepository) Extend(ctx context.Context, userID int64, key string, lockTTL time.Duration) error {
+	query := `
+		UPDATE idempotency_keys
+		SET locked_until = NOW() + make_interval(secs => $3)
+		WHERE user_id = $1 AND key = $2 AND status_code IS NULL
+	`
+
+	_, err := r.pool.Exec(ctx, query, userID, key, lockTTL.Seconds())
+	return err
+}
+
+// Complete сохраняет ответ на запрос, выполненный с ключом идемпотентности.
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $3, content_type = $4, response_body = $5
+		WHERE user_id = $1 AND key = $2 AND status_code IS NULL
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		record.UserID,
+		record.Key,
+		record.StatusCode,
+		record.ContentType,
+		record.ResponseBody,
+	)
+	return err
+}
+
+// Release освобождает ключ, для которого ответ так и не был сохранён.
+func (r *IdempotencyRepository) Release(ctx context.Context, userID int64, key string) error {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE user_id = $1 AND key = $2 AND status_code IS NULL
+	`
+
+	_, err := r.pool.Exec(ctx, query, userID, key)
+	return err
+}
+
+// PurgeExpired удаляет ключи, занятые раньше before: завершённые и
+// незавершённые с истёкшей блокировкой. Возвращает число удалённых ключей.
+func (r *IdempotencyRepository) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE created_at < $1
+			AND (status_code IS NOT NULL OR locked_until < NOW())
+	`
+
+	tag, err := r.pool.Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
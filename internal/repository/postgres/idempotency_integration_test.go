@@ -0,0 +1,170 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyRepository_Reserve(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	idempotencyRepo := postgres.NewIdempotencyRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "idempotentuser", "password")
+	require.NoError(t, err)
+
+	t.Run("новый ключ занимается", func(t *testing.T) {
+		record, created, err := idempotencyRepo.Reserve(ctx, user.ID, "key-1", "hash-1", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.False(t, record.IsCompleted())
+	})
+
+	t.Run("повтор возвращает сохранённый ответ", func(t *testing.T) {
+		record, _, err := idempotencyRepo.Reserve(ctx, user.ID, "key-1", "hash-1", time.Minute)
+		require.NoError(t, err)
+
+		record.StatusCode = http.StatusAccepted
+		record.ContentType = "text/plain"
+		record.ResponseBody = []byte("принято")
+		require.NoError(t, idempotencyRepo.Complete(ctx, record))
+
+		stored, created, err := idempotencyRepo.Reserve(ctx, user.ID, "key-1", "hash-2", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, "hash-1", stored.RequestHash)
+		assert.Equal(t, http.StatusAccepted, stored.StatusCode)
+		assert.Equal(t, "text/plain", stored.ContentType)
+		assert.Equal(t, "принято", string(stored.ResponseBody))
+	})
+
+	t.Run("незавершённый ключ с истёкшей блокировкой занимается заново", func(t *testing.T) {
+		_, created, err := idempotencyRepo.Reserve(ctx, user.ID, "key-stale", "hash-1", time.Millisecond)
+		require.NoError(t, err)
+		require.True(t, created)
+
+		_, created, err = idempotencyRepo.Reserve(ctx, user.ID, "key-stale", "hash-2", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, created)
+
+		time.Sleep(10 * time.Millisecond)
+
+		record, created, err := idempotencyRepo.Reserve(ctx, user.ID, "key-stale", "hash-2", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, "hash-2", record.RequestHash)
+	})
+
+	t.Run("ключи разных пользователей независимы", func(t *testing.T) {
+		user2, err := userRepo.Create(ctx, "idempotentuser2", "password")
+		require.NoError(t, err)
+
+		_, created, err := idempotencyRepo.Reserve(ctx, user2.ID, "key-1", "hash-1", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, created)
+	})
+}
+
+func TestIdempotencyRepository_Extend(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	idempotencyRepo := postgres.NewIdempotencyRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "extenduser", "password")
+	require.NoError(t, err)
+
+	t.Run("продлённый ключ не занимается повторно", func(t *testing.T) {
+		_, created, err := idempotencyRepo.Reserve(ctx, user.ID, "key-1", "hash-1", time.Millisecond)
+		require.NoError(t, err)
+		require.True(t, created)
+
+		require.NoError(t, idempotencyRepo.Extend(ctx, user.ID, "key-1", time.Minute))
+		time.Sleep(10 * time.Millisecond)
+
+		_, created, err = idempotencyRepo.Reserve(ctx, user.ID, "key-1", "hash-2", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, created)
+	})
+}
+
+func TestIdempotencyRepository_Release(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	idempotencyRepo := postgres.NewIdempotencyRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "releaseuser", "password")
+	require.NoError(t, err)
+
+	t.Run("незавершённый ключ освобождается", func(t *testing.T) {
+		_, _, err := idempotencyRepo.Reserve(ctx, user.ID, "key-1", "hash-1", time.Minute)
+		require.NoError(t, err)
+
+		require.NoError(t, idempotencyRepo.Release(ctx, user.ID, "key-1"))
+
+		_, created, err := idempotencyRepo.Reserve(ctx, user.ID, "key-1", "hash-2", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, created)
+	})
+
+	t.Run("завершённый ключ не освобождается", func(t *testing.T) {
+		record, _, err := idempotencyRepo.Reserve(ctx, user.ID, "key-2", "hash-1", time.Minute)
+		require.NoError(t, err)
+
+		record.StatusCode = http.StatusOK
+		require.NoError(t, idempotencyRepo.Complete(ctx, record))
+		require.NoError(t, idempotencyRepo.Release(ctx, user.ID, "key-2"))
+
+		_, created, err := idempotencyRepo.Reserve(ctx, user.ID, "key-2", "hash-1", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, created)
+	})
+}
+
+func TestIdempotencyRepository_PurgeExpired(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	idempotencyRepo := postgres.NewIdempotencyRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "purgeuser", "password")
+	require.NoError(t, err)
+
+	completed, _, err := idempotencyRepo.Reserve(ctx, user.ID, "completed", "hash-1", time.Minute)
+	require.NoError(t, err)
+	completed.StatusCode = http.StatusOK
+	require.NoError(t, idempotencyRepo.Complete(ctx, completed))
+
+	_, _, err = idempotencyRepo.Reserve(ctx, user.ID, "abandoned", "hash-1", time.Millisecond)
+	require.NoError(t, err)
+	_, _, err = idempotencyRepo.Reserve(ctx, user.ID, "in-progress", "hash-1", time.Minute)
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+
+	t.Run("свежие ключи не удаляются", func(t *testing.T) {
+		purged, err := idempotencyRepo.PurgeExpired(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, purged)
+	})
+
+	t.Run("удаляются завершённые и брошенные ключи", func(t *testing.T) {
+		purged, err := idempotencyRepo.PurgeExpired(ctx, time.Now().Add(time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+
+		_, created, err := idempotencyRepo.Reserve(ctx, user.ID, "in-progress", "hash-1", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, created)
+	})
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+// LedgerRepository реализует интерфейс ports.LedgerRepository для PostgreSQL.
+type LedgerRepository struct {
+	db dbtx
+}
+
+// NewLedgerRepository создаёт новый репозиторий журнала проводок.
+func NewLedgerRepository(pool *pgxpool.Pool) *LedgerRepository {
+	return &LedgerRepository{db: pool}
+}
+
+// GetByUserID возвращает все проводки пользователя в хронологическом порядке.
+func (r *LedgerRepository) GetByUserID(ctx context.Context, userID int64) ([]*domain.LedgerEntry, error) {
+	query := `
+		SELECT id, user_id, kind, amount, COALESCE(order_number, ''), description, created_at
+		FROM ledger_entries
+		WHERE user_id = $1
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.LedgerEntry
+	for rows.Next() {
+		var entry domain.LedgerEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Kind,
+			&entry.Amount,
+			&entry.OrderNumber,
+			&entry.Description,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// addAdjustment записывает корректирующую проводку вида kind в рамках транзакции
+// и обновляет партии баллов пользователя.
+func addAdjustment(ctx context.Context, tx pgx.Tx, userID int64, kind domain.LedgerEntryKind, amount decimal.Decimal, description string) (*domain.LedgerEntry, error) {
+	// Upsert блокирует строку счёта до конца транзакции, как при списании.
+	_, err := tx.Exec(ctx, `
+		INSERT INTO balances (user_id)
+		VALUES ($1)
+		ON CONFLICT (user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO ledger_entries (user_id, kind, amount, description)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, user_id, kind, amount, COALESCE(order_number, ''), description, created_at
+	`
+
+	var entry domain.LedgerEntry
+	err = tx.QueryRow(ctx, query, userID, kind, amount, description).Scan(
+		&entry.ID,
+		&entry.UserID,
+		&entry.Kind,
+		&entry.Amount,
+		&entry.OrderNumber,
+		&entry.Description,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if amount.IsPositive() {
+		err = creditLot(ctx, tx, userID, entry.ID, amount, nil)
+	} else {
+		_, err = consumeLots(ctx, tx, userID, amount.Neg())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// GetStatement возвращает проводки пользователя с остатком баллов после каждой.
+//
+// Остаток считается по всей истории пользователя, поэтому он корректен
+// и для страниц, начинающихся с середины периода.
+func (r *LedgerRepository) GetStatement(ctx context.Context, userID int64, query domain.StatementQuery) ([]*domain.StatementEntry, error) {
+	sql := `
+		SELECT id, user_id, kind, amount, order_number, description, created_at, balance
+		FROM (
+			SELECT id, user_id, kind, amount, COALESCE(order_number, '') AS order_number,
+				description, created_at, SUM(amount) OVER (ORDER BY id) AS balance
+			FROM ledger_entries
+			WHERE user_id = $1
+		) s
+		WHERE id > $2
+			AND ($3::timestamptz IS NULL OR created_at >= $3)
+			AND ($4::timestamptz IS NULL OR created_at < $4)
+		ORDER BY id ASC
+		LIMIT $5
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID, query.Cursor, query.From, query.To, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.StatementEntry
+	for rows.Next() {
+		var entry domain.StatementEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Kind,
+			&entry.Amount,
+			&entry.OrderNumber,
+			&entry.Description,
+			&entry.CreatedAt,
+			&entry.Balance,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,95 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/repository/postgres"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedgerRepository_GetByUserID(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	balanceRepo := postgres.NewBalanceRepository(testPool)
+	ledgerRepo := postgres.NewLedgerRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "ledgeruser", "password")
+	require.NoError(t, err)
+
+	t.Run("пустой журнал для нового пользователя", func(t *testing.T) {
+		entries, err := ledgerRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("проводки начисления и списания", func(t *testing.T) {
+		err := balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "2377225624", Amount: decimal.NewFromFloat(300.0)})
+		require.NoError(t, err)
+		err = balanceRepo.Withdraw(ctx, user.ID, "2377225624", decimal.NewFromFloat(120.0), domain.WithdrawalLimits{})
+		require.NoError(t, err)
+
+		entries, err := ledgerRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+
+		assert.Equal(t, domain.LedgerEntryAccrual, entries[0].Kind)
+		assert.True(t, decimal.NewFromFloat(300.0).Equal(entries[0].Amount))
+
+		assert.Equal(t, domain.LedgerEntryWithdrawal, entries[1].Kind)
+		assert.True(t, decimal.NewFromFloat(-120.0).Equal(entries[1].Amount))
+		assert.Equal(t, "2377225624", entries[1].OrderNumber)
+	})
+}
+
+func TestLedgerRepository_GetStatement(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	balanceRepo := postgres.NewBalanceRepository(testPool)
+	ledgerRepo := postgres.NewLedgerRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "statementuser", "password")
+	require.NoError(t, err)
+
+	err = balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "12345678903", Amount: decimal.NewFromFloat(100.0)})
+	require.NoError(t, err)
+	err = balanceRepo.Withdraw(ctx, user.ID, "79927398713", decimal.NewFromFloat(30.0), domain.WithdrawalLimits{})
+	require.NoError(t, err)
+	err = balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "2377225624", Amount: decimal.NewFromFloat(5.0)})
+	require.NoError(t, err)
+
+	t.Run("считает остаток после каждой проводки", func(t *testing.T) {
+		entries, err := ledgerRepo.GetStatement(ctx, user.ID, domain.StatementQuery{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		assert.True(t, decimal.NewFromFloat(100.0).Equal(entries[0].Balance))
+		assert.True(t, decimal.NewFromFloat(70.0).Equal(entries[1].Balance))
+		assert.True(t, decimal.NewFromFloat(75.0).Equal(entries[2].Balance))
+	})
+
+	t.Run("продолжает выборку после курсора", func(t *testing.T) {
+		first, err := ledgerRepo.GetStatement(ctx, user.ID, domain.StatementQuery{Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, first, 1)
+
+		rest, err := ledgerRepo.GetStatement(ctx, user.ID, domain.StatementQuery{Cursor: first[0].ID, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, rest, 2)
+		assert.True(t, decimal.NewFromFloat(70.0).Equal(rest[0].Balance))
+	})
+
+	t.Run("фильтрует по периоду", func(t *testing.T) {
+		future := time.Now().Add(time.Hour)
+		entries, err := ledgerRepo.GetStatement(ctx, user.ID, domain.StatementQuery{From: &future, Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+)
+
+// keysetDirection возвращает оператор сравнения с курсором и направление
+// сортировки для постраничной выборки по паре (время, id).
+func keysetDirection(ascending bool) (op, dir string) {
+	if ascending {
+		return ">", "ASC"
+	}
+	return "<", "DESC"
+}
+
+// listArgs возвращает общие параметры постраничной выборки: границы периода,
+// позицию курсора и лимит. Отсутствующие значения передаются как NULL.
+func listArgs(query domain.ListQuery) (from, to, cursorTime *time.Time, cursorID *int64, limit *int) {
+	if query.Cursor != nil {
+		cursorTime = &query.Cursor.Time
+		cursorID = &query.Cursor.ID
+	}
+	if query.Limit > 0 {
+		limit = &query.Limit
+	}
+	return query.From, query.To, cursorTime, cursorID, limit
+}
@@ -3,6 +3,8 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/jackc/pgerrcode"
@@ -14,24 +16,30 @@ import (
 
 // OrderRepository реализует интерфейс ports.OrderRepository для PostgreSQL.
 type OrderRepository struct {
-	pool *pgxpool.Pool
+	db dbtx
 }
 
 // NewOrderRepository создаёт новый репозиторий заказов.
 func NewOrderRepository(pool *pgxpool.Pool) *OrderRepository {
-	return &OrderRepository{pool: pool}
+	return &OrderRepository{db: pool}
 }
 
-// Create создаёт новый заказ.
+// Create создаёт новый заказ и открывает его историю статусов.
 func (r *OrderRepository) Create(ctx context.Context, userID int64, number string) (*domain.Order, error) {
 	query := `
-		INSERT INTO orders (user_id, number, status)
-		VALUES ($1, $2, $3)
-		RETURNING id, user_id, number, status, accrual, uploaded_at
+		WITH inserted AS (
+			INSERT INTO orders (user_id, number, status)
+			VALUES ($1, $2, $3)
+			RETURNING id, user_id, number, status, accrual, uploaded_at
+		), history AS (
+			INSERT INTO order_status_history (order_id, status, changed_at)
+			SELECT id, status, uploaded_at FROM inserted
+		)
+		SELECT id, user_id, number, status, accrual, uploaded_at FROM inserted
 	`
 
 	var order domain.Order
-	err := r.pool.QueryRow(ctx, query, userID, number, domain.OrderStatusNew).Scan(
+	err := r.db.QueryRow(ctx, query, userID, number, domain.OrderStatusNew).Scan(
 		&order.ID,
 		&order.UserID,
 		&order.Number,
@@ -51,16 +59,96 @@ func (r *OrderRepository) Create(ctx context.Context, userID int64, number strin
 	return &order, nil
 }
 
-// GetByNumber возвращает заказ по номеру.
+// CreateBatch создаёт заказы пользователя одним запросом. Уже существующие
+// номера не изменяются: для них по владельцу определяется, загружен ли
+// заказ этим пользователем или другим. Отменённые заказы номер не занимают.
+func (r *OrderRepository) CreateBatch(ctx context.Context, userID int64, numbers []string) ([]*domain.OrderSubmission, error) {
+	query := `
+		WITH input AS (
+			SELECT DISTINCT unnest($2::text[]) AS number
+		), inserted AS (
+			INSERT INTO orders (user_id, number, status)
+			SELECT $1, number, $3 FROM input
+			ON CONFLICT (number) WHERE status <> 'CANCELED' DO NOTHING
+			RETURNING id, number, status, uploaded_at
+		), history AS (
+			INSERT INTO order_status_history (order_id, status, changed_at)
+			SELECT id, status, uploaded_at FROM inserted
+		)
+		SELECT i.number, ins.number IS NOT NULL, o.user_id
+		FROM input i
+		LEFT JOIN inserted ins ON ins.number = i.number
+		LEFT JOIN orders o ON o.number = i.number AND o.status <> $4
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, numbers, domain.OrderStatusNew, domain.OrderStatusCanceled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		submissions []*domain.OrderSubmission
+		unresolved  []*domain.OrderSubmission
+	)
+	for rows.Next() {
+		var (
+			submission domain.OrderSubmission
+			created    bool
+			ownerID    *int64
+		)
+		if err := rows.Scan(&submission.Number, &created, &ownerID); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case created:
+			submission.Result = domain.OrderSubmitAccepted
+		case ownerID == nil:
+			// Заказ вставлен параллельной транзакцией после снимка запроса,
+			// владельца можно узнать только отдельным чтением.
+			unresolved = append(unresolved, &submission)
+		default:
+			submission.Result = submitResultForOwner(*ownerID, userID)
+		}
+		submissions = append(submissions, &submission)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for _, submission := range unresolved {
+		order, err := r.GetByNumber(ctx, submission.Number)
+		if err != nil {
+			return nil, err
+		}
+		submission.Result = submitResultForOwner(order.UserID, userID)
+	}
+
+	return submissions, nil
+}
+
+func submitResultForOwner(ownerID, userID int64) domain.OrderSubmitResult {
+	if ownerID == userID {
+		return domain.OrderSubmitAlreadyUploaded
+	}
+	return domain.OrderSubmitBelongsToOther
+}
+
+// GetByNumber возвращает заказ по номеру. Если номер не занят действующим
+// заказом, возвращается последний отменённый заказ с этим номером.
 func (r *OrderRepository) GetByNumber(ctx context.Context, number string) (*domain.Order, error) {
 	query := `
 		SELECT id, user_id, number, status, accrual, uploaded_at
 		FROM orders
 		WHERE number = $1
+		ORDER BY status = $2, uploaded_at DESC, id DESC
+		LIMIT 1
 	`
 
 	var order domain.Order
-	err := r.pool.QueryRow(ctx, query, number).Scan(
+	err := r.db.QueryRow(ctx, query, number, domain.OrderStatusCanceled).Scan(
 		&order.ID,
 		&order.UserID,
 		&order.Number,
@@ -88,7 +176,7 @@ func (r *OrderRepository) GetByUserID(ctx context.Context, userID int64) ([]*dom
 		ORDER BY uploaded_at DESC
 	`
 
-	rows, err := r.pool.Query(ctx, query, userID)
+	rows, err := r.db.Query(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -114,16 +202,88 @@ func (r *OrderRepository) GetByUserID(ctx context.Context, userID int64) ([]*dom
 	return orders, rows.Err()
 }
 
-// GetPendingOrders возвращает заказы со статусами NEW или PROCESSING.
-func (r *OrderRepository) GetPendingOrders(ctx context.Context) ([]*domain.Order, error) {
-	query := `
+// List возвращает заказы пользователя, отобранные по query, упорядоченные
+// по дате загрузки и идентификатору.
+func (r *OrderRepository) List(ctx context.Context, userID int64, query domain.OrderListQuery) ([]*domain.Order, error) {
+	op, dir := keysetDirection(query.Ascending)
+	sql := fmt.Sprintf(`
 		SELECT id, user_id, number, status, accrual, uploaded_at
 		FROM orders
-		WHERE status IN ($1, $2)
-		ORDER BY uploaded_at ASC
+		WHERE user_id = $1
+			AND ($2::timestamptz IS NULL OR uploaded_at >= $2)
+			AND ($3::timestamptz IS NULL OR uploaded_at < $3)
+			AND ($4::timestamptz IS NULL OR (uploaded_at, id) %[1]s ($4, $5::bigint))
+			AND ($6::text[] IS NULL OR status = ANY($6))
+		ORDER BY uploaded_at %[2]s, id %[2]s
+		LIMIT $7
+	`, op, dir)
+
+	var statuses []string
+	for _, status := range query.Statuses {
+		statuses = append(statuses, string(status))
+	}
+
+	from, to, cursorTime, cursorID, limit := listArgs(query.ListQuery)
+	rows, err := r.db.Query(ctx, sql, userID, from, to, cursorTime, cursorID, statuses, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*domain.Order
+	for rows.Next() {
+		var order domain.Order
+		err := rows.Scan(
+			&order.ID,
+			&order.UserID,
+			&order.Number,
+			&order.Status,
+			&order.Accrual,
+			&order.UploadedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, &order)
+	}
+
+	return orders, rows.Err()
+}
+
+// GetPendingOrders арендует на lease не более limit заказов со статусами
+// NEW или PROCESSING, срок проверки которых наступил, и возвращает их
+// в порядке наступления срока. Нулевой limit снимает ограничение.
+//
+// Заказы, арендованные другим экземпляром сервиса, пропускаются до истечения
+// аренды, а строки, заблокированные параллельной выборкой, — благодаря
+// SKIP LOCKED, поэтому каждый заказ достаётся только одному экземпляру.
+// Аренда упавшего экземпляра истекает сама.
+func (r *OrderRepository) GetPendingOrders(ctx context.Context, limit int, lease time.Duration) ([]*domain.Order, error) {
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM orders
+			WHERE status IN ($1, $2)
+				AND next_check_at <= NOW()
+				AND dead_lettered_at IS NULL
+				AND (locked_until IS NULL OR locked_until <= NOW())
+			ORDER BY next_check_at ASC, id ASC
+			LIMIT NULLIF($3, 0)
+			FOR UPDATE SKIP LOCKED
+		), claimed AS (
+			UPDATE orders o
+			SET locked_until = NOW() + make_interval(secs => $4)
+			FROM due
+			WHERE o.id = due.id
+			RETURNING o.id, o.user_id, o.number, o.status, o.accrual, o.uploaded_at,
+				o.attempts, o.next_check_at
+		)
+		SELECT id, user_id, number, status, accrual, uploaded_at, attempts
+		FROM claimed
+		ORDER BY next_check_at ASC, id ASC
 	`
 
-	rows, err := r.pool.Query(ctx, query, domain.OrderStatusNew, domain.OrderStatusProcessing)
+	rows, err := r.db.Query(ctx, query, domain.OrderStatusNew, domain.OrderStatusProcessing, limit, lease.Seconds())
 	if err != nil {
 		return nil, err
 	}
@@ -139,6 +299,7 @@ func (r *OrderRepository) GetPendingOrders(ctx context.Context) ([]*domain.Order
 			&order.Status,
 			&order.Accrual,
 			&order.UploadedAt,
+			&order.Attempts,
 		)
 		if err != nil {
 			return nil, err
@@ -149,22 +310,326 @@ func (r *OrderRepository) GetPendingOrders(ctx context.Context) ([]*domain.Order
 	return orders, rows.Err()
 }
 
-// UpdateStatus обновляет статус и начисление заказа.
-func (r *OrderRepository) UpdateStatus(ctx context.Context, number string, status domain.OrderStatus, accrual *decimal.Decimal) error {
+// ClaimLease арендует заказ orderID на lease, если его не арендовал другой
+// экземпляр сервиса. Арендованный заказ возвращает domain.ErrOrderLocked,
+// отсутствующий — domain.ErrOrderNotFound.
+func (r *OrderRepository) ClaimLease(ctx context.Context, orderID int64, lease time.Duration) error {
+	query := `
+		WITH current AS (
+			SELECT id, locked_until
+			FROM orders
+			WHERE id = $1
+			FOR UPDATE
+		), claimed AS (
+			UPDATE orders o
+			SET locked_until = NOW() + make_interval(secs => $2)
+			FROM current c
+			WHERE o.id = c.id AND (c.locked_until IS NULL OR c.locked_until <= NOW())
+			RETURNING o.id
+		)
+		SELECT
+			EXISTS (SELECT 1 FROM claimed),
+			EXISTS (SELECT 1 FROM current)
+	`
+
+	var claimed, exists bool
+	if err := r.db.QueryRow(ctx, query, orderID, lease.Seconds()).Scan(&claimed, &exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return domain.ErrOrderNotFound
+	}
+	if !claimed {
+		return domain.ErrOrderLocked
+	}
+
+	return nil
+}
+
+// ReleaseLease снимает аренду заказа, чтобы он снова попал в выборку
+// необработанных заказов.
+func (r *OrderRepository) ReleaseLease(ctx context.Context, orderID int64) error {
+	_, err := r.db.Exec(ctx, `UPDATE orders SET locked_until = NULL WHERE id = $1`, orderID)
+	return err
+}
+
+// Reschedule снимает аренду заказа и назначает следующую проверку
+// через delay, сохраняя число выполненных проверок attempts и причину
+// неудачи последней из них. Пустой lastError очищает причину.
+func (r *OrderRepository) Reschedule(ctx context.Context, orderID int64, attempts int, delay time.Duration, lastError string) error {
+	query := `
+		UPDATE orders
+		SET locked_until = NULL,
+			attempts = $2,
+			next_check_at = NOW() + make_interval(secs => $3),
+			last_error = NULLIF($4, '')
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, orderID, attempts, delay.Seconds(), lastError)
+	return err
+}
+
+// DeadLetter снимает заказ с опроса системы начислений, сохраняя число
+// выполненных проверок и причину неудачи последней из них.
+func (r *OrderRepository) DeadLetter(ctx context.Context, orderID int64, attempts int, lastError string) error {
+	query := `
+		UPDATE orders
+		SET locked_until = NULL,
+			attempts = $2,
+			last_error = NULLIF($3, ''),
+			dead_lettered_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, orderID, attempts, lastError)
+	return err
+}
+
+// ListDeadLetters возвращает снятые с опроса и всё ещё необработанные заказы,
+// отобранные по query.
+// Период и курсор относятся ко времени снятия с опроса.
+func (r *OrderRepository) ListDeadLetters(ctx context.Context, query domain.ListQuery) ([]*domain.DeadLetterOrder, error) {
+	op, dir := keysetDirection(query.Ascending)
+	sql := fmt.Sprintf(`
+		SELECT id, user_id, number, status, accrual, uploaded_at, attempts,
+			COALESCE(last_error, ''), dead_lettered_at
+		FROM orders
+		WHERE dead_lettered_at IS NOT NULL
+			AND status IN ($6, $7)
+			AND ($1::timestamptz IS NULL OR dead_lettered_at >= $1)
+			AND ($2::timestamptz IS NULL OR dead_lettered_at < $2)
+			AND ($3::timestamptz IS NULL OR (dead_lettered_at, id) %[1]s ($3, $4::bigint))
+		ORDER BY dead_lettered_at %[2]s, id %[2]s
+		LIMIT $5
+	`, op, dir)
+
+	from, to, cursorTime, cursorID, limit := listArgs(query)
+	rows, err := r.db.Query(ctx, sql, from, to, cursorTime, cursorID, limit,
+		domain.OrderStatusNew, domain.OrderStatusProcessing)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*domain.DeadLetterOrder
+	for rows.Next() {
+		var order domain.Order
+		deadLetter := domain.DeadLetterOrder{Order: &order}
+		err := rows.Scan(
+			&order.ID,
+			&order.UserID,
+			&order.Number,
+			&order.Status,
+			&order.Accrual,
+			&order.UploadedAt,
+			&order.Attempts,
+			&deadLetter.LastError,
+			&deadLetter.DeadLetteredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, &deadLetter)
+	}
+
+	return orders, rows.Err()
+}
+
+// Requeue возвращает снятый с опроса заказ в опрос с начала расписания
+// проверок. Если такого заказа нет, возвращается domain.ErrOrderNotFound.
+func (r *OrderRepository) Requeue(ctx context.Context, number string) (*domain.Order, error) {
 	query := `
 		UPDATE orders
-		SET status = $1, accrual = $2
-		WHERE number = $3
+		SET dead_lettered_at = NULL,
+			last_error = NULL,
+			attempts = 0,
+			next_check_at = NOW()
+		WHERE number = $1 AND dead_lettered_at IS NOT NULL AND status IN ($2, $3)
+		RETURNING id, user_id, number, status, accrual, uploaded_at, attempts
 	`
 
-	result, err := r.pool.Exec(ctx, query, status, accrual, number)
+	var order domain.Order
+	err := r.db.QueryRow(ctx, query, number, domain.OrderStatusNew, domain.OrderStatusProcessing).Scan(
+		&order.ID,
+		&order.UserID,
+		&order.Number,
+		&order.Status,
+		&order.Accrual,
+		&order.UploadedAt,
+		&order.Attempts,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// UpdateStatus обновляет статус и начисление заказа orderID.
+// Заказ в конечном статусе не изменяется: возвращается domain.ErrOrderAlreadyFinal.
+// Если статус или начисление изменились, переход записывается в историю статусов.
+func (r *OrderRepository) UpdateStatus(ctx context.Context, orderID int64, status domain.OrderStatus, accrual *decimal.Decimal) error {
+	query := `
+		WITH current AS (
+			SELECT id, status, accrual
+			FROM orders
+			WHERE id = $3
+			FOR UPDATE
+		), updated AS (
+			UPDATE orders o
+			SET status = $1, accrual = $2
+			FROM current c
+			WHERE o.id = c.id AND c.status NOT IN ($4, $5, $6)
+			RETURNING o.id, c.status AS prev_status, c.accrual AS prev_accrual
+		), history AS (
+			INSERT INTO order_status_history (order_id, status, accrual)
+			SELECT id, $1, $2
+			FROM updated
+			WHERE prev_status <> $1 OR prev_accrual IS DISTINCT FROM $2::numeric
+		)
+		SELECT
+			EXISTS (SELECT 1 FROM updated),
+			EXISTS (SELECT 1 FROM current)
+	`
+
+	var updated, exists bool
+	err := r.db.QueryRow(ctx, query,
+		status, accrual, orderID,
+		domain.OrderStatusInvalid, domain.OrderStatusProcessed, domain.OrderStatusCanceled,
+	).Scan(&updated, &exists)
 	if err != nil {
 		return err
 	}
 
-	if result.RowsAffected() == 0 {
+	if !exists {
 		return domain.ErrOrderNotFound
 	}
+	if !updated {
+		return domain.ErrOrderAlreadyFinal
+	}
 
 	return nil
 }
+
+// Reopen переводит заказ orderID, в том числе в конечном статусе, обратно в PROCESSING
+// без начисления, чтобы его можно было перепроверить, и записывает переход
+// в историю статусов. Расписание проверок заказа начинается заново.
+// Возвращает заказ в состоянии до перевода. Отменённый или отсутствующий заказ возвращает domain.ErrOrderNotFound.
+func (r *OrderRepository) Reopen(ctx context.Context, orderID int64) (*domain.Order, error) {
+	query := `
+		WITH current AS (
+			SELECT id, user_id, number, status, accrual, uploaded_at
+			FROM orders
+			WHERE id = $1 AND status <> $3
+			FOR UPDATE
+		), updated AS (
+			UPDATE orders o
+			SET status = $2, accrual = NULL, attempts = 0, next_check_at = NOW(),
+				last_error = NULL, dead_lettered_at = NULL
+			FROM current c
+			WHERE o.id = c.id
+			RETURNING o.id
+		), history AS (
+			INSERT INTO order_status_history (order_id, status)
+			SELECT id, $2 FROM updated
+		)
+		SELECT id, user_id, number, status, accrual, uploaded_at FROM current
+	`
+
+	var order domain.Order
+	err := r.db.QueryRow(ctx, query, orderID, domain.OrderStatusProcessing, domain.OrderStatusCanceled).Scan(
+		&order.ID,
+		&order.UserID,
+		&order.Number,
+		&order.Status,
+		&order.Accrual,
+		&order.UploadedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// Cancel отменяет заказ пользователя в статусе NEW и освобождает его номер.
+// Чужой или отсутствующий заказ возвращает domain.ErrOrderNotFound,
+// заказ, обработка которого уже началась, — domain.ErrOrderNotCancelable.
+func (r *OrderRepository) Cancel(ctx context.Context, userID int64, number string) (*domain.Order, error) {
+	query := `
+		WITH updated AS (
+			UPDATE orders
+			SET status = $3
+			WHERE user_id = $1 AND number = $2 AND status = $4
+			RETURNING id, user_id, number, status, accrual, uploaded_at
+		), history AS (
+			INSERT INTO order_status_history (order_id, status)
+			SELECT id, status FROM updated
+		)
+		SELECT id, user_id, number, status, accrual, uploaded_at FROM updated
+	`
+
+	var order domain.Order
+	err := r.db.QueryRow(ctx, query, userID, number, domain.OrderStatusCanceled, domain.OrderStatusNew).Scan(
+		&order.ID,
+		&order.UserID,
+		&order.Number,
+		&order.Status,
+		&order.Accrual,
+		&order.UploadedAt,
+	)
+	if err == nil {
+		return &order, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	var exists bool
+	err = r.db.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM orders WHERE user_id = $1 AND number = $2 AND status <> $3)`,
+		userID, number, domain.OrderStatusCanceled,
+	).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, domain.ErrOrderNotCancelable
+	}
+	return nil, domain.ErrOrderNotFound
+}
+
+// GetStatusHistory возвращает историю статусов заказа в хронологическом порядке.
+func (r *OrderRepository) GetStatusHistory(ctx context.Context, orderID int64) ([]*domain.OrderStatusChange, error) {
+	query := `
+		SELECT status, accrual, changed_at
+		FROM order_status_history
+		WHERE order_id = $1
+		ORDER BY changed_at ASC, id ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*domain.OrderStatusChange
+	for rows.Next() {
+		var change domain.OrderStatusChange
+		if err := rows.Scan(&change.Status, &change.Accrual, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, &change)
+	}
+
+	return history, rows.Err()
+}
@@ -4,7 +4,9 @@ package postgres_test
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/arvaliullin/gophermart/internal/repository/postgres"
@@ -42,6 +44,98 @@ func TestOrderRepository_Create(t *testing.T) {
 	})
 }
 
+func TestOrderRepository_CreateBatch(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	orderRepo := postgres.NewOrderRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "batchuser", "password")
+	require.NoError(t, err)
+	other, err := userRepo.Create(ctx, "batchother", "password")
+	require.NoError(t, err)
+
+	_, err = orderRepo.Create(ctx, user.ID, "12345678903")
+	require.NoError(t, err)
+	_, err = orderRepo.Create(ctx, other.ID, "79927398713")
+	require.NoError(t, err)
+
+	submissions, err := orderRepo.CreateBatch(ctx, user.ID,
+		[]string{"12345678903", "79927398713", "2377225624", "2377225624"})
+	require.NoError(t, err)
+
+	results := make(map[string]domain.OrderSubmitResult, len(submissions))
+	for _, submission := range submissions {
+		results[submission.Number] = submission.Result
+	}
+	assert.Equal(t, map[string]domain.OrderSubmitResult{
+		"12345678903": domain.OrderSubmitAlreadyUploaded,
+		"79927398713": domain.OrderSubmitBelongsToOther,
+		"2377225624":  domain.OrderSubmitAccepted,
+	}, results)
+
+	created, err := orderRepo.GetByNumber(ctx, "2377225624")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, created.UserID)
+	assert.Equal(t, domain.OrderStatusNew, created.Status)
+}
+
+func TestOrderRepository_List(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	orderRepo := postgres.NewOrderRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "listuser", "password")
+	require.NoError(t, err)
+
+	numbers := []string{"12345678903", "79927398713", "2377225624"}
+	created := make(map[string]int64, len(numbers))
+	for _, number := range numbers {
+		order, err := orderRepo.Create(ctx, user.ID, number)
+		require.NoError(t, err)
+		created[number] = order.ID
+	}
+	require.NoError(t, orderRepo.UpdateStatus(ctx, created["79927398713"], domain.OrderStatusProcessing, nil))
+
+	t.Run("постраничная выборка от новых к старым", func(t *testing.T) {
+		first, err := orderRepo.List(ctx, user.ID, domain.OrderListQuery{ListQuery: domain.ListQuery{Limit: 2}})
+		require.NoError(t, err)
+		require.Len(t, first, 2)
+		assert.Equal(t, "2377225624", first[0].Number)
+		assert.Equal(t, "79927398713", first[1].Number)
+
+		cursor := &domain.PageCursor{Time: first[1].UploadedAt, ID: first[1].ID}
+		second, err := orderRepo.List(ctx, user.ID, domain.OrderListQuery{ListQuery: domain.ListQuery{Limit: 2, Cursor: cursor}})
+		require.NoError(t, err)
+		require.Len(t, second, 1)
+		assert.Equal(t, "12345678903", second[0].Number)
+	})
+
+	t.Run("сортировка по возрастанию", func(t *testing.T) {
+		orders, err := orderRepo.List(ctx, user.ID, domain.OrderListQuery{ListQuery: domain.ListQuery{Ascending: true}})
+		require.NoError(t, err)
+		require.Len(t, orders, 3)
+		assert.Equal(t, "12345678903", orders[0].Number)
+	})
+
+	t.Run("фильтр по статусу", func(t *testing.T) {
+		orders, err := orderRepo.List(ctx, user.ID, domain.OrderListQuery{
+			Statuses: []domain.OrderStatus{domain.OrderStatusProcessing},
+		})
+		require.NoError(t, err)
+		require.Len(t, orders, 1)
+		assert.Equal(t, "79927398713", orders[0].Number)
+	})
+
+	t.Run("фильтр по периоду", func(t *testing.T) {
+		future := time.Now().Add(time.Hour)
+		orders, err := orderRepo.List(ctx, user.ID, domain.OrderListQuery{ListQuery: domain.ListQuery{From: &future}})
+		require.NoError(t, err)
+		assert.Empty(t, orders)
+	})
+}
+
 func TestOrderRepository_GetByNumber(t *testing.T) {
 	setupTest(t)
 	ctx := context.Background()
@@ -111,59 +205,373 @@ func TestOrderRepository_GetPendingOrders(t *testing.T) {
 	t.Run("получение необработанных заказов", func(t *testing.T) {
 		_, err := orderRepo.Create(ctx, user.ID, "4444444444")
 		require.NoError(t, err)
-		_, err = orderRepo.Create(ctx, user.ID, "5555555555")
+		processed, err := orderRepo.Create(ctx, user.ID, "5555555555")
 		require.NoError(t, err)
 
 		accrual := decimal.NewFromFloat(100.0)
-		err = orderRepo.UpdateStatus(ctx, "5555555555", domain.OrderStatusProcessed, &accrual)
+		err = orderRepo.UpdateStatus(ctx, processed.ID, domain.OrderStatusProcessed, &accrual)
 		require.NoError(t, err)
 
-		pending, err := orderRepo.GetPendingOrders(ctx)
+		pending, err := orderRepo.GetPendingOrders(ctx, 0, time.Minute)
 		require.NoError(t, err)
 		assert.Len(t, pending, 1)
 		assert.Equal(t, "4444444444", pending[0].Number)
+
+		require.NoError(t, orderRepo.ReleaseLease(ctx, pending[0].ID))
+	})
+
+	t.Run("ограничение размера пакета", func(t *testing.T) {
+		_, err := orderRepo.Create(ctx, user.ID, "6666666666")
+		require.NoError(t, err)
+
+		pending, err := orderRepo.GetPendingOrders(ctx, 1, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, "4444444444", pending[0].Number)
+
+		rest, err := orderRepo.GetPendingOrders(ctx, 0, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, rest, 1)
+		assert.Equal(t, "6666666666", rest[0].Number)
+
+		require.NoError(t, orderRepo.ReleaseLease(ctx, pending[0].ID))
+		require.NoError(t, orderRepo.ReleaseLease(ctx, rest[0].ID))
+	})
+
+	t.Run("арендованные заказы не выдаются повторно", func(t *testing.T) {
+		pending, err := orderRepo.GetPendingOrders(ctx, 0, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, pending, 2)
+
+		again, err := orderRepo.GetPendingOrders(ctx, 0, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, again)
+
+		require.NoError(t, orderRepo.ReleaseLease(ctx, pending[0].ID))
+
+		released, err := orderRepo.GetPendingOrders(ctx, 0, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, released, 1)
+		assert.Equal(t, pending[0].ID, released[0].ID)
+
+		require.NoError(t, orderRepo.ReleaseLease(ctx, pending[0].ID))
+		require.NoError(t, orderRepo.ReleaseLease(ctx, pending[1].ID))
+	})
+
+	t.Run("аренда отдельного заказа", func(t *testing.T) {
+		pending, err := orderRepo.GetPendingOrders(ctx, 1, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+
+		err = orderRepo.ClaimLease(ctx, pending[0].ID, time.Minute)
+		assert.ErrorIs(t, err, domain.ErrOrderLocked)
+
+		require.NoError(t, orderRepo.ReleaseLease(ctx, pending[0].ID))
+		require.NoError(t, orderRepo.ClaimLease(ctx, pending[0].ID, time.Minute))
+
+		again, err := orderRepo.GetPendingOrders(ctx, 0, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, again, 1)
+		assert.NotEqual(t, pending[0].ID, again[0].ID)
+
+		require.NoError(t, orderRepo.ReleaseLease(ctx, pending[0].ID))
+		require.NoError(t, orderRepo.ReleaseLease(ctx, again[0].ID))
+
+		err = orderRepo.ClaimLease(ctx, pending[0].ID+1000, time.Minute)
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	})
+
+	t.Run("истёкшая аренда освобождает заказ", func(t *testing.T) {
+		pending, err := orderRepo.GetPendingOrders(ctx, 0, 10*time.Millisecond)
+		require.NoError(t, err)
+		require.Len(t, pending, 2)
+
+		time.Sleep(50 * time.Millisecond)
+
+		reclaimed, err := orderRepo.GetPendingOrders(ctx, 0, time.Minute)
+		require.NoError(t, err)
+		assert.Len(t, reclaimed, 2)
+
+		for _, order := range reclaimed {
+			require.NoError(t, orderRepo.ReleaseLease(ctx, order.ID))
+		}
+	})
+
+	t.Run("параллельные выборки не пересекаются", func(t *testing.T) {
+		for _, number := range []string{"7777777777", "8888888888", "9999999999", "1111111111"} {
+			_, err := orderRepo.Create(ctx, user.ID, number)
+			require.NoError(t, err)
+		}
+
+		const instances = 4
+		claimed := make([][]*domain.Order, instances)
+		var wg sync.WaitGroup
+		for i := range instances {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				orders, err := orderRepo.GetPendingOrders(ctx, 2, time.Minute)
+				assert.NoError(t, err)
+				claimed[i] = orders
+			}()
+		}
+		wg.Wait()
+
+		seen := make(map[int64]bool)
+		for _, orders := range claimed {
+			for _, order := range orders {
+				assert.False(t, seen[order.ID], "заказ %s выдан дважды", order.Number)
+				seen[order.ID] = true
+			}
+		}
+		assert.Len(t, seen, 6)
 	})
 }
 
-func TestOrderRepository_UpdateStatus(t *testing.T) {
+func TestOrderRepository_Reschedule(t *testing.T) {
 	setupTest(t)
 	ctx := context.Background()
 	userRepo := postgres.NewUserRepository(testPool)
 	orderRepo := postgres.NewOrderRepository(testPool)
 
-	user, err := userRepo.Create(ctx, "updatestat", "password")
+	user, err := userRepo.Create(ctx, "reschedule", "password")
 	require.NoError(t, err)
 
-	t.Run("успешное обновление статуса", func(t *testing.T) {
-		_, err := orderRepo.Create(ctx, user.ID, "6666666666")
+	created, err := orderRepo.Create(ctx, user.ID, "12345678903")
+	require.NoError(t, err)
+
+	t.Run("новый заказ проверяется сразу", func(t *testing.T) {
+		pending, err := orderRepo.GetPendingOrders(ctx, 0, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, 0, pending[0].Attempts)
+	})
+
+	t.Run("отложенный заказ не выдаётся до срока", func(t *testing.T) {
+		require.NoError(t, orderRepo.Reschedule(ctx, created.ID, 1, time.Hour, ""))
+
+		pending, err := orderRepo.GetPendingOrders(ctx, 0, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	t.Run("наступивший срок возвращает заказ с числом проверок", func(t *testing.T) {
+		require.NoError(t, orderRepo.Reschedule(ctx, created.ID, 2, 0, "timeout"))
+
+		pending, err := orderRepo.GetPendingOrders(ctx, 0, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, created.ID, pending[0].ID)
+		assert.Equal(t, 2, pending[0].Attempts)
+	})
+}
+
+func TestOrderRepository_DeadLetter(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	orderRepo := postgres.NewOrderRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "deadletter", "password")
+	require.NoError(t, err)
+
+	stuck, err := orderRepo.Create(ctx, user.ID, "12345678903")
+	require.NoError(t, err)
+	_, err = orderRepo.Create(ctx, user.ID, "79927398713")
+	require.NoError(t, err)
+
+	t.Run("снятый с опроса заказ не выдаётся", func(t *testing.T) {
+		require.NoError(t, orderRepo.DeadLetter(ctx, stuck.ID, 10, "заказ не зарегистрирован"))
+
+		pending, err := orderRepo.GetPendingOrders(ctx, 0, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, "79927398713", pending[0].Number)
+		require.NoError(t, orderRepo.ReleaseLease(ctx, pending[0].ID))
+	})
+
+	t.Run("список снятых с опроса заказов", func(t *testing.T) {
+		orders, err := orderRepo.ListDeadLetters(ctx, domain.ListQuery{})
+		require.NoError(t, err)
+		require.Len(t, orders, 1)
+		assert.Equal(t, stuck.ID, orders[0].Order.ID)
+		assert.Equal(t, domain.OrderStatusNew, orders[0].Order.Status)
+		assert.Equal(t, 10, orders[0].Order.Attempts)
+		assert.Equal(t, "заказ не зарегистрирован", orders[0].LastError)
+		assert.False(t, orders[0].DeadLetteredAt.IsZero())
+	})
+
+	t.Run("возврат в опрос", func(t *testing.T) {
+		requeued, err := orderRepo.Requeue(ctx, "12345678903")
+		require.NoError(t, err)
+		assert.Equal(t, stuck.ID, requeued.ID)
+		assert.Equal(t, 0, requeued.Attempts)
+
+		orders, err := orderRepo.ListDeadLetters(ctx, domain.ListQuery{})
+		require.NoError(t, err)
+		assert.Empty(t, orders)
+
+		pending, err := orderRepo.GetPendingOrders(ctx, 0, time.Minute)
+		require.NoError(t, err)
+		assert.Len(t, pending, 2)
+	})
+
+	t.Run("заказ не в очереди снятых", func(t *testing.T) {
+		_, err := orderRepo.Requeue(ctx, "12345678903")
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	})
+}
+
+func TestOrderRepository_Cancel(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	orderRepo := postgres.NewOrderRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "canceluser", "password")
+	require.NoError(t, err)
+	other, err := userRepo.Create(ctx, "cancelother", "password")
+	require.NoError(t, err)
+
+	t.Run("отмена освобождает номер", func(t *testing.T) {
+		created, err := orderRepo.Create(ctx, user.ID, "12345678903")
+		require.NoError(t, err)
+
+		canceled, err := orderRepo.Cancel(ctx, user.ID, "12345678903")
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, canceled.ID)
+		assert.Equal(t, domain.OrderStatusCanceled, canceled.Status)
+
+		pending, err := orderRepo.GetPendingOrders(ctx, 0, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+
+		err = orderRepo.UpdateStatus(ctx, created.ID, domain.OrderStatusProcessing, nil)
+		assert.ErrorIs(t, err, domain.ErrOrderAlreadyFinal)
+
+		reuploaded, err := orderRepo.Create(ctx, other.ID, "12345678903")
+		require.NoError(t, err)
+
+		found, err := orderRepo.GetByNumber(ctx, "12345678903")
 		require.NoError(t, err)
+		assert.Equal(t, reuploaded.ID, found.ID)
 
-		accrual := decimal.NewFromFloat(250.50)
-		err = orderRepo.UpdateStatus(ctx, "6666666666", domain.OrderStatusProcessed, &accrual)
+		require.NoError(t, orderRepo.UpdateStatus(ctx, reuploaded.ID, domain.OrderStatusProcessing, nil))
+		previous, err := orderRepo.Reopen(ctx, reuploaded.ID)
 		require.NoError(t, err)
+		assert.Equal(t, reuploaded.ID, previous.ID)
 
-		updated, err := orderRepo.GetByNumber(ctx, "6666666666")
+		submissions, err := orderRepo.CreateBatch(ctx, user.ID, []string{"12345678903"})
 		require.NoError(t, err)
-		assert.Equal(t, domain.OrderStatusProcessed, updated.Status)
-		require.NotNil(t, updated.Accrual)
-		assert.True(t, decimal.NewFromFloat(250.50).Equal(*updated.Accrual))
+		require.Len(t, submissions, 1)
+		assert.Equal(t, domain.OrderSubmitBelongsToOther, submissions[0].Result)
+
+		history, err := orderRepo.GetStatusHistory(ctx, created.ID)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, domain.OrderStatusCanceled, history[1].Status)
+	})
+
+	t.Run("заказ в обработке нельзя отменить", func(t *testing.T) {
+		order, err := orderRepo.Create(ctx, user.ID, "79927398713")
+		require.NoError(t, err)
+		require.NoError(t, orderRepo.UpdateStatus(ctx, order.ID, domain.OrderStatusProcessing, nil))
+
+		_, err = orderRepo.Cancel(ctx, user.ID, "79927398713")
+		assert.ErrorIs(t, err, domain.ErrOrderNotCancelable)
 	})
 
-	t.Run("обновление статуса на INVALID без начисления", func(t *testing.T) {
-		_, err := orderRepo.Create(ctx, user.ID, "7777777777")
+	t.Run("чужой заказ не найден", func(t *testing.T) {
+		_, err := orderRepo.Create(ctx, user.ID, "2377225624")
+		require.NoError(t, err)
+
+		_, err = orderRepo.Cancel(ctx, other.ID, "2377225624")
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	})
+}
+
+func TestOrderRepository_Reopen(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	orderRepo := postgres.NewOrderRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "reopenuser", "password")
+	require.NoError(t, err)
+
+	t.Run("заказ в конечном статусе открывается для перепроверки", func(t *testing.T) {
+		created, err := orderRepo.Create(ctx, user.ID, "12345678903")
+		require.NoError(t, err)
+		accrual := decimal.NewFromFloat(100.0)
+		require.NoError(t, orderRepo.UpdateStatus(ctx, created.ID, domain.OrderStatusProcessed, &accrual))
+
+		previous, err := orderRepo.Reopen(ctx, created.ID)
 		require.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusProcessed, previous.Status)
+		require.NotNil(t, previous.Accrual)
 
-		err = orderRepo.UpdateStatus(ctx, "7777777777", domain.OrderStatusInvalid, nil)
+		reopened, err := orderRepo.GetByNumber(ctx, "12345678903")
 		require.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusProcessing, reopened.Status)
+		assert.Nil(t, reopened.Accrual)
 
-		updated, err := orderRepo.GetByNumber(ctx, "7777777777")
+		history, err := orderRepo.GetStatusHistory(ctx, created.ID)
 		require.NoError(t, err)
-		assert.Equal(t, domain.OrderStatusInvalid, updated.Status)
-		assert.Nil(t, updated.Accrual)
+		require.Len(t, history, 3)
+		assert.Equal(t, domain.OrderStatusProcessing, history[2].Status)
 	})
 
-	t.Run("ошибка при обновлении несуществующего заказа", func(t *testing.T) {
-		err := orderRepo.UpdateStatus(ctx, "0000000000", domain.OrderStatusProcessed, nil)
+	t.Run("отменённый заказ не открывается", func(t *testing.T) {
+		created, err := orderRepo.Create(ctx, user.ID, "79927398713")
+		require.NoError(t, err)
+		_, err = orderRepo.Cancel(ctx, user.ID, "79927398713")
+		require.NoError(t, err)
+
+		_, err = orderRepo.Reopen(ctx, created.ID)
 		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
 	})
 }
+
+func TestOrderRepository_GetStatusHistory(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	orderRepo := postgres.NewOrderRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "historyuser", "password")
+	require.NoError(t, err)
+
+	t.Run("записывает только фактические переходы", func(t *testing.T) {
+		order, err := orderRepo.Create(ctx, user.ID, "12345678903")
+		require.NoError(t, err)
+
+		require.NoError(t, orderRepo.UpdateStatus(ctx, order.ID, domain.OrderStatusProcessing, nil))
+		require.NoError(t, orderRepo.UpdateStatus(ctx, order.ID, domain.OrderStatusProcessing, nil))
+		accrual := decimal.NewFromFloat(150.0)
+		require.NoError(t, orderRepo.UpdateStatus(ctx, order.ID, domain.OrderStatusProcessed, &accrual))
+
+		history, err := orderRepo.GetStatusHistory(ctx, order.ID)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		assert.Equal(t, domain.OrderStatusNew, history[0].Status)
+		assert.True(t, order.UploadedAt.Equal(history[0].ChangedAt))
+		assert.Equal(t, domain.OrderStatusProcessing, history[1].Status)
+		assert.Nil(t, history[1].Accrual)
+		assert.Equal(t, domain.OrderStatusProcessed, history[2].Status)
+		require.NotNil(t, history[2].Accrual)
+		assert.True(t, accrual.Equal(*history[2].Accrual))
+	})
+
+	t.Run("пакетная загрузка открывает историю", func(t *testing.T) {
+		_, err := orderRepo.CreateBatch(ctx, user.ID, []string{"79927398713"})
+		require.NoError(t, err)
+
+		order, err := orderRepo.GetByNumber(ctx, "79927398713")
+		require.NoError(t, err)
+
+		history, err := orderRepo.GetStatusHistory(ctx, order.ID)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, domain.OrderStatusNew, history[0].Status)
+	})
+}
@@ -26,6 +26,17 @@ var connectionErrorCodes = map[string]struct{}{
 	pgerrcode.ProtocolViolation:                             {},
 }
 
+// dbtx описывает операции, общие для пула соединений и транзакции.
+//
+// Репозитории, работающие через dbtx, могут использоваться как напрямую с пулом,
+// так и внутри транзакции UnitOfWork.
+type dbtx interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 // DB представляет пул соединений с PostgreSQL.
 type DB struct {
 	Pool *pgxpool.Pool
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ReconciliationRepository реализует интерфейс ports.ReconciliationRepository для PostgreSQL.
+type ReconciliationRepository struct {
+	db dbtx
+}
+
+// NewReconciliationRepository создаёт новый репозиторий сверки балансов.
+func NewReconciliationRepository(pool *pgxpool.Pool) *ReconciliationRepository {
+	return &ReconciliationRepository{db: pool}
+}
+
+// mismatchQuery рассчитывает ожидаемые и фактические значения баланса.
+//
+// Расхождение остатка равно разнице начислений по заказам и проводок
+// ACCRUAL/ACCRUAL_CORRECTION за вычетом разницы списаний и проводок WITHDRAWAL/REFUND. Уже записанные
+// проводки RECONCILIATION погашают расхождение, поэтому исправленный
+// пользователь в отчёт больше не попадает. $1 ограничивает выборку одним
+// пользователем, NULL — все пользователи.
+const mismatchQuery = `
+	WITH ledger AS (
+		SELECT user_id,
+			SUM(amount) AS total,
+			COALESCE(SUM(amount) FILTER (WHERE kind IN ($2, $7)), 0) AS accrued,
+			COALESCE(-SUM(amount) FILTER (WHERE kind IN ($3, $4)), 0) AS withdrawn,
+			COALESCE(SUM(amount) FILTER (WHERE kind = $5), 0) AS reconciled
+		FROM ledger_entries
+		WHERE $1::bigint IS NULL OR user_id = $1
+		GROUP BY user_id
+	), accrued AS (
+		SELECT user_id, SUM(accrual) AS total
+		FROM orders
+		WHERE status = $6 AND accrual > 0 AND ($1::bigint IS NULL OR user_id = $1)
+		GROUP BY user_id
+	), withdrawn AS (
+		SELECT user_id, SUM(sum) AS total
+		FROM (
+			SELECT user_id, sum FROM withdrawals
+			UNION ALL
+			SELECT user_id, -sum FROM withdrawal_refunds
+		) s
+		WHERE $1::bigint IS NULL OR user_id = $1
+		GROUP BY user_id
+	), totals AS (
+		SELECT u.id AS user_id,
+			COALESCE(l.total, 0) AS actual_current,
+			COALESCE(l.total, 0)
+				+ (COALESCE(a.total, 0) - COALESCE(l.accrued, 0))
+				- (COALESCE(w.total, 0) - COALESCE(l.withdrawn, 0))
+				- COALESCE(l.reconciled, 0) AS expected_current,
+			COALESCE(l.withdrawn, 0) AS actual_withdrawn,
+			COALESCE(w.total, 0) AS expected_withdrawn
+		FROM users u
+		LEFT JOIN ledger l ON l.user_id = u.id
+		LEFT JOIN accrued a ON a.user_id = u.id
+		LEFT JOIN withdrawn w ON w.user_id = u.id
+		WHERE $1::bigint IS NULL OR u.id = $1
+	)
+	SELECT user_id, expected_current, actual_current, expected_withdrawn, actual_withdrawn
+	FROM totals
+	WHERE expected_current <> actual_current OR expected_withdrawn <> actual_withdrawn
+	ORDER BY user_id
+`
+
+// FindMismatches возвращает пользователей, чей журнал проводок расходится
+// с заказами и списаниями.
+func (r *ReconciliationRepository) FindMismatches(ctx context.Context) ([]*domain.BalanceMismatch, error) {
+	return findMismatches(ctx, r.db, nil)
+}
+
+// Repair исправляет расхождение остатка пользователя проводкой RECONCILIATION.
+//
+// Расхождение пересчитывается под блокировкой строки счёта, поэтому
+// параллельные операции не приводят к двойной корректировке. Расхождение
+// суммы списанных баллов проводкой не исправляется и остаётся в отчёте.
+// Внутри UnitOfWork исправление выполняется в точке сохранения и
+// фиксируется вместе с внешней транзакцией.
+func (r *ReconciliationRepository) Repair(ctx context.Context, userID int64) (*domain.LedgerEntry, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		SELECT user_id FROM balances WHERE user_id = $1 FOR UPDATE
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	mismatches, err := findMismatches(ctx, tx, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(mismatches) == 0 || mismatches[0].CurrentDiff().IsZero() {
+		return nil, nil
+	}
+
+	mismatch := mismatches[0]
+	description := fmt.Sprintf("сверка баланса: ожидаемый остаток %s, фактический %s",
+		mismatch.ExpectedCurrent.StringFixed(2), mismatch.ActualCurrent.StringFixed(2))
+
+	entry, err := addAdjustment(ctx, tx, userID, domain.LedgerEntryReconciliation, mismatch.CurrentDiff(), description)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func findMismatches(ctx context.Context, db dbtx, userID *int64) ([]*domain.BalanceMismatch, error) {
+	rows, err := db.Query(ctx, mismatchQuery,
+		userID,
+		domain.LedgerEntryAccrual,
+		domain.LedgerEntryWithdrawal,
+		domain.LedgerEntryRefund,
+		domain.LedgerEntryReconciliation,
+		domain.OrderStatusProcessed,
+		domain.LedgerEntryAccrualCorrection,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mismatches []*domain.BalanceMismatch
+	for rows.Next() {
+		var m domain.BalanceMismatch
+		err := rows.Scan(
+			&m.UserID,
+			&m.ExpectedCurrent,
+			&m.ActualCurrent,
+			&m.ExpectedWithdrawn,
+			&m.ActualWithdrawn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		mismatches = append(mismatches, &m)
+	}
+
+	return mismatches, rows.Err()
+}
@@ -0,0 +1,86 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/arvaliullin/gophermart/internal/repository/postgres"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconciliationRepository(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	orderRepo := postgres.NewOrderRepository(testPool)
+	balanceRepo := postgres.NewBalanceRepository(testPool)
+	reconRepo := postgres.NewReconciliationRepository(testPool)
+
+	consistent, err := userRepo.Create(ctx, "consistent", "password")
+	require.NoError(t, err)
+	consistentOrder, err := orderRepo.Create(ctx, consistent.ID, "12345678903")
+	require.NoError(t, err)
+	accrual := decimal.NewFromFloat(100.0)
+	require.NoError(t, orderRepo.UpdateStatus(ctx, consistentOrder.ID, domain.OrderStatusProcessed, &accrual))
+	require.NoError(t, balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: consistent.ID, OrderNumber: "12345678903", Amount: accrual}))
+
+	// Заказ обработан, но начисление в журнал не попало.
+	drifted, err := userRepo.Create(ctx, "drifted", "password")
+	require.NoError(t, err)
+	driftedOrder, err := orderRepo.Create(ctx, drifted.ID, "79927398713")
+	require.NoError(t, err)
+	require.NoError(t, orderRepo.UpdateStatus(ctx, driftedOrder.ID, domain.OrderStatusProcessed, &accrual))
+
+	t.Run("находит расхождение остатка", func(t *testing.T) {
+		mismatches, err := reconRepo.FindMismatches(ctx)
+		require.NoError(t, err)
+		require.Len(t, mismatches, 1)
+		assert.Equal(t, drifted.ID, mismatches[0].UserID)
+		assert.True(t, decimal.NewFromFloat(100.0).Equal(mismatches[0].ExpectedCurrent))
+		assert.True(t, decimal.Zero.Equal(mismatches[0].ActualCurrent))
+	})
+
+	t.Run("исправление откатывается вместе с единицей работы", func(t *testing.T) {
+		errRollback := errors.New("откат")
+		err := postgres.NewUnitOfWork(testPool).Do(ctx, func(ctx context.Context, tx ports.TxRepositories) error {
+			entry, err := tx.Reconciliation().Repair(ctx, drifted.ID)
+			require.NoError(t, err)
+			require.NotNil(t, entry)
+			return errRollback
+		})
+		assert.ErrorIs(t, err, errRollback)
+
+		mismatches, err := reconRepo.FindMismatches(ctx)
+		require.NoError(t, err)
+		assert.Len(t, mismatches, 1)
+	})
+
+	t.Run("исправляет расхождение проводкой RECONCILIATION", func(t *testing.T) {
+		entry, err := reconRepo.Repair(ctx, drifted.ID)
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+		assert.Equal(t, domain.LedgerEntryReconciliation, entry.Kind)
+		assert.True(t, decimal.NewFromFloat(100.0).Equal(entry.Amount))
+
+		balance, err := balanceRepo.GetByUserID(ctx, drifted.ID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(100.0).Equal(balance.Current))
+
+		mismatches, err := reconRepo.FindMismatches(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, mismatches)
+	})
+
+	t.Run("повторное исправление ничего не записывает", func(t *testing.T) {
+		entry, err := reconRepo.Repair(ctx, drifted.ID)
+		require.NoError(t, err)
+		assert.Nil(t, entry)
+	})
+}
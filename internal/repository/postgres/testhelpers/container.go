@@ -73,7 +73,7 @@ func (c *PostgresContainer) CleanupTables(ctx context.Context) error {
 	}
 	defer db.Close()
 
-	tables := []string{"withdrawals", "orders", "balances", "users"}
+	tables := []string{"idempotency_keys", "withdrawal_limits", "user_tiers", "transfers", "point_lots", "ledger_entries", "point_holds", "withdrawal_refunds", "withdrawals", "order_status_history", "orders", "balances", "users"}
 	for _, table := range tables {
 		if _, err := db.ExecContext(ctx, fmt.Sprintf("TRUNCATE TABLE %s CASCADE", table)); err != nil {
 			return fmt.Errorf("очистка таблицы %s: %w", table, err)
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TierRepository реализует интерфейс ports.TierRepository для PostgreSQL.
+type TierRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTierRepository создаёт новый репозиторий уровней лояльности.
+func NewTierRepository(pool *pgxpool.Pool) *TierRepository {
+	return &TierRepository{pool: pool}
+}
+
+// GetByUserID возвращает уровень пользователя. Если уровень ещё не рассчитан,
+// возвращается domain.DefaultTier.
+func (r *TierRepository) GetByUserID(ctx context.Context, userID int64) (*domain.UserTier, error) {
+	query := `
+		SELECT user_id, tier, lifetime_accrued, updated_at
+		FROM user_tiers
+		WHERE user_id = $1
+	`
+
+	var tier domain.UserTier
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
+		&tier.UserID,
+		&tier.Tier,
+		&tier.LifetimeAccrued,
+		&tier.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &domain.UserTier{UserID: userID, Tier: domain.DefaultTier}, nil
+		}
+		return nil, err
+	}
+
+	return &tier, nil
+}
+
+// Recompute пересчитывает уровни всех пользователей по сумме начислений
+// за заказы и возвращает количество пользователей, чьи данные изменились.
+// Сумма учитывает корректировки ACCRUAL_CORRECTION после перепроверки заказов;
+// возвраты по списаниям и надбавки за уровень начислениями не считаются.
+func (r *TierRepository) Recompute(ctx context.Context, policy domain.TierPolicy) (int64, error) {
+	tiers := make([]string, len(policy))
+	thresholds := make([]string, len(policy))
+	for i, rule := range policy {
+		tiers[i] = string(rule.Tier)
+		thresholds[i] = rule.Threshold.String()
+	}
+
+	query := `
+		WITH accrued AS (
+			SELECT u.id AS user_id,
+				COALESCE(SUM(l.amount) FILTER (WHERE l.kind IN ($1, $5)), 0) AS total
+			FROM users u
+			LEFT JOIN ledger_entries l ON l.user_id = u.id
+			GROUP BY u.id
+		), ranked AS (
+			SELECT a.user_id, a.total,
+				COALESCE((
+					SELECT p.tier
+					FROM unnest($2::text[], $3::text[]) AS p(tier, threshold)
+					WHERE a.total >= p.threshold::numeric
+					ORDER BY p.threshold::numeric DESC
+					LIMIT 1
+				), $4) AS tier
+			FROM accrued a
+		)
+		INSERT INTO user_tiers (user_id, tier, lifetime_accrued, updated_at)
+		SELECT user_id, tier, total, NOW()
+		FROM ranked
+		ON CONFLICT (user_id) DO UPDATE
+		SET tier = EXCLUDED.tier,
+			lifetime_accrued = EXCLUDED.lifetime_accrued,
+			updated_at = EXCLUDED.updated_at
+		WHERE user_tiers.tier <> EXCLUDED.tier
+			OR user_tiers.lifetime_accrued <> EXCLUDED.lifetime_accrued
+	`
+
+	tag, err := r.pool.Exec(ctx, query, domain.LedgerEntryAccrual, tiers, thresholds, domain.DefaultTier,
+		domain.LedgerEntryAccrualCorrection)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
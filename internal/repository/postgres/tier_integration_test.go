@@ -0,0 +1,107 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/repository/postgres"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTierRepository(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	balanceRepo := postgres.NewBalanceRepository(testPool)
+	ledgerRepo := postgres.NewLedgerRepository(testPool)
+	tierRepo := postgres.NewTierRepository(testPool)
+
+	policy := domain.TierPolicy{
+		{Tier: domain.TierBronze, Threshold: decimal.Zero, Multiplier: decimal.NewFromInt(1)},
+		{Tier: domain.TierSilver, Threshold: decimal.NewFromInt(1000), Multiplier: decimal.RequireFromString("1.1")},
+		{Tier: domain.TierGold, Threshold: decimal.NewFromInt(5000), Multiplier: decimal.RequireFromString("1.25")},
+	}
+
+	user, err := userRepo.Create(ctx, "tieruser", "password")
+	require.NoError(t, err)
+
+	t.Run("уровень по умолчанию до пересчёта", func(t *testing.T) {
+		tier, err := tierRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.DefaultTier, tier.Tier)
+	})
+
+	t.Run("пересчёт назначает уровень по сумме начислений", func(t *testing.T) {
+		require.NoError(t, balanceRepo.AddAccrual(ctx, &domain.Accrual{
+			UserID:      user.ID,
+			OrderNumber: "12345678903",
+			Amount:      decimal.NewFromInt(1000),
+		}))
+
+		updated, err := tierRepo.Recompute(ctx, policy)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), updated)
+
+		tier, err := tierRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TierSilver, tier.Tier)
+		assert.True(t, decimal.NewFromInt(1000).Equal(tier.LifetimeAccrued))
+
+		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TierSilver, balance.Tier)
+	})
+
+	t.Run("повторный пересчёт ничего не меняет", func(t *testing.T) {
+		updated, err := tierRepo.Recompute(ctx, policy)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), updated)
+	})
+
+	t.Run("надбавка записывается отдельной проводкой", func(t *testing.T) {
+		require.NoError(t, balanceRepo.AddAccrual(ctx, &domain.Accrual{
+			UserID:      user.ID,
+			OrderNumber: "79927398713",
+			Amount:      decimal.NewFromInt(100),
+			Bonus:       decimal.NewFromInt(10),
+		}))
+
+		entries, err := ledgerRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+
+		var bonus *domain.LedgerEntry
+		for _, entry := range entries {
+			if entry.Kind == domain.LedgerEntryBonus {
+				bonus = entry
+			}
+		}
+		require.NotNil(t, bonus)
+		assert.True(t, decimal.NewFromInt(10).Equal(bonus.Amount))
+
+		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromInt(1110).Equal(balance.Current))
+	})
+
+	t.Run("отзыв начисления после перепроверки понижает уровень", func(t *testing.T) {
+		_, err := balanceRepo.SettleAccrual(ctx, &domain.Accrual{
+			UserID:      user.ID,
+			OrderNumber: "12345678903",
+			Amount:      decimal.NewFromInt(500),
+		}, "перепроверка заказа")
+		require.NoError(t, err)
+
+		_, err = tierRepo.Recompute(ctx, policy)
+		require.NoError(t, err)
+
+		tier, err := tierRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TierBronze, tier.Tier)
+		assert.True(t, decimal.NewFromInt(600).Equal(tier.LifetimeAccrued))
+	})
+}
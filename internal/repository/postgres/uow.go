@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// UnitOfWork реализует интерфейс ports.UnitOfWork на транзакциях PostgreSQL.
+type UnitOfWork struct {
+	pool *pgxpool.Pool
+}
+
+// NewUnitOfWork создаёт новый UnitOfWork поверх пула соединений.
+func NewUnitOfWork(pool *pgxpool.Pool) *UnitOfWork {
+	return &UnitOfWork{pool: pool}
+}
+
+// Do выполняет fn в одной транзакции и фиксирует её, если fn завершилась без ошибки.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context, tx ports.TxRepositories) error) error {
+	tx, err := u.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(ctx, newTxRepositories(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+type txRepositories struct {
+	orders   *OrderRepository
+	balances *BalanceRepository
+	ledger   *LedgerRepository
+	recon    *ReconciliationRepository
+}
+
+func newTxRepositories(tx pgx.Tx) *txRepositories {
+	return &txRepositories{
+		orders:   &OrderRepository{db: tx},
+		balances: &BalanceRepository{db: tx},
+		ledger:   &LedgerRepository{db: tx},
+		recon:    &ReconciliationRepository{db: tx},
+	}
+}
+
+// Orders возвращает репозиторий заказов, привязанный к транзакции.
+func (t *txRepositories) Orders() ports.OrderRepository {
+	return t.orders
+}
+
+// Balances возвращает репозиторий баланса, привязанный к транзакции.
+func (t *txRepositories) Balances() ports.BalanceRepository {
+	return t.balances
+}
+
+// Ledger возвращает репозиторий журнала проводок, привязанный к транзакции.
+func (t *txRepositories) Ledger() ports.LedgerRepository {
+	return t.ledger
+}
+
+// Reconciliation возвращает репозиторий сверки балансов, привязанный к транзакции.
+func (t *txRepositories) Reconciliation() ports.ReconciliationRepository {
+	return t.recon
+}
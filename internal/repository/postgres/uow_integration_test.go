@@ -0,0 +1,107 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/arvaliullin/gophermart/internal/repository/postgres"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitOfWork_Do(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	orderRepo := postgres.NewOrderRepository(testPool)
+	balanceRepo := postgres.NewBalanceRepository(testPool)
+	uow := postgres.NewUnitOfWork(testPool)
+
+	user, err := userRepo.Create(ctx, "uowuser", "password")
+	require.NoError(t, err)
+
+	accrual := decimal.NewFromFloat(150.0)
+	var processedID int64
+
+	t.Run("статус и начисление фиксируются вместе", func(t *testing.T) {
+		created, err := orderRepo.Create(ctx, user.ID, "12345678903")
+		require.NoError(t, err)
+		processedID = created.ID
+
+		err = uow.Do(ctx, func(ctx context.Context, tx ports.TxRepositories) error {
+			if err := tx.Orders().UpdateStatus(ctx, created.ID, domain.OrderStatusProcessed, &accrual); err != nil {
+				return err
+			}
+			return tx.Balances().AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "12345678903", Amount: accrual})
+		})
+		require.NoError(t, err)
+
+		order, err := orderRepo.GetByNumber(ctx, "12345678903")
+		require.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusProcessed, order.Status)
+
+		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, accrual.Equal(balance.Current))
+	})
+
+	t.Run("журнал проводок читается внутри транзакции", func(t *testing.T) {
+		errRollback := errors.New("откат")
+		err := uow.Do(ctx, func(ctx context.Context, tx ports.TxRepositories) error {
+			if err := tx.Balances().AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "2377225624", Amount: accrual}); err != nil {
+				return err
+			}
+			entries, err := tx.Ledger().GetByUserID(ctx, user.ID)
+			require.NoError(t, err)
+			assert.Len(t, entries, 2)
+			return errRollback
+		})
+		assert.ErrorIs(t, err, errRollback)
+
+		entries, err := postgres.NewLedgerRepository(testPool).GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("ошибка начисления откатывает смену статуса", func(t *testing.T) {
+		created, err := orderRepo.Create(ctx, user.ID, "79927398713")
+		require.NoError(t, err)
+
+		errCredit := errors.New("сбой начисления")
+		err = uow.Do(ctx, func(ctx context.Context, tx ports.TxRepositories) error {
+			if err := tx.Orders().UpdateStatus(ctx, created.ID, domain.OrderStatusProcessed, &accrual); err != nil {
+				return err
+			}
+			return errCredit
+		})
+		assert.ErrorIs(t, err, errCredit)
+
+		order, err := orderRepo.GetByNumber(ctx, "79927398713")
+		require.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusNew, order.Status)
+
+		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, accrual.Equal(balance.Current))
+	})
+
+	t.Run("повторная обработка не начисляет баллы дважды", func(t *testing.T) {
+		err := uow.Do(ctx, func(ctx context.Context, tx ports.TxRepositories) error {
+			if err := tx.Orders().UpdateStatus(ctx, processedID, domain.OrderStatusProcessed, &accrual); err != nil {
+				return err
+			}
+			return tx.Balances().AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "12345678903", Amount: accrual})
+		})
+		assert.ErrorIs(t, err, domain.ErrOrderAlreadyFinal)
+
+		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, accrual.Equal(balance.Current))
+	})
+}
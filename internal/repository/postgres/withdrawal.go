@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -17,13 +18,16 @@ func NewWithdrawalRepository(pool *pgxpool.Pool) *WithdrawalRepository {
 	return &WithdrawalRepository{pool: pool}
 }
 
-// GetByUserID возвращает все списания пользователя, отсортированные по дате.
+// GetByUserID возвращает все списания пользователя, отсортированные по дате,
+// вместе с суммой выполненных по ним возвратов.
 func (r *WithdrawalRepository) GetByUserID(ctx context.Context, userID int64) ([]*domain.Withdrawal, error) {
 	query := `
-		SELECT id, user_id, order_number, sum, processed_at
-		FROM withdrawals
-		WHERE user_id = $1
-		ORDER BY processed_at DESC
+		SELECT w.id, w.user_id, w.order_number, w.sum,
+			COALESCE((SELECT SUM(rf.sum) FROM withdrawal_refunds rf WHERE rf.withdrawal_id = w.id), 0),
+			w.processed_at
+		FROM withdrawals w
+		WHERE w.user_id = $1
+		ORDER BY w.processed_at DESC
 	`
 
 	rows, err := r.pool.Query(ctx, query, userID)
@@ -40,6 +44,89 @@ func (r *WithdrawalRepository) GetByUserID(ctx context.Context, userID int64) ([
 			&w.UserID,
 			&w.OrderNumber,
 			&w.Sum,
+			&w.Refunded,
+			&w.ProcessedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		withdrawals = append(withdrawals, &w)
+	}
+
+	return withdrawals, rows.Err()
+}
+
+// GetByOrderNumber возвращает списания пользователя, оформленные на номер
+// заказа, в хронологическом порядке вместе с суммой выполненных по ним возвратов.
+func (r *WithdrawalRepository) GetByOrderNumber(ctx context.Context, userID int64, orderNumber string) ([]*domain.Withdrawal, error) {
+	query := `
+		SELECT w.id, w.user_id, w.order_number, w.sum,
+			COALESCE((SELECT SUM(rf.sum) FROM withdrawal_refunds rf WHERE rf.withdrawal_id = w.id), 0),
+			w.processed_at
+		FROM withdrawals w
+		WHERE w.user_id = $1 AND w.order_number = $2
+		ORDER BY w.processed_at ASC, w.id ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, orderNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var withdrawals []*domain.Withdrawal
+	for rows.Next() {
+		var w domain.Withdrawal
+		err := rows.Scan(
+			&w.ID,
+			&w.UserID,
+			&w.OrderNumber,
+			&w.Sum,
+			&w.Refunded,
+			&w.ProcessedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		withdrawals = append(withdrawals, &w)
+	}
+
+	return withdrawals, rows.Err()
+}
+
+// List возвращает списания пользователя, отобранные по query, упорядоченные
+// по дате и идентификатору, вместе с суммой выполненных по ним возвратов.
+func (r *WithdrawalRepository) List(ctx context.Context, userID int64, query domain.ListQuery) ([]*domain.Withdrawal, error) {
+	op, dir := keysetDirection(query.Ascending)
+	sql := fmt.Sprintf(`
+		SELECT w.id, w.user_id, w.order_number, w.sum,
+			COALESCE((SELECT SUM(rf.sum) FROM withdrawal_refunds rf WHERE rf.withdrawal_id = w.id), 0),
+			w.processed_at
+		FROM withdrawals w
+		WHERE w.user_id = $1
+			AND ($2::timestamptz IS NULL OR w.processed_at >= $2)
+			AND ($3::timestamptz IS NULL OR w.processed_at < $3)
+			AND ($4::timestamptz IS NULL OR (w.processed_at, w.id) %[1]s ($4, $5::bigint))
+		ORDER BY w.processed_at %[2]s, w.id %[2]s
+		LIMIT $6
+	`, op, dir)
+
+	from, to, cursorTime, cursorID, limit := listArgs(query)
+	rows, err := r.pool.Query(ctx, sql, userID, from, to, cursorTime, cursorID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var withdrawals []*domain.Withdrawal
+	for rows.Next() {
+		var w domain.Withdrawal
+		err := rows.Scan(
+			&w.ID,
+			&w.UserID,
+			&w.OrderNumber,
+			&w.Sum,
+			&w.Refunded,
 			&w.ProcessedAt,
 		)
 		if err != nil {
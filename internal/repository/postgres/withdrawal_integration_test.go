@@ -5,7 +5,9 @@ package postgres_test
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/arvaliullin/gophermart/internal/repository/postgres"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
@@ -29,12 +31,12 @@ func TestWithdrawalRepository_GetByUserID(t *testing.T) {
 	})
 
 	t.Run("список списаний после операций", func(t *testing.T) {
-		err := balanceRepo.AddAccrual(ctx, user.ID, decimal.NewFromFloat(500.0))
+		err := balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "4111111111111111", Amount: decimal.NewFromFloat(500.0)})
 		require.NoError(t, err)
 
-		err = balanceRepo.Withdraw(ctx, user.ID, "1111111111", decimal.NewFromFloat(100.0))
+		err = balanceRepo.Withdraw(ctx, user.ID, "1111111111", decimal.NewFromFloat(100.0), domain.WithdrawalLimits{})
 		require.NoError(t, err)
-		err = balanceRepo.Withdraw(ctx, user.ID, "2222222222", decimal.NewFromFloat(50.0))
+		err = balanceRepo.Withdraw(ctx, user.ID, "2222222222", decimal.NewFromFloat(50.0), domain.WithdrawalLimits{})
 		require.NoError(t, err)
 
 		withdrawals, err := withdrawalRepo.GetByUserID(ctx, user.ID)
@@ -51,9 +53,9 @@ func TestWithdrawalRepository_GetByUserID(t *testing.T) {
 		user2, err := userRepo.Create(ctx, "anotheruser", "password")
 		require.NoError(t, err)
 
-		err = balanceRepo.AddAccrual(ctx, user2.ID, decimal.NewFromFloat(100.0))
+		err = balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user2.ID, OrderNumber: "5555555555554444", Amount: decimal.NewFromFloat(100.0)})
 		require.NoError(t, err)
-		err = balanceRepo.Withdraw(ctx, user2.ID, "3333333333", decimal.NewFromFloat(25.0))
+		err = balanceRepo.Withdraw(ctx, user2.ID, "3333333333", decimal.NewFromFloat(25.0), domain.WithdrawalLimits{})
 		require.NoError(t, err)
 
 		user1Withdrawals, err := withdrawalRepo.GetByUserID(ctx, user.ID)
@@ -66,3 +68,75 @@ func TestWithdrawalRepository_GetByUserID(t *testing.T) {
 		assert.Equal(t, "3333333333", user2Withdrawals[0].OrderNumber)
 	})
 }
+
+func TestWithdrawalRepository_List(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	balanceRepo := postgres.NewBalanceRepository(testPool)
+	withdrawalRepo := postgres.NewWithdrawalRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "listwithdrawals", "password")
+	require.NoError(t, err)
+
+	err = balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "4111111111111111", Amount: decimal.NewFromFloat(500.0)})
+	require.NoError(t, err)
+	for _, number := range []string{"1111111111", "2222222222", "3333333333"} {
+		err = balanceRepo.Withdraw(ctx, user.ID, number, decimal.NewFromFloat(10.0), domain.WithdrawalLimits{})
+		require.NoError(t, err)
+	}
+
+	t.Run("постраничная выборка по курсору", func(t *testing.T) {
+		first, err := withdrawalRepo.List(ctx, user.ID, domain.ListQuery{Limit: 2, Ascending: true})
+		require.NoError(t, err)
+		require.Len(t, first, 2)
+		assert.Equal(t, "1111111111", first[0].OrderNumber)
+
+		cursor := &domain.PageCursor{Time: first[1].ProcessedAt, ID: first[1].ID}
+		second, err := withdrawalRepo.List(ctx, user.ID, domain.ListQuery{Limit: 2, Ascending: true, Cursor: cursor})
+		require.NoError(t, err)
+		require.Len(t, second, 1)
+		assert.Equal(t, "3333333333", second[0].OrderNumber)
+	})
+
+	t.Run("учитывает период", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		withdrawals, err := withdrawalRepo.List(ctx, user.ID, domain.ListQuery{To: &past})
+		require.NoError(t, err)
+		assert.Empty(t, withdrawals)
+	})
+}
+
+func TestWithdrawalRepository_GetByOrderNumber(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	balanceRepo := postgres.NewBalanceRepository(testPool)
+	withdrawalRepo := postgres.NewWithdrawalRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "orderwithdrawals", "password")
+	require.NoError(t, err)
+
+	err = balanceRepo.AddAccrual(ctx, &domain.Accrual{UserID: user.ID, OrderNumber: "4111111111111111", Amount: decimal.NewFromFloat(500.0)})
+	require.NoError(t, err)
+	err = balanceRepo.Withdraw(ctx, user.ID, "1111111111", decimal.NewFromFloat(100.0), domain.WithdrawalLimits{})
+	require.NoError(t, err)
+	err = balanceRepo.Withdraw(ctx, user.ID, "2222222222", decimal.NewFromFloat(50.0), domain.WithdrawalLimits{})
+	require.NoError(t, err)
+
+	t.Run("возвращает списания по номеру заказа", func(t *testing.T) {
+		withdrawals, err := withdrawalRepo.GetByOrderNumber(ctx, user.ID, "1111111111")
+		require.NoError(t, err)
+		require.Len(t, withdrawals, 1)
+		assert.True(t, decimal.NewFromFloat(100.0).Equal(withdrawals[0].Sum))
+	})
+
+	t.Run("не возвращает списания другого пользователя", func(t *testing.T) {
+		other, err := userRepo.Create(ctx, "otherwithdrawals", "password")
+		require.NoError(t, err)
+
+		withdrawals, err := withdrawalRepo.GetByOrderNumber(ctx, other.ID, "1111111111")
+		require.NoError(t, err)
+		assert.Empty(t, withdrawals)
+	})
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WithdrawalLimitRepository реализует интерфейс ports.WithdrawalLimitRepository для PostgreSQL.
+type WithdrawalLimitRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewWithdrawalLimitRepository создаёт новый репозиторий индивидуальных лимитов списаний.
+func NewWithdrawalLimitRepository(pool *pgxpool.Pool) *WithdrawalLimitRepository {
+	return &WithdrawalLimitRepository{pool: pool}
+}
+
+// GetByUserID возвращает индивидуальные лимиты пользователя или nil,
+// если они не назначены.
+func (r *WithdrawalLimitRepository) GetByUserID(ctx context.Context, userID int64) (*domain.WithdrawalLimitsOverride, error) {
+	return getWithdrawalLimitsOverride(ctx, r.pool, userID)
+}
+
+// Set назначает пользователю индивидуальные лимиты, заменяя ранее назначенные.
+func (r *WithdrawalLimitRepository) Set(ctx context.Context, override *domain.WithdrawalLimitsOverride) error {
+	query := `
+		INSERT INTO withdrawal_limits (user_id, per_transaction, daily, monthly, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET per_transaction = EXCLUDED.per_transaction,
+			daily = EXCLUDED.daily,
+			monthly = EXCLUDED.monthly,
+			updated_at = EXCLUDED.updated_at
+		RETURNING updated_at
+	`
+
+	return r.pool.QueryRow(ctx, query,
+		override.UserID,
+		override.PerTransaction,
+		override.Daily,
+		override.Monthly,
+	).Scan(&override.UpdatedAt)
+}
+
+// Delete снимает индивидуальные лимиты пользователя.
+func (r *WithdrawalLimitRepository) Delete(ctx context.Context, userID int64) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM withdrawal_limits WHERE user_id = $1`, userID)
+	return err
+}
+
+func getWithdrawalLimitsOverride(ctx context.Context, db dbtx, userID int64) (*domain.WithdrawalLimitsOverride, error) {
+	query := `
+		SELECT user_id, per_transaction, daily, monthly, updated_at
+		FROM withdrawal_limits
+		WHERE user_id = $1
+	`
+
+	var override domain.WithdrawalLimitsOverride
+	err := db.QueryRow(ctx, query, userID).Scan(
+		&override.UserID,
+		&override.PerTransaction,
+		&override.Daily,
+		&override.Monthly,
+		&override.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &override, nil
+}
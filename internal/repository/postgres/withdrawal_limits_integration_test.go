@@ -0,0 +1,102 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/repository/postgres"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithdrawalLimitRepository(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	limitRepo := postgres.NewWithdrawalLimitRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "limituser", "password")
+	require.NoError(t, err)
+
+	t.Run("без индивидуальных лимитов возвращает nil", func(t *testing.T) {
+		override, err := limitRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Nil(t, override)
+	})
+
+	t.Run("назначение и чтение лимитов", func(t *testing.T) {
+		daily := decimal.NewFromInt(300)
+		require.NoError(t, limitRepo.Set(ctx, &domain.WithdrawalLimitsOverride{UserID: user.ID, Daily: &daily}))
+
+		override, err := limitRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		require.NotNil(t, override)
+		assert.Nil(t, override.PerTransaction)
+		assert.Nil(t, override.Monthly)
+		if assert.NotNil(t, override.Daily) {
+			assert.True(t, daily.Equal(*override.Daily))
+		}
+	})
+
+	t.Run("снятие лимитов", func(t *testing.T) {
+		require.NoError(t, limitRepo.Delete(ctx, user.ID))
+
+		override, err := limitRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Nil(t, override)
+	})
+}
+
+func TestBalanceRepository_WithdrawLimits(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(testPool)
+	balanceRepo := postgres.NewBalanceRepository(testPool)
+	limitRepo := postgres.NewWithdrawalLimitRepository(testPool)
+
+	user, err := userRepo.Create(ctx, "limitedwithdraw", "password")
+	require.NoError(t, err)
+	require.NoError(t, balanceRepo.CreateForUser(ctx, user.ID))
+	require.NoError(t, balanceRepo.AddAccrual(ctx, &domain.Accrual{
+		UserID:      user.ID,
+		OrderNumber: "12345678903",
+		Amount:      decimal.NewFromInt(1000),
+	}))
+
+	limits := domain.WithdrawalLimits{
+		PerTransaction: decimal.NewFromInt(200),
+		Daily:          decimal.NewFromInt(300),
+	}
+
+	t.Run("превышение лимита на одно списание", func(t *testing.T) {
+		err := balanceRepo.Withdraw(ctx, user.ID, "2377225624", decimal.NewFromInt(250), limits)
+		assert.ErrorIs(t, err, domain.ErrWithdrawalLimitExceeded)
+	})
+
+	t.Run("превышение суточного лимита", func(t *testing.T) {
+		require.NoError(t, balanceRepo.Withdraw(ctx, user.ID, "2377225624", decimal.NewFromInt(200), limits))
+
+		err := balanceRepo.Withdraw(ctx, user.ID, "1234567890", decimal.NewFromInt(150), limits)
+		assert.ErrorIs(t, err, domain.ErrWithdrawalLimitExceeded)
+
+		balance, err := balanceRepo.GetByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromInt(800).Equal(balance.Current))
+	})
+
+	t.Run("индивидуальные лимиты важнее лимитов по умолчанию", func(t *testing.T) {
+		unlimited := decimal.Zero
+		require.NoError(t, limitRepo.Set(ctx, &domain.WithdrawalLimitsOverride{
+			UserID:         user.ID,
+			PerTransaction: &unlimited,
+			Daily:          &unlimited,
+		}))
+
+		err := balanceRepo.Withdraw(ctx, user.ID, "1234567890", decimal.NewFromInt(500), limits)
+		require.NoError(t, err)
+	})
+}
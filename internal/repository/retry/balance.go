@@ -3,6 +3,7 @@ package retry
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/arvaliullin/gophermart/internal/core/ports"
@@ -49,16 +50,115 @@ func (a *BalanceRepositoryAdapter) CreateForUser(ctx context.Context, userID int
 	})
 }
 
-// AddAccrual добавляет начисление к балансу пользователя.
-func (a *BalanceRepositoryAdapter) AddAccrual(ctx context.Context, userID int64, amount decimal.Decimal) error {
+// AddAccrual добавляет начисление к балансу пользователя за заказ.
+func (a *BalanceRepositoryAdapter) AddAccrual(ctx context.Context, accrual *domain.Accrual) error {
 	return a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
-		return a.repo.AddAccrual(ctx, userID, amount)
+		return a.repo.AddAccrual(ctx, accrual)
 	})
 }
 
+// SettleAccrual доводит сумму начислений пользователю за заказ до заданной.
+func (a *BalanceRepositoryAdapter) SettleAccrual(ctx context.Context, accrual *domain.Accrual, description string) (*domain.LedgerEntry, error) {
+	var entry *domain.LedgerEntry
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		entry, err = a.repo.SettleAccrual(ctx, accrual, description)
+		return err
+	})
+	return entry, err
+}
+
 // Withdraw выполняет списание средств с баланса пользователя.
-func (a *BalanceRepositoryAdapter) Withdraw(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal) error {
+func (a *BalanceRepositoryAdapter) Withdraw(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal, limits domain.WithdrawalLimits) error {
 	return a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
-		return a.repo.Withdraw(ctx, userID, orderNumber, amount)
+		return a.repo.Withdraw(ctx, userID, orderNumber, amount, limits)
+	})
+}
+
+// Refund возвращает баллы по списанию.
+func (a *BalanceRepositoryAdapter) Refund(ctx context.Context, withdrawalID int64, amount *decimal.Decimal) (*domain.Refund, error) {
+	var refund *domain.Refund
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		refund, err = a.repo.Refund(ctx, withdrawalID, amount)
+		return err
+	})
+	return refund, err
+}
+
+// Hold блокирует баллы пользователя под оплату заказа.
+func (a *BalanceRepositoryAdapter) Hold(ctx context.Context, userID int64, orderNumber string, amount decimal.Decimal, expiresAt time.Time, limits domain.WithdrawalLimits) (*domain.Hold, error) {
+	var hold *domain.Hold
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		hold, err = a.repo.Hold(ctx, userID, orderNumber, amount, expiresAt, limits)
+		return err
+	})
+	return hold, err
+}
+
+// CaptureHold подтверждает блокировку и превращает её в списание.
+func (a *BalanceRepositoryAdapter) CaptureHold(ctx context.Context, holdID int64, limits domain.WithdrawalLimits) (*domain.Withdrawal, error) {
+	var withdrawal *domain.Withdrawal
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		withdrawal, err = a.repo.CaptureHold(ctx, holdID, limits)
+		return err
+	})
+	return withdrawal, err
+}
+
+// ReleaseHold отменяет блокировку баллов.
+func (a *BalanceRepositoryAdapter) ReleaseHold(ctx context.Context, holdID int64) (*domain.Hold, error) {
+	var hold *domain.Hold
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		hold, err = a.repo.ReleaseHold(ctx, holdID)
+		return err
+	})
+	return hold, err
+}
+
+// ExpireHolds завершает блокировки с истёкшим сроком.
+func (a *BalanceRepositoryAdapter) ExpireHolds(ctx context.Context) (int64, error) {
+	var expired int64
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		expired, err = a.repo.ExpireHolds(ctx)
+		return err
+	})
+	return expired, err
+}
+
+// GetExpiring возвращает сумму баллов пользователя, которые сгорят до момента before.
+func (a *BalanceRepositoryAdapter) GetExpiring(ctx context.Context, userID int64, before time.Time) (decimal.Decimal, error) {
+	var expiring decimal.Decimal
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		expiring, err = a.repo.GetExpiring(ctx, userID, before)
+		return err
+	})
+	return expiring, err
+}
+
+// ExpirePoints списывает сгоревшие партии баллов.
+func (a *BalanceRepositoryAdapter) ExpirePoints(ctx context.Context) (int64, error) {
+	var expired int64
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		expired, err = a.repo.ExpirePoints(ctx)
+		return err
+	})
+	return expired, err
+}
+
+// Transfer переводит баллы от одного пользователя другому.
+func (a *BalanceRepositoryAdapter) Transfer(ctx context.Context, fromUserID, toUserID int64, amount, dailyLimit decimal.Decimal) (*domain.Transfer, error) {
+	var transfer *domain.Transfer
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		transfer, err = a.repo.Transfer(ctx, fromUserID, toUserID, amount, dailyLimit)
+		return err
 	})
+	return transfer, err
 }
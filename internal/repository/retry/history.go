@@ -0,0 +1,40 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/arvaliullin/gophermart/internal/pkg/retry"
+)
+
+// ErrHistoryRepoNil возвращается при попытке создать адаптер с nil репозиторием.
+var ErrHistoryRepoNil = fmt.Errorf("репозиторий истории по счёту не задан")
+
+// HistoryRepositoryAdapter добавляет стратегию повторов для репозитория истории по счёту.
+type HistoryRepositoryAdapter struct {
+	repo     ports.HistoryRepository
+	strategy *retry.Strategy
+}
+
+// NewHistoryRepositoryAdapter создаёт адаптер репозитория истории по счёту с поддержкой retry.
+func NewHistoryRepositoryAdapter(repo ports.HistoryRepository, strategy *retry.Strategy) (*HistoryRepositoryAdapter, error) {
+	if repo == nil {
+		return nil, ErrHistoryRepoNil
+	}
+
+	return &HistoryRepositoryAdapter{
+		repo:     repo,
+		strategy: strategy,
+	}, nil
+}
+
+// StreamByUserID передаёт заказы и списания пользователя за период в fn.
+// Повтор возможен, только пока ни одна запись не передана.
+func (a *HistoryRepositoryAdapter) StreamByUserID(ctx context.Context, userID int64, from, to *time.Time, fn func(*domain.HistoryRecord) error) error {
+	return streamWithRetry(ctx, a.strategy, func(ctx context.Context, fn func(*domain.HistoryRecord) error) error {
+		return a.repo.StreamByUserID(ctx, userID, from, to, fn)
+	}, fn)
+}
@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/arvaliullin/gophermart/internal/pkg/retry"
+)
+
+// ErrIdempotencyRepoNil возвращается при попытке создать адаптер с nil репозиторием.
+var ErrIdempotencyRepoNil = fmt.Errorf("репозиторий ключей идемпотентности не задан")
+
+// IdempotencyRepositoryAdapter добавляет стратегию повторов для репозитория ключей идемпотентности.
+type IdempotencyRepositoryAdapter struct {
+	repo     ports.IdempotencyRepository
+	strategy *retry.Strategy
+}
+
+// NewIdempotencyRepositoryAdapter создаёт адаптер репозитория ключей идемпотентности с поддержкой retry.
+func NewIdempotencyRepositoryAdapter(repo ports.IdempotencyRepository, strategy *retry.Strategy) (*IdempotencyRepositoryAdapter, error) {
+	if repo == nil {
+		return nil, ErrIdempotencyRepoNil
+	}
+
+	return &IdempotencyRepositoryAdapter{
+		repo:     repo,
+		strategy: strategy,
+	}, nil
+}
+
+// Reserve занимает ключ идемпотентности за пользователем.
+func (a *IdempotencyRepositoryAdapter) Reserve(ctx context.Context, userID int64, key, requestHash string, lockTTL time.Duration) (*domain.IdempotencyRecord, bool, error) {
+	var (
+		record  *domain.IdempotencyRecord
+		created bool
+	)
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		record, created, err = a.repo.Reserve(ctx, userID, key, requestHash, lockTTL)
+		return err
+	})
+	return record, created, err
+}
+
+// Extend продлевает блокировку незавершённого ключа.
+func (a *IdempotencyRepositoryAdapter) Extend(ctx context.Context, userID int64, key string, lockTTL time.Duration) error {
+	return a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		return a.repo.Extend(ctx, userID, key, lockTTL)
+	})
+}
+
+// Complete сохраняет ответ на запрос.
+func (a *IdempotencyRepositoryAdapter) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	return a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		return a.repo.Complete(ctx, record)
+	})
+}
+
+// Release освобождает незавершённый ключ.
+func (a *IdempotencyRepositoryAdapter) Release(ctx context.Context, userID int64, key string) error {
+	return a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		return a.repo.Release(ctx, userID, key)
+	})
+}
+
+// PurgeExpired удаляет устаревшие ключи.
+func (a *IdempotencyRepositoryAdapter) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		purged, err = a.repo.PurgeExpired(ctx, before)
+		return err
+	})
+	return purged, err
+}
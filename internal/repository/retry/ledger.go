@@ -0,0 +1,53 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/arvaliullin/gophermart/internal/pkg/retry"
+)
+
+// ErrLedgerRepoNil возвращается при попытке создать адаптер с nil репозиторием.
+var ErrLedgerRepoNil = fmt.Errorf("репозиторий журнала проводок не задан")
+
+// LedgerRepositoryAdapter добавляет стратегию повторов для репозитория журнала проводок.
+type LedgerRepositoryAdapter struct {
+	repo     ports.LedgerRepository
+	strategy *retry.Strategy
+}
+
+// NewLedgerRepositoryAdapter создаёт адаптер репозитория журнала проводок с поддержкой retry.
+func NewLedgerRepositoryAdapter(repo ports.LedgerRepository, strategy *retry.Strategy) (*LedgerRepositoryAdapter, error) {
+	if repo == nil {
+		return nil, ErrLedgerRepoNil
+	}
+
+	return &LedgerRepositoryAdapter{
+		repo:     repo,
+		strategy: strategy,
+	}, nil
+}
+
+// GetByUserID возвращает все проводки пользователя.
+func (a *LedgerRepositoryAdapter) GetByUserID(ctx context.Context, userID int64) ([]*domain.LedgerEntry, error) {
+	var entries []*domain.LedgerEntry
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		entries, err = a.repo.GetByUserID(ctx, userID)
+		return err
+	})
+	return entries, err
+}
+
+// GetStatement возвращает страницу выписки по счёту пользователя.
+func (a *LedgerRepositoryAdapter) GetStatement(ctx context.Context, userID int64, query domain.StatementQuery) ([]*domain.StatementEntry, error) {
+	var entries []*domain.StatementEntry
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		entries, err = a.repo.GetStatement(ctx, userID, query)
+		return err
+	})
+	return entries, err
+}
@@ -3,6 +3,7 @@ package retry
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/arvaliullin/gophermart/internal/core/domain"
 	"github.com/arvaliullin/gophermart/internal/core/ports"
@@ -42,6 +43,17 @@ func (a *OrderRepositoryAdapter) Create(ctx context.Context, userID int64, numbe
 	return order, err
 }
 
+// CreateBatch создаёт заказы пользователя одним запросом.
+func (a *OrderRepositoryAdapter) CreateBatch(ctx context.Context, userID int64, numbers []string) ([]*domain.OrderSubmission, error) {
+	var submissions []*domain.OrderSubmission
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		submissions, err = a.repo.CreateBatch(ctx, userID, numbers)
+		return err
+	})
+	return submissions, err
+}
+
 // GetByNumber возвращает заказ по номеру.
 func (a *OrderRepositoryAdapter) GetByNumber(ctx context.Context, number string) (*domain.Order, error) {
 	var order *domain.Order
@@ -64,20 +76,114 @@ func (a *OrderRepositoryAdapter) GetByUserID(ctx context.Context, userID int64)
 	return orders, err
 }
 
-// GetPendingOrders возвращает заказы со статусами NEW или PROCESSING.
-func (a *OrderRepositoryAdapter) GetPendingOrders(ctx context.Context) ([]*domain.Order, error) {
+// List возвращает страницу заказов пользователя.
+func (a *OrderRepositoryAdapter) List(ctx context.Context, userID int64, query domain.OrderListQuery) ([]*domain.Order, error) {
 	var orders []*domain.Order
 	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
 		var err error
-		orders, err = a.repo.GetPendingOrders(ctx)
+		orders, err = a.repo.List(ctx, userID, query)
 		return err
 	})
 	return orders, err
 }
 
+// GetPendingOrders арендует на lease не более limit заказов со статусами NEW или PROCESSING.
+func (a *OrderRepositoryAdapter) GetPendingOrders(ctx context.Context, limit int, lease time.Duration) ([]*domain.Order, error) {
+	var orders []*domain.Order
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		orders, err = a.repo.GetPendingOrders(ctx, limit, lease)
+		return err
+	})
+	return orders, err
+}
+
+// ClaimLease арендует заказ, если его не арендовал другой экземпляр сервиса.
+func (a *OrderRepositoryAdapter) ClaimLease(ctx context.Context, orderID int64, lease time.Duration) error {
+	return a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		return a.repo.ClaimLease(ctx, orderID, lease)
+	})
+}
+
+// ReleaseLease снимает аренду заказа.
+func (a *OrderRepositoryAdapter) ReleaseLease(ctx context.Context, orderID int64) error {
+	return a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		return a.repo.ReleaseLease(ctx, orderID)
+	})
+}
+
+// Reschedule снимает аренду заказа и назначает его следующую проверку.
+func (a *OrderRepositoryAdapter) Reschedule(ctx context.Context, orderID int64, attempts int, delay time.Duration, lastError string) error {
+	return a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		return a.repo.Reschedule(ctx, orderID, attempts, delay, lastError)
+	})
+}
+
+// DeadLetter снимает заказ с опроса системы начислений.
+func (a *OrderRepositoryAdapter) DeadLetter(ctx context.Context, orderID int64, attempts int, lastError string) error {
+	return a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		return a.repo.DeadLetter(ctx, orderID, attempts, lastError)
+	})
+}
+
+// ListDeadLetters возвращает снятые с опроса заказы, отобранные по query.
+func (a *OrderRepositoryAdapter) ListDeadLetters(ctx context.Context, query domain.ListQuery) ([]*domain.DeadLetterOrder, error) {
+	var orders []*domain.DeadLetterOrder
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		orders, err = a.repo.ListDeadLetters(ctx, query)
+		return err
+	})
+	return orders, err
+}
+
+// Requeue возвращает снятый с опроса заказ в опрос.
+func (a *OrderRepositoryAdapter) Requeue(ctx context.Context, number string) (*domain.Order, error) {
+	var order *domain.Order
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = a.repo.Requeue(ctx, number)
+		return err
+	})
+	return order, err
+}
+
 // UpdateStatus обновляет статус и начисление заказа.
-func (a *OrderRepositoryAdapter) UpdateStatus(ctx context.Context, number string, status domain.OrderStatus, accrual *decimal.Decimal) error {
+func (a *OrderRepositoryAdapter) UpdateStatus(ctx context.Context, orderID int64, status domain.OrderStatus, accrual *decimal.Decimal) error {
 	return a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
-		return a.repo.UpdateStatus(ctx, number, status, accrual)
+		return a.repo.UpdateStatus(ctx, orderID, status, accrual)
+	})
+}
+
+// GetStatusHistory возвращает историю статусов заказа.
+func (a *OrderRepositoryAdapter) GetStatusHistory(ctx context.Context, orderID int64) ([]*domain.OrderStatusChange, error) {
+	var history []*domain.OrderStatusChange
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		history, err = a.repo.GetStatusHistory(ctx, orderID)
+		return err
 	})
+	return history, err
+}
+
+// Cancel отменяет заказ пользователя.
+func (a *OrderRepositoryAdapter) Cancel(ctx context.Context, userID int64, number string) (*domain.Order, error) {
+	var order *domain.Order
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = a.repo.Cancel(ctx, userID, number)
+		return err
+	})
+	return order, err
+}
+
+// Reopen переводит заказ обратно в статус, допускающий перепроверку.
+func (a *OrderRepositoryAdapter) Reopen(ctx context.Context, orderID int64) (*domain.Order, error) {
+	var order *domain.Order
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = a.repo.Reopen(ctx, orderID)
+		return err
+	})
+	return order, err
 }
@@ -0,0 +1,53 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/arvaliullin/gophermart/internal/pkg/retry"
+)
+
+// ErrReconciliationRepoNil возвращается при попытке создать адаптер с nil репозиторием.
+var ErrReconciliationRepoNil = fmt.Errorf("репозиторий сверки балансов не задан")
+
+// ReconciliationRepositoryAdapter добавляет стратегию повторов для репозитория сверки балансов.
+type ReconciliationRepositoryAdapter struct {
+	repo     ports.ReconciliationRepository
+	strategy *retry.Strategy
+}
+
+// NewReconciliationRepositoryAdapter создаёт адаптер репозитория сверки балансов с поддержкой retry.
+func NewReconciliationRepositoryAdapter(repo ports.ReconciliationRepository, strategy *retry.Strategy) (*ReconciliationRepositoryAdapter, error) {
+	if repo == nil {
+		return nil, ErrReconciliationRepoNil
+	}
+
+	return &ReconciliationRepositoryAdapter{
+		repo:     repo,
+		strategy: strategy,
+	}, nil
+}
+
+// FindMismatches возвращает расхождения балансов с исходными данными.
+func (a *ReconciliationRepositoryAdapter) FindMismatches(ctx context.Context) ([]*domain.BalanceMismatch, error) {
+	var mismatches []*domain.BalanceMismatch
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		mismatches, err = a.repo.FindMismatches(ctx)
+		return err
+	})
+	return mismatches, err
+}
+
+// Repair исправляет расхождение остатка пользователя.
+func (a *ReconciliationRepositoryAdapter) Repair(ctx context.Context, userID int64) (*domain.LedgerEntry, error) {
+	var entry *domain.LedgerEntry
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		entry, err = a.repo.Repair(ctx, userID)
+		return err
+	})
+	return entry, err
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
 	"github.com/arvaliullin/gophermart/internal/core/ports/mocks"
 	"github.com/arvaliullin/gophermart/internal/pkg/retry"
 	"github.com/shopspring/decimal"
@@ -122,6 +123,43 @@ func TestOrderRepositoryAdapter_Create(t *testing.T) {
 	assert.Equal(t, expectedOrder, order)
 }
 
+func TestOrderRepositoryAdapter_CreateBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockOrderRepository(ctrl)
+	adapter, _ := NewOrderRepositoryAdapter(repo, testStrategy())
+
+	numbers := []string{"123", "456"}
+	expected := []*domain.OrderSubmission{
+		{Number: "123", Result: domain.OrderSubmitAccepted},
+		{Number: "456", Result: domain.OrderSubmitBelongsToOther},
+	}
+	repo.EXPECT().CreateBatch(ctx, int64(1), numbers).Return(expected, nil)
+
+	submissions, err := adapter.CreateBatch(ctx, 1, numbers)
+	require.NoError(t, err)
+	assert.Equal(t, expected, submissions)
+}
+
+func TestOrderRepositoryAdapter_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockOrderRepository(ctrl)
+	adapter, _ := NewOrderRepositoryAdapter(repo, testStrategy())
+
+	query := domain.OrderListQuery{ListQuery: domain.ListQuery{Limit: 10}}
+	expected := []*domain.Order{{ID: 1}}
+	repo.EXPECT().List(ctx, int64(1), query).Return(expected, nil)
+
+	orders, err := adapter.List(ctx, 1, query)
+	require.NoError(t, err)
+	assert.Equal(t, expected, orders)
+}
+
 func TestOrderRepositoryAdapter_GetByNumber(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -138,6 +176,54 @@ func TestOrderRepositoryAdapter_GetByNumber(t *testing.T) {
 	assert.Equal(t, expectedOrder, order)
 }
 
+func TestOrderRepositoryAdapter_GetStatusHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockOrderRepository(ctrl)
+	adapter, _ := NewOrderRepositoryAdapter(repo, testStrategy())
+
+	expected := []*domain.OrderStatusChange{{Status: domain.OrderStatusNew}}
+	repo.EXPECT().GetStatusHistory(ctx, int64(1)).Return(expected, nil)
+
+	history, err := adapter.GetStatusHistory(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, expected, history)
+}
+
+func TestOrderRepositoryAdapter_Cancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockOrderRepository(ctrl)
+	adapter, _ := NewOrderRepositoryAdapter(repo, testStrategy())
+
+	expected := &domain.Order{ID: 1, Number: "123", Status: domain.OrderStatusCanceled}
+	repo.EXPECT().Cancel(ctx, int64(1), "123").Return(expected, nil)
+
+	order, err := adapter.Cancel(ctx, 1, "123")
+	require.NoError(t, err)
+	assert.Equal(t, expected, order)
+}
+
+func TestOrderRepositoryAdapter_Reopen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockOrderRepository(ctrl)
+	adapter, _ := NewOrderRepositoryAdapter(repo, testStrategy())
+
+	expected := &domain.Order{ID: 1, Number: "123", Status: domain.OrderStatusInvalid}
+	repo.EXPECT().Reopen(ctx, int64(1)).Return(expected, nil)
+
+	order, err := adapter.Reopen(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, expected, order)
+}
+
 func TestOrderRepositoryAdapter_GetByUserID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -163,13 +249,102 @@ func TestOrderRepositoryAdapter_GetPendingOrders(t *testing.T) {
 	adapter, _ := NewOrderRepositoryAdapter(repo, testStrategy())
 
 	expectedOrders := []*domain.Order{{ID: 1, Status: domain.OrderStatusNew}}
-	repo.EXPECT().GetPendingOrders(ctx).Return(expectedOrders, nil)
+	repo.EXPECT().GetPendingOrders(ctx, 10, time.Minute).Return(expectedOrders, nil)
 
-	orders, err := adapter.GetPendingOrders(ctx)
+	orders, err := adapter.GetPendingOrders(ctx, 10, time.Minute)
 	require.NoError(t, err)
 	assert.Equal(t, expectedOrders, orders)
 }
 
+func TestOrderRepositoryAdapter_ClaimLease(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockOrderRepository(ctrl)
+	adapter, _ := NewOrderRepositoryAdapter(repo, testStrategy())
+
+	repo.EXPECT().ClaimLease(ctx, int64(1), time.Minute).Return(domain.ErrOrderLocked)
+
+	err := adapter.ClaimLease(ctx, 1, time.Minute)
+	assert.ErrorIs(t, err, domain.ErrOrderLocked)
+}
+
+func TestOrderRepositoryAdapter_ReleaseLease(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockOrderRepository(ctrl)
+	adapter, _ := NewOrderRepositoryAdapter(repo, testStrategy())
+
+	repo.EXPECT().ReleaseLease(ctx, int64(1)).Return(nil)
+
+	err := adapter.ReleaseLease(ctx, 1)
+	require.NoError(t, err)
+}
+
+func TestOrderRepositoryAdapter_Reschedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockOrderRepository(ctrl)
+	adapter, _ := NewOrderRepositoryAdapter(repo, testStrategy())
+
+	repo.EXPECT().Reschedule(ctx, int64(1), 3, 8*time.Second, "timeout").Return(nil)
+
+	err := adapter.Reschedule(ctx, 1, 3, 8*time.Second, "timeout")
+	require.NoError(t, err)
+}
+
+func TestOrderRepositoryAdapter_DeadLetter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockOrderRepository(ctrl)
+	adapter, _ := NewOrderRepositoryAdapter(repo, testStrategy())
+
+	repo.EXPECT().DeadLetter(ctx, int64(1), 10, "timeout").Return(nil)
+
+	err := adapter.DeadLetter(ctx, 1, 10, "timeout")
+	require.NoError(t, err)
+}
+
+func TestOrderRepositoryAdapter_ListDeadLetters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockOrderRepository(ctrl)
+	adapter, _ := NewOrderRepositoryAdapter(repo, testStrategy())
+
+	query := domain.ListQuery{Limit: 10}
+	expected := []*domain.DeadLetterOrder{{Order: &domain.Order{ID: 1}, LastError: "timeout"}}
+	repo.EXPECT().ListDeadLetters(ctx, query).Return(expected, nil)
+
+	orders, err := adapter.ListDeadLetters(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, expected, orders)
+}
+
+func TestOrderRepositoryAdapter_Requeue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockOrderRepository(ctrl)
+	adapter, _ := NewOrderRepositoryAdapter(repo, testStrategy())
+
+	expected := &domain.Order{ID: 1, Number: "12345678903", Status: domain.OrderStatusNew}
+	repo.EXPECT().Requeue(ctx, "12345678903").Return(expected, nil)
+
+	order, err := adapter.Requeue(ctx, "12345678903")
+	require.NoError(t, err)
+	assert.Equal(t, expected, order)
+}
+
 func TestOrderRepositoryAdapter_UpdateStatus(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -179,9 +354,9 @@ func TestOrderRepositoryAdapter_UpdateStatus(t *testing.T) {
 	adapter, _ := NewOrderRepositoryAdapter(repo, testStrategy())
 
 	accrual := decimal.NewFromFloat(100.0)
-	repo.EXPECT().UpdateStatus(ctx, "123", domain.OrderStatusProcessed, &accrual).Return(nil)
+	repo.EXPECT().UpdateStatus(ctx, int64(1), domain.OrderStatusProcessed, &accrual).Return(nil)
 
-	err := adapter.UpdateStatus(ctx, "123", domain.OrderStatusProcessed, &accrual)
+	err := adapter.UpdateStatus(ctx, 1, domain.OrderStatusProcessed, &accrual)
 	require.NoError(t, err)
 }
 
@@ -241,13 +416,51 @@ func TestBalanceRepositoryAdapter_AddAccrual(t *testing.T) {
 	repo := mocks.NewMockBalanceRepository(ctrl)
 	adapter, _ := NewBalanceRepositoryAdapter(repo, testStrategy())
 
-	amount := decimal.NewFromFloat(50.0)
-	repo.EXPECT().AddAccrual(ctx, int64(1), amount).Return(nil)
+	accrual := &domain.Accrual{UserID: 1, OrderNumber: "123", Amount: decimal.NewFromFloat(50.0)}
+	repo.EXPECT().AddAccrual(ctx, accrual).Return(nil)
 
-	err := adapter.AddAccrual(ctx, 1, amount)
+	err := adapter.AddAccrual(ctx, accrual)
 	require.NoError(t, err)
 }
 
+func TestBalanceRepositoryAdapter_SettleAccrual(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockBalanceRepository(ctrl)
+	adapter, _ := NewBalanceRepositoryAdapter(repo, testStrategy())
+
+	accrual := &domain.Accrual{UserID: 1, OrderNumber: "123", Amount: decimal.NewFromFloat(80.0)}
+	expected := &domain.LedgerEntry{ID: 1, Kind: domain.LedgerEntryAccrualCorrection, Amount: decimal.NewFromFloat(30.0)}
+	repo.EXPECT().SettleAccrual(ctx, accrual, "перепроверка").Return(expected, nil)
+
+	entry, err := adapter.SettleAccrual(ctx, accrual, "перепроверка")
+	require.NoError(t, err)
+	assert.Equal(t, expected, entry)
+}
+
+func TestBalanceRepositoryAdapter_Expiration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockBalanceRepository(ctrl)
+	adapter, _ := NewBalanceRepositoryAdapter(repo, testStrategy())
+
+	before := time.Now().Add(time.Hour)
+	repo.EXPECT().GetExpiring(ctx, int64(1), before).Return(decimal.NewFromFloat(15.0), nil)
+	repo.EXPECT().ExpirePoints(ctx).Return(int64(2), nil)
+
+	expiring, err := adapter.GetExpiring(ctx, 1, before)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromFloat(15.0).Equal(expiring))
+
+	expired, err := adapter.ExpirePoints(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), expired)
+}
+
 func TestBalanceRepositoryAdapter_Withdraw(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -257,10 +470,82 @@ func TestBalanceRepositoryAdapter_Withdraw(t *testing.T) {
 	adapter, _ := NewBalanceRepositoryAdapter(repo, testStrategy())
 
 	amount := decimal.NewFromFloat(25.0)
-	repo.EXPECT().Withdraw(ctx, int64(1), "123", amount).Return(nil)
+	limits := domain.WithdrawalLimits{Daily: decimal.NewFromInt(100)}
+	repo.EXPECT().Withdraw(ctx, int64(1), "123", amount, limits).Return(nil)
+
+	err := adapter.Withdraw(ctx, 1, "123", amount, limits)
+	require.NoError(t, err)
+}
+
+func TestBalanceRepositoryAdapter_Refund(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockBalanceRepository(ctrl)
+	adapter, _ := NewBalanceRepositoryAdapter(repo, testStrategy())
+
+	amount := decimal.NewFromFloat(10.0)
+	expectedRefund := &domain.Refund{ID: 1, WithdrawalID: 2, UserID: 1, Sum: amount}
+	repo.EXPECT().Refund(ctx, int64(2), &amount).Return(expectedRefund, nil)
+
+	refund, err := adapter.Refund(ctx, 2, &amount)
+	require.NoError(t, err)
+	assert.Equal(t, expectedRefund, refund)
+}
+
+func TestBalanceRepositoryAdapter_Holds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockBalanceRepository(ctrl)
+	adapter, _ := NewBalanceRepositoryAdapter(repo, testStrategy())
+
+	amount := decimal.NewFromFloat(10.0)
+	expiresAt := time.Now().Add(time.Minute)
+	limit := domain.WithdrawalLimits{Daily: decimal.NewFromFloat(100.0)}
+	expectedHold := &domain.Hold{ID: 1, UserID: 1, OrderNumber: "123", Amount: amount}
+	expectedWithdrawal := &domain.Withdrawal{ID: 2, UserID: 1, OrderNumber: "123", Sum: amount}
+
+	repo.EXPECT().Hold(ctx, int64(1), "123", amount, expiresAt, limit).Return(expectedHold, nil)
+	repo.EXPECT().CaptureHold(ctx, int64(1), domain.WithdrawalLimits{}).Return(expectedWithdrawal, nil)
+	repo.EXPECT().ReleaseHold(ctx, int64(1)).Return(expectedHold, nil)
+	repo.EXPECT().ExpireHolds(ctx).Return(int64(3), nil)
+
+	hold, err := adapter.Hold(ctx, 1, "123", amount, expiresAt, limit)
+	require.NoError(t, err)
+	assert.Equal(t, expectedHold, hold)
+
+	withdrawal, err := adapter.CaptureHold(ctx, 1, domain.WithdrawalLimits{})
+	require.NoError(t, err)
+	assert.Equal(t, expectedWithdrawal, withdrawal)
+
+	hold, err = adapter.ReleaseHold(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, expectedHold, hold)
 
-	err := adapter.Withdraw(ctx, 1, "123", amount)
+	expired, err := adapter.ExpireHolds(ctx)
 	require.NoError(t, err)
+	assert.Equal(t, int64(3), expired)
+}
+
+func TestBalanceRepositoryAdapter_Transfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockBalanceRepository(ctrl)
+	adapter, _ := NewBalanceRepositoryAdapter(repo, testStrategy())
+
+	amount := decimal.NewFromFloat(10.0)
+	limit := decimal.NewFromFloat(100.0)
+	expected := &domain.Transfer{ID: 1, FromUserID: 1, ToUserID: 2, Amount: amount}
+	repo.EXPECT().Transfer(ctx, int64(1), int64(2), amount, limit).Return(expected, nil)
+
+	transfer, err := adapter.Transfer(ctx, 1, 2, amount, limit)
+	require.NoError(t, err)
+	assert.Equal(t, expected, transfer)
 }
 
 func TestNewWithdrawalRepositoryAdapter(t *testing.T) {
@@ -297,6 +582,423 @@ func TestWithdrawalRepositoryAdapter_GetByUserID(t *testing.T) {
 	assert.Equal(t, expectedWithdrawals, withdrawals)
 }
 
+func TestWithdrawalRepositoryAdapter_GetByOrderNumber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockWithdrawalRepository(ctrl)
+	adapter, _ := NewWithdrawalRepositoryAdapter(repo, testStrategy())
+
+	expected := []*domain.Withdrawal{{ID: 1, OrderNumber: "123"}}
+	repo.EXPECT().GetByOrderNumber(ctx, int64(1), "123").Return(expected, nil)
+
+	withdrawals, err := adapter.GetByOrderNumber(ctx, 1, "123")
+	require.NoError(t, err)
+	assert.Equal(t, expected, withdrawals)
+}
+
+func TestWithdrawalRepositoryAdapter_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockWithdrawalRepository(ctrl)
+	adapter, _ := NewWithdrawalRepositoryAdapter(repo, testStrategy())
+
+	query := domain.ListQuery{Limit: 10, Ascending: true}
+	expected := []*domain.Withdrawal{{ID: 1}}
+	repo.EXPECT().List(ctx, int64(1), query).Return(expected, nil)
+
+	withdrawals, err := adapter.List(ctx, 1, query)
+	require.NoError(t, err)
+	assert.Equal(t, expected, withdrawals)
+}
+
+func TestNewLedgerRepositoryAdapter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("успешное создание", func(t *testing.T) {
+		repo := mocks.NewMockLedgerRepository(ctrl)
+		adapter, err := NewLedgerRepositoryAdapter(repo, testStrategy())
+		require.NoError(t, err)
+		assert.NotNil(t, adapter)
+	})
+
+	t.Run("ошибка при nil репозитории", func(t *testing.T) {
+		adapter, err := NewLedgerRepositoryAdapter(nil, testStrategy())
+		assert.ErrorIs(t, err, ErrLedgerRepoNil)
+		assert.Nil(t, adapter)
+	})
+}
+
+func TestLedgerRepositoryAdapter_GetByUserID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockLedgerRepository(ctrl)
+	adapter, _ := NewLedgerRepositoryAdapter(repo, testStrategy())
+
+	expectedEntries := []*domain.LedgerEntry{{ID: 1}, {ID: 2}}
+	repo.EXPECT().GetByUserID(ctx, int64(1)).Return(expectedEntries, nil)
+
+	entries, err := adapter.GetByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, expectedEntries, entries)
+}
+
+func TestLedgerRepositoryAdapter_GetStatement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockLedgerRepository(ctrl)
+	adapter, _ := NewLedgerRepositoryAdapter(repo, testStrategy())
+
+	query := domain.StatementQuery{Cursor: 5, Limit: 10}
+	expectedEntries := []*domain.StatementEntry{{LedgerEntry: domain.LedgerEntry{ID: 6}}}
+	repo.EXPECT().GetStatement(ctx, int64(1), query).Return(expectedEntries, nil)
+
+	entries, err := adapter.GetStatement(ctx, 1, query)
+	require.NoError(t, err)
+	assert.Equal(t, expectedEntries, entries)
+}
+
+func TestNewHistoryRepositoryAdapter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("успешное создание", func(t *testing.T) {
+		repo := mocks.NewMockHistoryRepository(ctrl)
+		adapter, err := NewHistoryRepositoryAdapter(repo, testStrategy())
+		require.NoError(t, err)
+		assert.NotNil(t, adapter)
+	})
+
+	t.Run("ошибка при nil репозитории", func(t *testing.T) {
+		adapter, err := NewHistoryRepositoryAdapter(nil, testStrategy())
+		assert.ErrorIs(t, err, ErrHistoryRepoNil)
+		assert.Nil(t, adapter)
+	})
+}
+
+func TestHistoryRepositoryAdapter_StreamByUserID(t *testing.T) {
+	ctx := context.Background()
+	retryableErr := errors.New("connection error")
+	strategy := retry.NewStrategy(
+		[]time.Duration{10 * time.Millisecond},
+		func(err error) bool { return err == retryableErr },
+	)
+
+	t.Run("повтор до передачи первой записи", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockHistoryRepository(ctrl)
+		adapter, _ := NewHistoryRepositoryAdapter(repo, strategy)
+
+		gomock.InOrder(
+			repo.EXPECT().StreamByUserID(ctx, int64(1), nil, nil, gomock.Any()).Return(retryableErr),
+			repo.EXPECT().StreamByUserID(ctx, int64(1), nil, nil, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ int64, _, _ *time.Time, fn func(*domain.HistoryRecord) error) error {
+					return fn(&domain.HistoryRecord{Withdrawal: &domain.Withdrawal{ID: 1}})
+				}),
+		)
+
+		var ids []int64
+		err := adapter.StreamByUserID(ctx, 1, nil, nil, func(record *domain.HistoryRecord) error {
+			ids = append(ids, record.Withdrawal.ID)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, ids)
+	})
+
+	t.Run("без повтора после частичной передачи", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockHistoryRepository(ctrl)
+		adapter, _ := NewHistoryRepositoryAdapter(repo, strategy)
+
+		repo.EXPECT().StreamByUserID(ctx, int64(1), nil, nil, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ int64, _, _ *time.Time, fn func(*domain.HistoryRecord) error) error {
+				if err := fn(&domain.HistoryRecord{Withdrawal: &domain.Withdrawal{ID: 1}}); err != nil {
+					return err
+				}
+				return retryableErr
+			})
+
+		calls := 0
+		err := adapter.StreamByUserID(ctx, 1, nil, nil, func(*domain.HistoryRecord) error {
+			calls++
+			return nil
+		})
+		assert.ErrorIs(t, err, retryableErr)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestNewReconciliationRepositoryAdapter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("успешное создание", func(t *testing.T) {
+		repo := mocks.NewMockReconciliationRepository(ctrl)
+		adapter, err := NewReconciliationRepositoryAdapter(repo, testStrategy())
+		require.NoError(t, err)
+		assert.NotNil(t, adapter)
+	})
+
+	t.Run("ошибка при nil репозитории", func(t *testing.T) {
+		adapter, err := NewReconciliationRepositoryAdapter(nil, testStrategy())
+		assert.ErrorIs(t, err, ErrReconciliationRepoNil)
+		assert.Nil(t, adapter)
+	})
+}
+
+func TestReconciliationRepositoryAdapter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockReconciliationRepository(ctrl)
+	adapter, _ := NewReconciliationRepositoryAdapter(repo, testStrategy())
+
+	expectedMismatches := []*domain.BalanceMismatch{{UserID: 1}}
+	expectedEntry := &domain.LedgerEntry{ID: 2, UserID: 1}
+	repo.EXPECT().FindMismatches(ctx).Return(expectedMismatches, nil)
+	repo.EXPECT().Repair(ctx, int64(1)).Return(expectedEntry, nil)
+
+	mismatches, err := adapter.FindMismatches(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, expectedMismatches, mismatches)
+
+	entry, err := adapter.Repair(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, expectedEntry, entry)
+}
+
+func TestNewTierRepositoryAdapter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("успешное создание", func(t *testing.T) {
+		repo := mocks.NewMockTierRepository(ctrl)
+		adapter, err := NewTierRepositoryAdapter(repo, testStrategy())
+		require.NoError(t, err)
+		assert.NotNil(t, adapter)
+	})
+
+	t.Run("ошибка при nil репозитории", func(t *testing.T) {
+		adapter, err := NewTierRepositoryAdapter(nil, testStrategy())
+		assert.ErrorIs(t, err, ErrTierRepoNil)
+		assert.Nil(t, adapter)
+	})
+}
+
+func TestTierRepositoryAdapter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockTierRepository(ctrl)
+	adapter, _ := NewTierRepositoryAdapter(repo, testStrategy())
+
+	expectedTier := &domain.UserTier{UserID: 1, Tier: domain.TierGold}
+	policy := domain.TierPolicy{{Tier: domain.TierGold}}
+	repo.EXPECT().GetByUserID(ctx, int64(1)).Return(expectedTier, nil)
+	repo.EXPECT().Recompute(ctx, policy).Return(int64(3), nil)
+
+	tier, err := adapter.GetByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, expectedTier, tier)
+
+	updated, err := adapter.Recompute(ctx, policy)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), updated)
+}
+
+func TestNewWithdrawalLimitRepositoryAdapter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("успешное создание", func(t *testing.T) {
+		repo := mocks.NewMockWithdrawalLimitRepository(ctrl)
+		adapter, err := NewWithdrawalLimitRepositoryAdapter(repo, testStrategy())
+		require.NoError(t, err)
+		assert.NotNil(t, adapter)
+	})
+
+	t.Run("ошибка при nil репозитории", func(t *testing.T) {
+		adapter, err := NewWithdrawalLimitRepositoryAdapter(nil, testStrategy())
+		assert.ErrorIs(t, err, ErrWithdrawalLimitRepoNil)
+		assert.Nil(t, adapter)
+	})
+}
+
+func TestWithdrawalLimitRepositoryAdapter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockWithdrawalLimitRepository(ctrl)
+	adapter, _ := NewWithdrawalLimitRepositoryAdapter(repo, testStrategy())
+
+	daily := decimal.NewFromInt(100)
+	override := &domain.WithdrawalLimitsOverride{UserID: 1, Daily: &daily}
+	repo.EXPECT().GetByUserID(ctx, int64(1)).Return(override, nil)
+	repo.EXPECT().Set(ctx, override).Return(nil)
+	repo.EXPECT().Delete(ctx, int64(1)).Return(nil)
+
+	got, err := adapter.GetByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, override, got)
+
+	require.NoError(t, adapter.Set(ctx, override))
+	require.NoError(t, adapter.Delete(ctx, 1))
+}
+
+func TestNewIdempotencyRepositoryAdapter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("успешное создание", func(t *testing.T) {
+		repo := mocks.NewMockIdempotencyRepository(ctrl)
+		adapter, err := NewIdempotencyRepositoryAdapter(repo, testStrategy())
+		require.NoError(t, err)
+		assert.NotNil(t, adapter)
+	})
+
+	t.Run("ошибка при nil репозитории", func(t *testing.T) {
+		adapter, err := NewIdempotencyRepositoryAdapter(nil, testStrategy())
+		assert.ErrorIs(t, err, ErrIdempotencyRepoNil)
+		assert.Nil(t, adapter)
+	})
+}
+
+func TestIdempotencyRepositoryAdapter_Reserve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+	adapter, _ := NewIdempotencyRepositoryAdapter(repo, testStrategy())
+
+	expectedRecord := &domain.IdempotencyRecord{UserID: 1, Key: "key", RequestHash: "hash"}
+	repo.EXPECT().Reserve(ctx, int64(1), "key", "hash", time.Minute).Return(expectedRecord, true, nil)
+
+	record, created, err := adapter.Reserve(ctx, 1, "key", "hash", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, expectedRecord, record)
+}
+
+func TestIdempotencyRepositoryAdapter_Extend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+	adapter, _ := NewIdempotencyRepositoryAdapter(repo, testStrategy())
+
+	repo.EXPECT().Extend(ctx, int64(1), "key", time.Minute).Return(nil)
+
+	err := adapter.Extend(ctx, 1, "key", time.Minute)
+	require.NoError(t, err)
+}
+
+func TestIdempotencyRepositoryAdapter_Complete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+	adapter, _ := NewIdempotencyRepositoryAdapter(repo, testStrategy())
+
+	record := &domain.IdempotencyRecord{UserID: 1, Key: "key", StatusCode: 200}
+	repo.EXPECT().Complete(ctx, record).Return(nil)
+
+	err := adapter.Complete(ctx, record)
+	require.NoError(t, err)
+}
+
+func TestIdempotencyRepositoryAdapter_Release(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+	adapter, _ := NewIdempotencyRepositoryAdapter(repo, testStrategy())
+
+	repo.EXPECT().Release(ctx, int64(1), "key").Return(nil)
+
+	err := adapter.Release(ctx, 1, "key")
+	require.NoError(t, err)
+}
+
+func TestIdempotencyRepositoryAdapter_PurgeExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+	adapter, _ := NewIdempotencyRepositoryAdapter(repo, testStrategy())
+
+	before := time.Now()
+	repo.EXPECT().PurgeExpired(ctx, before).Return(int64(3), nil)
+
+	purged, err := adapter.PurgeExpired(ctx, before)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+}
+
+func TestNewUnitOfWorkAdapter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("успешное создание", func(t *testing.T) {
+		uow := mocks.NewMockUnitOfWork(ctrl)
+		adapter, err := NewUnitOfWorkAdapter(uow, testStrategy())
+		require.NoError(t, err)
+		assert.NotNil(t, adapter)
+	})
+
+	t.Run("ошибка при nil unit of work", func(t *testing.T) {
+		adapter, err := NewUnitOfWorkAdapter(nil, testStrategy())
+		assert.ErrorIs(t, err, ErrUnitOfWorkNil)
+		assert.Nil(t, adapter)
+	})
+}
+
+func TestUnitOfWorkAdapter_Do_RetriesWholeTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	uow := mocks.NewMockUnitOfWork(ctrl)
+
+	retryableErr := errors.New("connection error")
+	strategy := retry.NewStrategy(
+		[]time.Duration{10 * time.Millisecond},
+		func(err error) bool { return err == retryableErr },
+	)
+
+	adapter, _ := NewUnitOfWorkAdapter(uow, strategy)
+
+	gomock.InOrder(
+		uow.EXPECT().Do(ctx, gomock.Any()).Return(retryableErr),
+		uow.EXPECT().Do(ctx, gomock.Any()).Return(nil),
+	)
+
+	err := adapter.Do(ctx, func(ctx context.Context, tx ports.TxRepositories) error {
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestRetryOnError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
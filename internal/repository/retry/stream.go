@@ -0,0 +1,38 @@
+package retry
+
+import (
+	"context"
+
+	"github.com/arvaliullin/gophermart/internal/pkg/retry"
+)
+
+// streamWithRetry выполняет потоковое чтение stream со стратегией повторов.
+//
+// Повтор возможен, только пока ни одна запись не передана в fn: после
+// частичной выдачи повтор продублировал бы уже отданные записи, поэтому
+// ошибка возвращается вызывающему как есть.
+func streamWithRetry[T any](
+	ctx context.Context,
+	strategy *retry.Strategy,
+	stream func(ctx context.Context, fn func(T) error) error,
+	fn func(T) error,
+) error {
+	var streamed bool
+	var streamErr error
+
+	err := strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		err := stream(ctx, func(item T) error {
+			streamed = true
+			return fn(item)
+		})
+		if err != nil && streamed {
+			streamErr = err
+			return nil
+		}
+		return err
+	})
+	if streamErr != nil {
+		return streamErr
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/arvaliullin/gophermart/internal/pkg/retry"
+)
+
+// ErrTierRepoNil возвращается при попытке создать адаптер с nil репозиторием.
+var ErrTierRepoNil = fmt.Errorf("репозиторий уровней лояльности не задан")
+
+// TierRepositoryAdapter добавляет стратегию повторов для репозитория уровней лояльности.
+type TierRepositoryAdapter struct {
+	repo     ports.TierRepository
+	strategy *retry.Strategy
+}
+
+// NewTierRepositoryAdapter создаёт адаптер репозитория уровней лояльности с поддержкой retry.
+func NewTierRepositoryAdapter(repo ports.TierRepository, strategy *retry.Strategy) (*TierRepositoryAdapter, error) {
+	if repo == nil {
+		return nil, ErrTierRepoNil
+	}
+
+	return &TierRepositoryAdapter{
+		repo:     repo,
+		strategy: strategy,
+	}, nil
+}
+
+// GetByUserID возвращает уровень пользователя.
+func (a *TierRepositoryAdapter) GetByUserID(ctx context.Context, userID int64) (*domain.UserTier, error) {
+	var tier *domain.UserTier
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		tier, err = a.repo.GetByUserID(ctx, userID)
+		return err
+	})
+	return tier, err
+}
+
+// Recompute пересчитывает уровни всех пользователей.
+func (a *TierRepositoryAdapter) Recompute(ctx context.Context, policy domain.TierPolicy) (int64, error) {
+	var updated int64
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = a.repo.Recompute(ctx, policy)
+		return err
+	})
+	return updated, err
+}
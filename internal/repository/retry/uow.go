@@ -0,0 +1,40 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/arvaliullin/gophermart/internal/pkg/retry"
+)
+
+// ErrUnitOfWorkNil возвращается при попытке создать адаптер с nil UnitOfWork.
+var ErrUnitOfWorkNil = fmt.Errorf("unit of work не задан")
+
+// UnitOfWorkAdapter добавляет стратегию повторов для UnitOfWork.
+//
+// При сбое соединения транзакция откатывается целиком, поэтому fn
+// безопасно выполняется повторно с начала.
+type UnitOfWorkAdapter struct {
+	uow      ports.UnitOfWork
+	strategy *retry.Strategy
+}
+
+// NewUnitOfWorkAdapter создаёт адаптер UnitOfWork с поддержкой retry.
+func NewUnitOfWorkAdapter(uow ports.UnitOfWork, strategy *retry.Strategy) (*UnitOfWorkAdapter, error) {
+	if uow == nil {
+		return nil, ErrUnitOfWorkNil
+	}
+
+	return &UnitOfWorkAdapter{
+		uow:      uow,
+		strategy: strategy,
+	}, nil
+}
+
+// Do выполняет fn в транзакции, повторяя её целиком при сбоях соединения.
+func (a *UnitOfWorkAdapter) Do(ctx context.Context, fn func(ctx context.Context, tx ports.TxRepositories) error) error {
+	return a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		return a.uow.Do(ctx, fn)
+	})
+}
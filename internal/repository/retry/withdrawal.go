@@ -40,3 +40,25 @@ func (a *WithdrawalRepositoryAdapter) GetByUserID(ctx context.Context, userID in
 	})
 	return withdrawals, err
 }
+
+// GetByOrderNumber возвращает списания пользователя по номеру заказа.
+func (a *WithdrawalRepositoryAdapter) GetByOrderNumber(ctx context.Context, userID int64, orderNumber string) ([]*domain.Withdrawal, error) {
+	var withdrawals []*domain.Withdrawal
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		withdrawals, err = a.repo.GetByOrderNumber(ctx, userID, orderNumber)
+		return err
+	})
+	return withdrawals, err
+}
+
+// List возвращает страницу списаний пользователя.
+func (a *WithdrawalRepositoryAdapter) List(ctx context.Context, userID int64, query domain.ListQuery) ([]*domain.Withdrawal, error) {
+	var withdrawals []*domain.Withdrawal
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		withdrawals, err = a.repo.List(ctx, userID, query)
+		return err
+	})
+	return withdrawals, err
+}
@@ -0,0 +1,56 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arvaliullin/gophermart/internal/core/domain"
+	"github.com/arvaliullin/gophermart/internal/core/ports"
+	"github.com/arvaliullin/gophermart/internal/pkg/retry"
+)
+
+// ErrWithdrawalLimitRepoNil возвращается при попытке создать адаптер с nil репозиторием.
+var ErrWithdrawalLimitRepoNil = fmt.Errorf("репозиторий лимитов списаний не задан")
+
+// WithdrawalLimitRepositoryAdapter добавляет стратегию повторов для репозитория лимитов списаний.
+type WithdrawalLimitRepositoryAdapter struct {
+	repo     ports.WithdrawalLimitRepository
+	strategy *retry.Strategy
+}
+
+// NewWithdrawalLimitRepositoryAdapter создаёт адаптер репозитория лимитов списаний с поддержкой retry.
+func NewWithdrawalLimitRepositoryAdapter(repo ports.WithdrawalLimitRepository, strategy *retry.Strategy) (*WithdrawalLimitRepositoryAdapter, error) {
+	if repo == nil {
+		return nil, ErrWithdrawalLimitRepoNil
+	}
+
+	return &WithdrawalLimitRepositoryAdapter{
+		repo:     repo,
+		strategy: strategy,
+	}, nil
+}
+
+// GetByUserID возвращает индивидуальные лимиты пользователя.
+func (a *WithdrawalLimitRepositoryAdapter) GetByUserID(ctx context.Context, userID int64) (*domain.WithdrawalLimitsOverride, error) {
+	var override *domain.WithdrawalLimitsOverride
+	err := a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		override, err = a.repo.GetByUserID(ctx, userID)
+		return err
+	})
+	return override, err
+}
+
+// Set назначает пользователю индивидуальные лимиты.
+func (a *WithdrawalLimitRepositoryAdapter) Set(ctx context.Context, override *domain.WithdrawalLimitsOverride) error {
+	return a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		return a.repo.Set(ctx, override)
+	})
+}
+
+// Delete снимает индивидуальные лимиты пользователя.
+func (a *WithdrawalLimitRepositoryAdapter) Delete(ctx context.Context, userID int64) error {
+	return a.strategy.DoWithRetry(ctx, func(ctx context.Context) error {
+		return a.repo.Delete(ctx, userID)
+	})
+}
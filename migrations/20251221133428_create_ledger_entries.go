@@ -0,0 +1,73 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateLedgerEntries, downCreateLedgerEntries)
+}
+
+func upCreateLedgerEntries(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS ledger_entries (
+			id           BIGSERIAL PRIMARY KEY,
+			user_id      BIGINT NOT NULL REFERENCES users(id),
+			kind         VARCHAR(50) NOT NULL,
+			amount       DECIMAL(15, 2) NOT NULL,
+			order_number VARCHAR(255),
+			description  TEXT NOT NULL DEFAULT '',
+			created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_ledger_entries_user_id ON ledger_entries(user_id);
+
+		INSERT INTO ledger_entries (user_id, kind, amount, order_number, description, created_at)
+		SELECT user_id, kind, amount, order_number, description, created_at
+		FROM (
+			SELECT user_id, 'ACCRUAL' AS kind, accrual AS amount, number AS order_number,
+				'перенос начисления по заказу' AS description, uploaded_at AS created_at
+			FROM orders
+			WHERE status = 'PROCESSED' AND accrual > 0
+			UNION ALL
+			SELECT user_id, 'WITHDRAWAL', -sum, order_number,
+				'перенос списания', processed_at
+			FROM withdrawals
+		) AS history
+		ORDER BY created_at;
+
+		INSERT INTO ledger_entries (user_id, kind, amount, description)
+		SELECT b.user_id, 'ADJUSTMENT', b.current - COALESCE(l.total, 0),
+			'выравнивание остатка при переходе на журнал проводок'
+		FROM balances b
+		LEFT JOIN (
+			SELECT user_id, SUM(amount) AS total
+			FROM ledger_entries
+			GROUP BY user_id
+		) l ON l.user_id = b.user_id
+		WHERE b.current <> COALESCE(l.total, 0)
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+func downCreateLedgerEntries(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		UPDATE balances b
+		SET current = l.current, withdrawn = l.withdrawn
+		FROM (
+			SELECT user_id,
+				SUM(amount) AS current,
+				COALESCE(-SUM(amount) FILTER (WHERE kind = 'WITHDRAWAL'), 0) AS withdrawn
+			FROM ledger_entries
+			GROUP BY user_id
+		) l
+		WHERE l.user_id = b.user_id;
+
+		DROP TABLE IF EXISTS ledger_entries
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
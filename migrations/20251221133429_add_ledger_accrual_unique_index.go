@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddLedgerAccrualUniqueIndex, downAddLedgerAccrualUniqueIndex)
+}
+
+func upAddLedgerAccrualUniqueIndex(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_entries_accrual_order
+		ON ledger_entries(order_number)
+		WHERE kind = 'ACCRUAL'
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+func downAddLedgerAccrualUniqueIndex(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP INDEX IF EXISTS idx_ledger_entries_accrual_order`)
+	return err
+}
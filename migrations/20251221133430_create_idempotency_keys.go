@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateIdempotencyKeys, downCreateIdempotencyKeys)
+}
+
+// Незавершённый ключ занят до locked_until: если процесс упал, не успев
+// сохранить ответ, ключ после этого момента может занять повторный запрос.
+func upCreateIdempotencyKeys(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS idempotency_keys (
+			user_id       BIGINT NOT NULL REFERENCES users(id),
+			key           VARCHAR(255) NOT NULL,
+			request_hash  VARCHAR(64) NOT NULL,
+			status_code   INTEGER,
+			content_type  VARCHAR(255) NOT NULL DEFAULT '',
+			response_body BYTEA,
+			created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			locked_until  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (user_id, key)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at)
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+func downCreateIdempotencyKeys(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS idempotency_keys`)
+	return err
+}
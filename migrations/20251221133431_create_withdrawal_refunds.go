@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateWithdrawalRefunds, downCreateWithdrawalRefunds)
+}
+
+func upCreateWithdrawalRefunds(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS withdrawal_refunds (
+			id            BIGSERIAL PRIMARY KEY,
+			withdrawal_id BIGINT NOT NULL REFERENCES withdrawals(id),
+			user_id       BIGINT NOT NULL REFERENCES users(id),
+			sum           DECIMAL(15, 2) NOT NULL CHECK (sum > 0),
+			processed_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_withdrawal_refunds_withdrawal_id ON withdrawal_refunds(withdrawal_id)
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+func downCreateWithdrawalRefunds(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS withdrawal_refunds`)
+	return err
+}
@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreatePointHolds, downCreatePointHolds)
+}
+
+func upCreatePointHolds(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS point_holds (
+			id           BIGSERIAL PRIMARY KEY,
+			user_id      BIGINT NOT NULL REFERENCES users(id),
+			order_number VARCHAR(255) NOT NULL,
+			amount       DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+			status       VARCHAR(50) NOT NULL DEFAULT 'ACTIVE',
+			created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			expires_at   TIMESTAMPTZ NOT NULL,
+			resolved_at  TIMESTAMPTZ
+		);
+		CREATE INDEX IF NOT EXISTS idx_point_holds_user_id ON point_holds(user_id);
+		CREATE INDEX IF NOT EXISTS idx_point_holds_active_expires_at ON point_holds(expires_at)
+			WHERE status = 'ACTIVE'
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+func downCreatePointHolds(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS point_holds`)
+	return err
+}
@@ -0,0 +1,86 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreatePointLots, downCreatePointLots)
+}
+
+// Баллы, накопленные до появления партий, переносятся в партии по истории
+// журнала. Каждое начисление по обработанному заказу становится партией со
+// сроком действия от загрузки заказа на срок из WithPointsTTL, остальные
+// поступления — бессрочными партиями. Все списания затем погашаются из
+// партий в том же порядке, что и при работе сервиса: сначала партии с
+// ближайшим сроком сгорания. Остатки с уже истёкшим сроком сгорят при
+// ближайшем запуске сгорания баллов.
+func upCreatePointLots(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS point_lots (
+			id              BIGSERIAL PRIMARY KEY,
+			user_id         BIGINT NOT NULL REFERENCES users(id),
+			ledger_entry_id BIGINT REFERENCES ledger_entries(id),
+			amount          DECIMAL(15, 2) NOT NULL,
+			remaining       DECIMAL(15, 2) NOT NULL CHECK (remaining >= 0),
+			expires_at      TIMESTAMPTZ,
+			expired_at      TIMESTAMPTZ,
+			created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_point_lots_user_id ON point_lots(user_id)
+			WHERE remaining > 0;
+		CREATE INDEX IF NOT EXISTS idx_point_lots_expires_at ON point_lots(expires_at)
+			WHERE remaining > 0
+	`
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	credit := `
+		INSERT INTO point_lots (user_id, ledger_entry_id, amount, remaining, expires_at, created_at)
+		SELECT le.user_id, le.id, le.amount, le.amount,
+			CASE WHEN $1 > 0 AND o.uploaded_at IS NOT NULL
+				THEN o.uploaded_at + make_interval(months => $1)
+			END,
+			le.created_at
+		FROM ledger_entries le
+		LEFT JOIN orders o
+			ON le.kind = 'ACCRUAL' AND o.number = le.order_number AND o.user_id = le.user_id
+		WHERE le.amount > 0
+		ORDER BY le.created_at, le.id
+	`
+	if _, err := tx.ExecContext(ctx, credit, pointsTTL(ctx)); err != nil {
+		return err
+	}
+
+	consume := `
+		WITH lots AS (
+			SELECT id, user_id, amount,
+				SUM(amount) OVER (
+					PARTITION BY user_id
+					ORDER BY expires_at ASC NULLS LAST, id ASC
+				) - amount AS consumed_before
+			FROM point_lots
+		), debits AS (
+			SELECT user_id, -SUM(amount) AS total
+			FROM ledger_entries
+			WHERE amount < 0
+			GROUP BY user_id
+		)
+		UPDATE point_lots p
+		SET remaining = GREATEST(lots.amount - GREATEST(debits.total - lots.consumed_before, 0), 0)
+		FROM lots
+		JOIN debits ON debits.user_id = lots.user_id
+		WHERE p.id = lots.id
+	`
+	_, err := tx.ExecContext(ctx, consume)
+	return err
+}
+
+func downCreatePointLots(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS point_lots`)
+	return err
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateTransfers, downCreateTransfers)
+}
+
+func upCreateTransfers(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS transfers (
+			id           BIGSERIAL PRIMARY KEY,
+			from_user_id BIGINT NOT NULL REFERENCES users(id),
+			to_user_id   BIGINT NOT NULL REFERENCES users(id),
+			amount       DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+			created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			CHECK (from_user_id <> to_user_id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_transfers_from_user_id_created_at ON transfers(from_user_id, created_at)
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+func downCreateTransfers(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS transfers`)
+	return err
+}
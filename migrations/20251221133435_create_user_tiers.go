@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateUserTiers, downCreateUserTiers)
+}
+
+func upCreateUserTiers(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS user_tiers (
+			user_id          BIGINT PRIMARY KEY REFERENCES users(id),
+			tier             VARCHAR(50) NOT NULL,
+			lifetime_accrued DECIMAL(15, 2) NOT NULL DEFAULT 0,
+			updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+func downCreateUserTiers(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS user_tiers`)
+	return err
+}
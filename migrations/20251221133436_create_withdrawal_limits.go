@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateWithdrawalLimits, downCreateWithdrawalLimits)
+}
+
+func upCreateWithdrawalLimits(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS withdrawal_limits (
+			user_id         BIGINT PRIMARY KEY REFERENCES users(id),
+			per_transaction DECIMAL(15, 2) CHECK (per_transaction >= 0),
+			daily           DECIMAL(15, 2) CHECK (daily >= 0),
+			monthly         DECIMAL(15, 2) CHECK (monthly >= 0),
+			updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+func downCreateWithdrawalLimits(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS withdrawal_limits`)
+	return err
+}
@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateOrderStatusHistory, downCreateOrderStatusHistory)
+}
+
+// Для уже загруженных заказов время переходов неизвестно: история начинается
+// со статуса NEW в момент загрузки, текущий статус фиксируется тем же временем,
+// чтобы не выдавать момент миграции за момент перехода.
+func upCreateOrderStatusHistory(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS order_status_history (
+			id         BIGSERIAL PRIMARY KEY,
+			order_id   BIGINT NOT NULL REFERENCES orders(id),
+			status     VARCHAR(50) NOT NULL,
+			accrual    DECIMAL(15, 2),
+			changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id
+			ON order_status_history(order_id, changed_at);
+
+		INSERT INTO order_status_history (order_id, status, changed_at)
+		SELECT id, 'NEW', uploaded_at FROM orders;
+
+		INSERT INTO order_status_history (order_id, status, accrual, changed_at)
+		SELECT id, status, accrual, uploaded_at FROM orders WHERE status <> 'NEW'
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+func downCreateOrderStatusHistory(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS order_status_history`)
+	return err
+}
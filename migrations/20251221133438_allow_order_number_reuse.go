@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAllowOrderNumberReuse, downAllowOrderNumberReuse)
+}
+
+// Номер отменённого заказа освобождается: уникальность номера
+// обеспечивается только среди неотменённых заказов.
+func upAllowOrderNumberReuse(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_number_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_number_active
+			ON orders(number) WHERE status <> 'CANCELED';
+		CREATE INDEX IF NOT EXISTS idx_orders_number ON orders(number)
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+// Пока номера могли повторяться, у одного номера накапливаются отменённые
+// заказы. Перед возвратом уникальности для каждого номера остаётся один
+// заказ: неотменённый, а если таких нет, — загруженный последним. Остальные
+// отменённые заказы удаляются вместе с историей статусов, поэтому откат
+// необратимо теряет их.
+func downAllowOrderNumberReuse(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		CREATE TEMPORARY TABLE orders_number_duplicates ON COMMIT DROP AS
+		SELECT id FROM (
+			SELECT id, ROW_NUMBER() OVER (
+				PARTITION BY number
+				ORDER BY status = 'CANCELED', uploaded_at DESC, id DESC
+			) AS rn
+			FROM orders
+		) ranked
+		WHERE rn > 1;
+
+		DELETE FROM order_status_history
+		WHERE order_id IN (SELECT id FROM orders_number_duplicates);
+		DELETE FROM orders
+		WHERE id IN (SELECT id FROM orders_number_duplicates);
+
+		DROP INDEX IF EXISTS idx_orders_number;
+		DROP INDEX IF EXISTS idx_orders_number_active;
+		ALTER TABLE orders ADD CONSTRAINT orders_number_key UNIQUE (number)
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddOrderLeases, downAddOrderLeases)
+}
+
+// Необработанный заказ арендуется экземпляром сервиса до locked_until,
+// чтобы несколько реплик не опрашивали систему начислений по одному заказу.
+func upAddOrderLeases(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		ALTER TABLE orders ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
+		CREATE INDEX IF NOT EXISTS idx_orders_pending
+			ON orders(uploaded_at, id) WHERE status IN ('NEW', 'PROCESSING')
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+func downAddOrderLeases(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		DROP INDEX IF EXISTS idx_orders_pending;
+		ALTER TABLE orders DROP COLUMN IF EXISTS locked_until
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddOrderCheckSchedule, downAddOrderCheckSchedule)
+}
+
+// Необработанный заказ запрашивается в системе начислений не раньше
+// next_check_at; attempts считает уже выполненные проверки. Выборка
+// необработанных заказов идёт по next_check_at, поэтому индекс по времени
+// загрузки больше не нужен.
+func upAddOrderCheckSchedule(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		ALTER TABLE orders
+			ADD COLUMN IF NOT EXISTS next_check_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
+		CREATE INDEX IF NOT EXISTS idx_orders_next_check
+			ON orders(next_check_at, id) WHERE status IN ('NEW', 'PROCESSING');
+		DROP INDEX IF EXISTS idx_orders_pending
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+func downAddOrderCheckSchedule(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		CREATE INDEX IF NOT EXISTS idx_orders_pending
+			ON orders(uploaded_at, id) WHERE status IN ('NEW', 'PROCESSING');
+		DROP INDEX IF EXISTS idx_orders_next_check;
+		ALTER TABLE orders
+			DROP COLUMN IF EXISTS attempts,
+			DROP COLUMN IF EXISTS next_check_at
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddOrderDeadLetter, downAddOrderDeadLetter)
+}
+
+// Заказ, который система начислений так и не обработала, снимается
+// с опроса: dead_lettered_at фиксирует момент, last_error — причину
+// последней неудачной проверки.
+func upAddOrderDeadLetter(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		ALTER TABLE orders
+			ADD COLUMN IF NOT EXISTS last_error TEXT,
+			ADD COLUMN IF NOT EXISTS dead_lettered_at TIMESTAMPTZ;
+		CREATE INDEX IF NOT EXISTS idx_orders_dead_lettered_at
+			ON orders(dead_lettered_at, id) WHERE dead_lettered_at IS NOT NULL
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+func downAddOrderDeadLetter(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		DROP INDEX IF EXISTS idx_orders_dead_lettered_at;
+		ALTER TABLE orders
+			DROP COLUMN IF EXISTS dead_lettered_at,
+			DROP COLUMN IF EXISTS last_error
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
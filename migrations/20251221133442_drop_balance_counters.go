@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upDropBalanceCounters, downDropBalanceCounters)
+}
+
+// Счётчики balances.current и withdrawn удаляются отдельно от переноса
+// истории в журнал проводок, чтобы перенос можно было сверить со старыми
+// значениями и откатить.
+func upDropBalanceCounters(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		ALTER TABLE balances DROP COLUMN IF EXISTS current;
+		ALTER TABLE balances DROP COLUMN IF EXISTS withdrawn
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+func downDropBalanceCounters(ctx context.Context, tx *sql.Tx) error {
+	query := `
+		ALTER TABLE balances ADD COLUMN IF NOT EXISTS current DECIMAL(15, 2) NOT NULL DEFAULT 0;
+		ALTER TABLE balances ADD COLUMN IF NOT EXISTS withdrawn DECIMAL(15, 2) NOT NULL DEFAULT 0;
+
+		UPDATE balances b
+		SET current = l.current, withdrawn = l.withdrawn
+		FROM (
+			SELECT user_id,
+				SUM(amount) AS current,
+				COALESCE(-SUM(amount) FILTER (WHERE kind = 'WITHDRAWAL'), 0) AS withdrawn
+			FROM ledger_entries
+			GROUP BY user_id
+		) l
+		WHERE l.user_id = b.user_id
+	`
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
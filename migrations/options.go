@@ -0,0 +1,19 @@
+package migrations
+
+import "context"
+
+type pointsTTLKey struct{}
+
+// WithPointsTTL возвращает контекст применения миграций, в котором баллы
+// действуют months месяцев, как в настройке POINTS_TTL_MONTHS. По нему
+// миграции задают срок действия партий, перенесённых из истории начислений.
+// Без этой настройки перенесённые баллы не сгорают.
+func WithPointsTTL(ctx context.Context, months int) context.Context {
+	return context.WithValue(ctx, pointsTTLKey{}, months)
+}
+
+// pointsTTL возвращает срок действия баллов в месяцах из контекста миграций.
+func pointsTTL(ctx context.Context) int {
+	months, _ := ctx.Value(pointsTTLKey{}).(int)
+	return max(months, 0)
+}